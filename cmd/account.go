@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/auth"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	accountCmd := &cobra.Command{
+		Use:     "account",
+		Aliases: []string{"accounts"},
+		Short:   "Manages the Minecraft accounts used to launch instances",
+		Long: `Manages the Minecraft accounts used to launch instances.
+The default account is used unless "minepkg launch --account <name>" is used
+or the instance sets one in its .minepkg-local.toml:
+
+  account = "alt"`,
+	}
+
+	addRunner := &accountAddRunner{}
+	addCmd := commands.New(&cobra.Command{
+		Use:   "add <name>",
+		Short: "Adds an account and signs in with it",
+		Args:  cobra.ExactArgs(1),
+	}, addRunner)
+	addCmd.Flags().BoolVar(&addRunner.makeDefault, "default", false, "Make this the default account")
+	addCmd.Flags().BoolVar(&addRunner.device, "device", false, "Sign in with a code on another device instead of opening a browser (for SSH sessions)")
+
+	listCmd := commands.New(&cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Lists all accounts",
+		Args:    cobra.ExactArgs(0),
+	}, &accountListRunner{})
+
+	removeCmd := commands.New(&cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Removes an account and its stored credentials",
+		Args:    cobra.ExactArgs(1),
+	}, &accountRemoveRunner{})
+
+	useRunner := &accountUseRunner{}
+	useCmd := commands.New(&cobra.Command{
+		Use:   "use <name>",
+		Short: "Makes an account the default account",
+		Args:  cobra.ExactArgs(1),
+	}, useRunner)
+	useCmd.Flags().BoolVar(&useRunner.local, "local", false, "Only use this account for the instance in the current directory")
+
+	accountCmd.AddCommand(addCmd.Command, listCmd.Command, removeCmd.Command, useCmd.Command)
+	rootCmd.AddCommand(accountCmd)
+}
+
+// loadAccounts reads all accounts
+func (r *Root) loadAccounts() (*auth.Accounts, error) {
+	if err := r.useAccount(""); err != nil {
+		return nil, err
+	}
+	return r.accounts, nil
+}
+
+// accountError turns account errors into helpful cli errors
+func accountError(name string, err error) error {
+	switch {
+	case errors.Is(err, auth.ErrAccountNotFound):
+		return &commands.CliError{
+			Text: fmt.Sprintf("there is no account named %s", name),
+			Suggestions: []string{
+				fmt.Sprintf("Run %s to list all accounts", gchalk.Bold("minepkg account list")),
+			},
+		}
+	case errors.Is(err, auth.ErrAccountExists):
+		return &commands.CliError{
+			Text: fmt.Sprintf("there already is an account named %s", name),
+			Suggestions: []string{
+				fmt.Sprintf("Remove it first with %s", gchalk.Bold("minepkg account remove "+name)),
+			},
+		}
+	case errors.Is(err, auth.ErrInvalidAccountName):
+		return &commands.CliError{
+			Text:        fmt.Sprintf("%s can not be used as account name", name),
+			Suggestions: []string{"Use letters, numbers, - and _ only"},
+		}
+	}
+	return err
+}
+
+type accountAddRunner struct {
+	makeDefault bool
+	device      bool
+}
+
+func (a *accountAddRunner) RunE(cmd *cobra.Command, args []string) error {
+	accounts, err := root.loadAccounts()
+	if err != nil {
+		return err
+	}
+
+	account, err := accounts.Add(args[0])
+	if err != nil {
+		return accountError(args[0], err)
+	}
+	if a.makeDefault {
+		accounts.Default = account.Name
+	}
+
+	// login saves the account once it succeeded
+	root.account = account
+	root.minecraftAuthStore = accounts.Store(account)
+	root.authProvider = nil
+	login := root.login
+	if a.device {
+		login = root.deviceLogin
+	}
+	if err := login(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added account %s (%s)\n", account.Name, account.PlayerName)
+	if accounts.Default == account.Name {
+		fmt.Println("It is your default account now")
+	}
+	return nil
+}
+
+type accountListRunner struct{}
+
+func (a *accountListRunner) RunE(cmd *cobra.Command, args []string) error {
+	accounts, err := root.loadAccounts()
+	if err != nil {
+		return err
+	}
+
+	if len(accounts.Accounts) == 0 {
+		fmt.Printf("No accounts yet. Add one with %s\n", gchalk.Bold("minepkg account add <name>"))
+		return nil
+	}
+
+	for _, account := range accounts.Accounts {
+		marker := " "
+		if account.Name == accounts.Default {
+			marker = "*"
+		}
+		player := account.PlayerName
+		if player == "" {
+			player = "(unknown player)"
+		}
+		fmt.Printf("%s %-16s %-16s %s\n", marker, account.Name, player, gchalk.Gray(account.Provider))
+	}
+	return nil
+}
+
+type accountRemoveRunner struct{}
+
+func (a *accountRemoveRunner) RunE(cmd *cobra.Command, args []string) error {
+	accounts, err := root.loadAccounts()
+	if err != nil {
+		return err
+	}
+
+	if err := accounts.Remove(args[0]); err != nil {
+		return accountError(args[0], err)
+	}
+	if err := accounts.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed account %s\n", args[0])
+	if accounts.Default != "" {
+		fmt.Printf("Default account is %s\n", accounts.Default)
+	}
+	return nil
+}
+
+type accountUseRunner struct {
+	local bool
+}
+
+func (a *accountUseRunner) RunE(cmd *cobra.Command, args []string) error {
+	accounts, err := root.loadAccounts()
+	if err != nil {
+		return err
+	}
+	if _, err := accounts.Get(args[0]); err != nil {
+		return accountError(args[0], err)
+	}
+
+	if a.local {
+		instance, err := root.LocalInstance()
+		if err != nil {
+			return err
+		}
+		settings, err := instance.LocalSettings()
+		if err != nil {
+			return err
+		}
+		settings.Account = args[0]
+		if err := instance.SaveLocalSettings(settings); err != nil {
+			return err
+		}
+		fmt.Printf("This instance now launches with account %s\n", args[0])
+		return nil
+	}
+
+	if err := accounts.Use(args[0]); err != nil {
+		return accountError(args[0], err)
+	}
+	if err := accounts.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Default account is %s now\n", args[0])
+	return nil
+}
@@ -0,0 +1,77 @@
+package backupCmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/rcon"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Manage world backups of this instance",
+		Long: `Backs up the singleplayer saves and the server world of the instance in the current directory.
+Backups are saved in the "backups" directory of the instance.
+Defaults can be set in the [backup] section of the .minepkg-local.toml:
+
+  [backup]
+  format = "tar.zst" # or "zip"
+  every = "6h"       # scheduled backups while the server runs
+  keepLast = 3
+  keepHourly = 24
+  keepDaily = 7`,
+	}
+
+	cmd.AddCommand(newCreate())
+	cmd.AddCommand(newList())
+	cmd.AddCommand(newRestore())
+	cmd.AddCommand(newPrune())
+
+	return cmd
+}
+
+// dialRunningServer returns a rcon client if the server of the instance is running.
+// Returns nil if the server is not running or rcon is disabled
+func dialRunningServer(instance *instances.Instance) (*rcon.Client, error) {
+	client, err := instance.DialRcon(5 * time.Second)
+	if errors.Is(err, rcon.ErrAuthFailed) {
+		return nil, &commands.CliError{
+			Text:        "the running server rejected the RCON password",
+			Suggestions: []string{"Restart the server, it might still use an old rcon.password"},
+		}
+	}
+	if err != nil {
+		return nil, nil
+	}
+	return client, nil
+}
+
+// runningClient returns true if a Minecraft client of the instance is running in the background or in another terminal
+func runningClient(instance *instances.Instance) bool {
+	info, err := instance.RunInfo()
+	if err != nil {
+		info, err = instance.ForegroundRunInfo()
+	}
+	return err == nil && !info.Server
+}
+
+// getBackup returns the backup with the given name or a helpful error
+func getBackup(store *backup.Store, name string) (*backup.Backup, error) {
+	b, err := store.Get(name)
+	if errors.Is(err, backup.ErrNotFound) {
+		return nil, &commands.CliError{
+			Text: fmt.Sprintf("backup %s does not exist", name),
+			Suggestions: []string{
+				fmt.Sprintf("Run %s to see all backups", gchalk.Bold("minepkg backup list")),
+			},
+		}
+	}
+	return b, err
+}
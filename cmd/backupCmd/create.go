@@ -0,0 +1,91 @@
+package backupCmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func newCreate() *cobra.Command {
+	runner := &createRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "create",
+		Short: "Backs up all worlds of this instance",
+		Long: `Backs up all worlds of this instance.
+If the server is running, auto saving is paused during the backup (using RCON).
+Old backups are pruned afterwards if a retention policy is set in the [backup] settings.`,
+		Args: cobra.NoArgs,
+	}, runner)
+
+	cmd.Flags().StringVar(&runner.format, "format", "", "Archive format: zip or tar.zst (defaults to the backup.format setting or zip)")
+	cmd.Flags().BoolVar(&runner.noPrune, "no-prune", false, "Do not remove old backups afterwards")
+
+	return cmd.Command
+}
+
+type createRunner struct {
+	format  string
+	noPrune bool
+}
+
+func (c *createRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+	settings, err := instance.BackupSettings()
+	if err != nil {
+		return err
+	}
+
+	formatName := c.format
+	if formatName == "" {
+		formatName = settings.Format
+	}
+	format, err := backup.ParseFormat(formatName)
+	if err != nil {
+		return &commands.CliError{Text: err.Error()}
+	}
+
+	var server backup.Commander
+	client, err := dialRunningServer(instance)
+	if err != nil {
+		return err
+	}
+	if client != nil {
+		defer client.Close()
+		fmt.Println("Server is running, pausing auto save during the backup")
+		server = client
+	} else if runningClient(instance) {
+		fmt.Println(gchalk.Yellow("Minecraft is running. Worlds that are open might not be backed up consistently"))
+	}
+
+	created, err := instance.CreateBackup(server, format)
+	if errors.Is(err, backup.ErrNothingToBackup) {
+		return &commands.CliError{
+			Text:        "this instance has no worlds to backup",
+			Suggestions: []string{"Launch it and create a world first"},
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created backup %s (%s)\n", gchalk.Bold(created.Name), commands.HumanSize(created.Size))
+
+	if c.noPrune {
+		return nil
+	}
+	pruned, err := instance.BackupStore().Prune(settings.Policy())
+	if err != nil {
+		return err
+	}
+	for _, b := range pruned {
+		fmt.Println("Removed old backup " + b.Name)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package backupCmd
+
+import (
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func newList() *cobra.Command {
+	cmd := commands.New(&cobra.Command{
+		Use:     "list",
+		Short:   "Lists the backups of this instance",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}, &listRunner{})
+
+	return cmd.Command
+}
+
+type listRunner struct{}
+
+func (l *listRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	backups, err := instance.BackupStore().List()
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Println(gchalk.Gray("No backups yet. Create one with ") + gchalk.Bold("minepkg backup create"))
+		return nil
+	}
+
+	for _, b := range backups {
+		fmt.Printf(
+			"  %-24s %-20s %-8s %10s\n",
+			b.Name,
+			b.Time.Format("2006-01-02 15:04:05"),
+			b.Format,
+			commands.HumanSize(b.Size),
+		)
+	}
+	return nil
+}
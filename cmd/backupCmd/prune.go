@@ -0,0 +1,85 @@
+package backupCmd
+
+import (
+	"fmt"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func newPrune() *cobra.Command {
+	runner := &pruneRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "prune",
+		Short: "Removes old backups",
+		Long: `Removes all backups that are not kept by the retention policy.
+The policy defaults to the keepLast, keepHourly and keepDaily settings in the [backup] section.`,
+		Args: cobra.NoArgs,
+	}, runner)
+
+	cmd.Flags().IntVar(&runner.keepLast, "keep-last", 0, "Keep the newest n backups")
+	cmd.Flags().IntVar(&runner.keepHourly, "keep-hourly", 0, "Keep one backup for each of the last n hours")
+	cmd.Flags().IntVar(&runner.keepDaily, "keep-daily", 0, "Keep one backup for each of the last n days")
+	cmd.Flags().BoolVar(&runner.dryRun, "dry-run", false, "Only print the backups that would be removed")
+
+	return cmd.Command
+}
+
+type pruneRunner struct {
+	keepLast   int
+	keepHourly int
+	keepDaily  int
+	dryRun     bool
+}
+
+func (p *pruneRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+	settings, err := instance.BackupSettings()
+	if err != nil {
+		return err
+	}
+
+	policy := settings.Policy()
+	if cmd.Flags().Changed("keep-last") || cmd.Flags().Changed("keep-hourly") || cmd.Flags().Changed("keep-daily") {
+		policy.KeepLast = p.keepLast
+		policy.KeepHourly = p.keepHourly
+		policy.KeepDaily = p.keepDaily
+	}
+	if policy.IsZero() {
+		return &commands.CliError{
+			Text: "no retention policy set, refusing to remove all backups",
+			Suggestions: []string{
+				"Pass --keep-last, --keep-hourly or --keep-daily",
+				"Set keepLast, keepHourly or keepDaily in the [backup] section of the .minepkg-local.toml",
+			},
+		}
+	}
+
+	store := instance.BackupStore()
+	if p.dryRun {
+		backups, err := store.List()
+		if err != nil {
+			return err
+		}
+		for _, b := range policy.Expired(backups) {
+			fmt.Println("Would remove " + b.Name)
+		}
+		return nil
+	}
+
+	pruned, err := store.Prune(policy)
+	if err != nil {
+		return err
+	}
+	if len(pruned) == 0 {
+		fmt.Println("Nothing to remove")
+	}
+	for _, b := range pruned {
+		fmt.Println("Removed " + b.Name)
+	}
+	return nil
+}
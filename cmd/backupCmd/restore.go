@@ -0,0 +1,92 @@
+package backupCmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/erikgeiser/promptkit/confirmation"
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newRestore() *cobra.Command {
+	runner := &restoreRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "restore <name|latest>",
+		Short: "Replaces the worlds with the ones in a backup",
+		Long: `Replaces the worlds with the ones in a backup.
+The current worlds are backed up before they are replaced. Minecraft has to be stopped.`,
+		Args: cobra.ExactArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.yes, "yes", "y", false, "Do not ask for confirmation")
+
+	return cmd.Command
+}
+
+type restoreRunner struct {
+	yes bool
+}
+
+func (r *restoreRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+	b, err := getBackup(instance.BackupStore(), args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := dialRunningServer(instance)
+	if err != nil {
+		return err
+	}
+	_, runErr := instance.RunInfo()
+	_, foregroundErr := instance.ForegroundRunInfo()
+	if client != nil || runErr == nil || foregroundErr == nil {
+		if client != nil {
+			client.Close()
+		}
+		return &commands.CliError{
+			Text:        "can not restore a backup while Minecraft is running",
+			Suggestions: []string{fmt.Sprintf("Stop it first, for example with %s", gchalk.Bold("minepkg stop"))},
+		}
+	}
+
+	if !r.yes && !viper.GetBool("nonInteractive") {
+		input := confirmation.New(fmt.Sprintf("Replace the worlds with backup %s?", b.Name), confirmation.No)
+		ok, err := input.RunPrompt()
+		if err != nil || !ok {
+			fmt.Println("Aborting")
+			return nil
+		}
+	}
+
+	settings, err := instance.BackupSettings()
+	if err != nil {
+		return err
+	}
+	format, err := backup.ParseFormat(settings.Format)
+	if err != nil {
+		return err
+	}
+	safety, err := instance.CreateBackup(nil, format)
+	switch {
+	case errors.Is(err, backup.ErrNothingToBackup):
+	case err != nil:
+		return fmt.Errorf("could not backup the current worlds: %w", err)
+	default:
+		fmt.Printf("Backed up the current worlds as %s\n", gchalk.Bold(safety.Name))
+	}
+
+	if err := instance.RestoreBackup(b); err != nil {
+		return err
+	}
+	fmt.Printf("Restored backup %s\n", b.Name)
+	return nil
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 )
 
@@ -16,12 +18,30 @@ type configEntry struct {
 }
 
 var config = map[string]configEntry{
-	"noninteractive":      {configKindBool, ""},
-	"usesystemjava":       {configKindBool, ""},
-	"verboselogging":      {configKindBool, ""},
-	"acceptminecrafteula": {configKindBool, ""},
-	"init.defaultsource":  {configKindBool, ""},
-	"updateChannel":       {configKindString, ""},
+	"noninteractive":         {configKindBool, ""},
+	"usesystemjava":          {configKindBool, ""},
+	"verboselogging":         {configKindBool, ""},
+	"acceptminecrafteula":    {configKindBool, ""},
+	"init.defaultsource":     {configKindBool, ""},
+	"updateChannel":          {configKindString, ""},
+	"java.mirrors.adoptium":  {configKindString, "URL of a mirror of the Adoptium API"},
+	"java.mirrors.zulu":      {configKindString, "URL of a mirror of the Azul Zulu API"},
+	"java.mirrors.microsoft": {configKindString, "URL of a mirror of the Microsoft OpenJDK downloads"},
+	"java.mirrors.graalvm":   {configKindString, "URL of a mirror of the GraalVM releases API"},
+	"java.vendor":            {configKindString, "Default java vendor: adoptium, zulu, microsoft or graalvm"},
+	"crashReports":           {configKindString, "Submit crash reports to minepkg.io: ask, always or never"},
+	"server.profileApi":      {configKindString, "URL of the API used to look up player UUIDs"},
+	"curseforge.apiKey":      {configKindString, "API key for CurseForge (defaults to the CURSEFORGE_API_KEY env variable)"},
+}
+
+// configEntryFor returns the entry of the key (keys are case insensitive)
+func configEntryFor(key string) (configEntry, bool) {
+	for name, entry := range config {
+		if strings.EqualFold(name, key) {
+			return entry, true
+		}
+	}
+	return configEntry{}, false
 }
 
 var SubCmd = &cobra.Command{
@@ -24,7 +24,7 @@ type getRunner struct{}
 func (i *getRunner) RunE(cmd *cobra.Command, args []string) error {
 	key := strings.ToLower(args[0])
 
-	_, ok := config[key]
+	_, ok := configEntryFor(key)
 	if !ok {
 		return fmt.Errorf("config key \"%s\" does not exist", key)
 	}
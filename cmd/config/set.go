@@ -30,7 +30,7 @@ func (i *setRunner) RunE(cmd *cobra.Command, args []string) error {
 	value := args[1]
 
 	var newValue interface{}
-	entry, ok := config[key]
+	entry, ok := configEntryFor(key)
 	if !ok {
 		return fmt.Errorf("config key \"%s\" does not exist", key)
 	}
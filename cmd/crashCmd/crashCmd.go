@@ -0,0 +1,48 @@
+package crashCmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/crash"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "crash",
+		Short: "Manage the crash reports saved for this instance",
+	}
+
+	cmd.AddCommand(newList())
+	cmd.AddCommand(newShow())
+	cmd.AddCommand(newSubmit())
+
+	return cmd
+}
+
+// openStore returns the crash report store of the instance in the current directory
+func openStore() (*crash.Store, *instances.Instance, error) {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return nil, nil, err
+	}
+	return crash.NewStore(instance.CrashReportsDir()), instance, nil
+}
+
+// getReport returns the report with the given id or a helpful error
+func getReport(store *crash.Store, id string) (*crash.SavedReport, error) {
+	report, err := store.Get(id)
+	if errors.Is(err, crash.ErrReportNotFound) {
+		return nil, &commands.CliError{
+			Text: fmt.Sprintf("crash report %s does not exist", id),
+			Suggestions: []string{
+				fmt.Sprintf("Run %s to see all saved crash reports", gchalk.Bold("minepkg crash list")),
+			},
+		}
+	}
+	return report, err
+}
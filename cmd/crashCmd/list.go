@@ -0,0 +1,52 @@
+package crashCmd
+
+import (
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func newList() *cobra.Command {
+	cmd := commands.New(&cobra.Command{
+		Use:     "list",
+		Short:   "Lists the saved crash reports of this instance",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}, &listRunner{})
+
+	return cmd.Command
+}
+
+type listRunner struct{}
+
+func (l *listRunner) RunE(cmd *cobra.Command, args []string) error {
+	store, _, err := openStore()
+	if err != nil {
+		return err
+	}
+
+	reports, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		fmt.Println(gchalk.Gray("No crash reports saved"))
+		return nil
+	}
+
+	for _, r := range reports {
+		status := gchalk.Gray("local")
+		if r.Submitted {
+			status = gchalk.Green("submitted")
+		}
+		problem := ""
+		if len(r.Findings) != 0 {
+			problem = r.Findings[0].Problem
+		}
+		fmt.Printf("  %-20s %-18s exit %-4d %s\n", r.ID, status, r.Report.ExitCode, problem)
+	}
+
+	return nil
+}
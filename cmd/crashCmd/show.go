@@ -0,0 +1,84 @@
+package crashCmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/launcher"
+	"github.com/spf13/cobra"
+)
+
+func newShow() *cobra.Command {
+	runner := &showRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "show <id|latest>",
+		Short: "Shows a saved crash report",
+		Long:  "Shows a saved crash report exactly as it would be submitted to minepkg.io",
+		Args:  cobra.MaximumNArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVar(&runner.json, "json", false, "Print the raw report as json")
+
+	return cmd.Command
+}
+
+type showRunner struct {
+	json bool
+}
+
+func (s *showRunner) RunE(cmd *cobra.Command, args []string) error {
+	store, _, err := openStore()
+	if err != nil {
+		return err
+	}
+
+	id := "latest"
+	if len(args) == 1 {
+		id = args[0]
+	}
+	saved, err := getReport(store, id)
+	if err != nil {
+		return err
+	}
+
+	if s.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(saved)
+	}
+
+	report := saved.Report
+	fmt.Println(gchalk.Bold("Crash report " + saved.ID))
+	fmt.Printf("  time: %s\n", saved.Time.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  submitted: %t\n", saved.Submitted)
+	fmt.Printf("  package: %s@%s (%s)\n", report.Package.Name, report.Package.Version, report.Package.Platform)
+	fmt.Printf("  minecraft: %s\n", report.MinecraftVersion)
+	fmt.Printf("  server: %t\n", report.Server)
+	fmt.Printf("  os: %s/%s\n", report.OS, report.Arch)
+	fmt.Printf("  exit code: %d\n", report.ExitCode)
+
+	mods := make([]string, 0, len(report.Mods))
+	for name, version := range report.Mods {
+		mods = append(mods, name+"@"+version)
+	}
+	sort.Strings(mods)
+	fmt.Println(gchalk.Bold("Mods"))
+	for _, mod := range mods {
+		fmt.Println("  " + mod)
+	}
+
+	for _, f := range saved.Findings {
+		fmt.Println(launcher.FindingError(f).RichError())
+	}
+
+	if report.Logs != "" {
+		fmt.Println(gchalk.Bold("Logs"))
+		fmt.Println(report.Logs)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package crashCmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/spf13/cobra"
+)
+
+func newSubmit() *cobra.Command {
+	runner := &submitRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "submit <id|latest>",
+		Short: "Submits a saved crash report to minepkg.io",
+		Long:  "Submits a saved crash report to minepkg.io. Check what gets sent with `minepkg crash show` first",
+		Args:  cobra.MaximumNArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVar(&runner.force, "force", false, "Submit the report even if it was submitted before")
+
+	return cmd.Command
+}
+
+type submitRunner struct {
+	force bool
+}
+
+func (s *submitRunner) RunE(cmd *cobra.Command, args []string) error {
+	store, _, err := openStore()
+	if err != nil {
+		return err
+	}
+
+	id := "latest"
+	if len(args) == 1 {
+		id = args[0]
+	}
+	saved, err := getReport(store, id)
+	if err != nil {
+		return err
+	}
+
+	if saved.Submitted && !s.force {
+		return &commands.CliError{
+			Text:        fmt.Sprintf("crash report %s was already submitted", saved.ID),
+			Suggestions: []string{"Use --force to submit it again"},
+		}
+	}
+
+	if err := globals.ApiClient.PostCrashReport(context.TODO(), saved.Report); err != nil {
+		return err
+	}
+
+	saved.Submitted = true
+	if err := store.Save(saved); err != nil {
+		return err
+	}
+	fmt.Printf("Submitted crash report %s\n", saved.ID)
+	return nil
+}
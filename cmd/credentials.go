@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/credentials"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	credentialsCmd := &cobra.Command{
+		Use:   "credentials",
+		Short: "Manages where your login credentials are stored",
+		Long: fmt.Sprintf(`Credentials are stored in the system keyring. If there is none (on headless servers for example),
+they are stored in files encrypted with a passphrase. The passphrase is read from %s or prompted for.`, credentials.PassphraseEnv),
+	}
+
+	migrateRunner := &credentialsMigrateRunner{}
+	migrateCmd := commands.New(&cobra.Command{
+		Use:     "migrate",
+		Short:   "Moves all credentials to the keyring or the encrypted file",
+		Example: "  minepkg credentials migrate --to file",
+		Args:    cobra.ExactArgs(0),
+	}, migrateRunner)
+	migrateCmd.Flags().StringVar(&migrateRunner.to, "to", "", "Where to move the credentials: keyring or file")
+	migrateCmd.MarkFlagRequired("to")
+
+	credentialsCmd.AddCommand(migrateCmd.Command)
+	rootCmd.AddCommand(credentialsCmd)
+}
+
+type credentialsMigrateRunner struct {
+	to string
+}
+
+func (c *credentialsMigrateRunner) RunE(cmd *cobra.Command, args []string) error {
+	if c.to != "keyring" && c.to != "file" {
+		return &commands.CliError{
+			Text:        fmt.Sprintf("can not migrate to %q", c.to),
+			Suggestions: []string{"Use --to keyring or --to file"},
+		}
+	}
+
+	stores := []*credentials.Store{root.minepkgAuthStore}
+	accounts, err := root.loadAccounts()
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts.Accounts {
+		stores = append(stores, accounts.Store(account))
+	}
+
+	moved := 0
+	for _, store := range stores {
+		migrate := store.MigrateToFile
+		if c.to == "keyring" {
+			migrate = store.MigrateToKeyring
+		}
+		ok, err := migrate()
+		if err != nil {
+			return fmt.Errorf("could not migrate %s: %w", store.Name, err)
+		}
+		if ok {
+			fmt.Printf("Moved %s to the %s\n", store.Name, c.to)
+			moved++
+		}
+	}
+
+	if moved == 0 {
+		fmt.Println("No credentials to move")
+		return nil
+	}
+	fmt.Println(gchalk.Green("✓"), "Migrated", moved, "credentials")
+	return nil
+}
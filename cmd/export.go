@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/curseforge"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/launcher"
+	"github.com/minepkg/minepkg/internals/mrpack"
+	"github.com/minepkg/minepkg/internals/packwiz"
+	"github.com/minepkg/minepkg/internals/prism"
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	runner := &exportRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "export",
+		Short: "Exports the modpack in the current directory for other launchers",
+		Long: `Exports the modpack in the current directory for other launchers.
+Supported formats:
+  mrpack       Modrinth modpack (.mrpack). Mods that are not hosted on Modrinth, GitHub or GitLab are embedded
+  curseforge   CurseForge modpack zip. Mods that are not from CurseForge are embedded
+  prism        Prism Launcher / MultiMC instance zip (or a directory if the output does not end with .zip)
+  packwiz      packwiz pack directory (pack.toml, index.toml and mods/*.pw.toml)
+
+The content of the "overwrites" directory is included.`,
+		Example: "  minepkg export --format mrpack -o my-pack.mrpack",
+		Args:    cobra.NoArgs,
+	}, runner)
+
+	cmd.Flags().StringVar(&runner.format, "format", "mrpack", "Format of the export: mrpack, curseforge, prism or packwiz")
+	cmd.Flags().StringVarP(&runner.output, "output", "o", "", "File or directory to write (defaults to <name>-<version>.mrpack, .zip or -packwiz)")
+
+	rootCmd.AddCommand(cmd.Command)
+}
+
+type exportFormat struct {
+	// ext is the extension of the exported file. Formats without one are exported as directory
+	ext    string
+	export func(instance *instances.Instance, output string) error
+}
+
+var exportFormats = map[string]exportFormat{
+	"mrpack":     {"mrpack", exportMrpack},
+	"curseforge": {"zip", exportCurseForge},
+	"prism":      {"zip", exportPrism},
+	"packwiz":    {"", exportPackwiz},
+}
+
+type exportRunner struct {
+	format string
+	output string
+}
+
+func (e *exportRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := root.LocalInstance()
+	if err != nil {
+		return err
+	}
+	if instance.Manifest.Package.Type != manifest.TypeModpack {
+		return &commands.CliError{
+			Text:        "can only export modpacks",
+			Suggestions: []string{"Run this in a directory with the minepkg.toml of a modpack"},
+		}
+	}
+
+	format, ok := exportFormats[e.format]
+	if !ok {
+		return &commands.CliError{
+			Text:        fmt.Sprintf("unsupported export format %q", e.format),
+			Suggestions: []string{"Use --format mrpack, --format curseforge, --format prism or --format packwiz"},
+		}
+	}
+
+	if err := resolveModpack(instance); err != nil {
+		return err
+	}
+
+	output := e.output
+	if output == "" {
+		output = fmt.Sprintf("%s-%s", instance.Manifest.Package.Name, exportVersion(instance))
+		if format.ext != "" {
+			output += "." + format.ext
+		} else {
+			output += "-" + e.format
+		}
+	}
+	return format.export(instance, output)
+}
+
+// resolveModpack resolves all dependencies, saves the lockfile and downloads them
+func resolveModpack(instance *instances.Instance) error {
+	ctx := context.Background()
+	cliLauncher := &launcher.Launcher{
+		Instance:       instance,
+		MinepkgVersion: rootCmd.Version,
+		NonInteractive: viper.GetBool("nonInteractive"),
+	}
+
+	outdatedReqs, err := cliLauncher.PrepareRequirements()
+	if err != nil {
+		return fmt.Errorf("failed to update requirements: %w", err)
+	}
+	if err := cliLauncher.PrepareDependencies(ctx, outdatedReqs); err != nil {
+		return err
+	}
+	return instance.EnsureDependencies(ctx)
+}
+
+// exportVersion returns the version of the modpack or "dev" if it has none
+func exportVersion(instance *instances.Instance) string {
+	if instance.Manifest.Package.Version != "" {
+		return instance.Manifest.Package.Version
+	}
+	return "dev"
+}
+
+// exportFiles returns the mod files to export and warns about the ones that can not be exported
+func exportFiles(instance *instances.Instance) []instances.PackFile {
+	files, skipped := instance.PackFiles()
+	for _, dep := range skipped {
+		fmt.Println(gchalk.Yellow(fmt.Sprintf("[minepkg] Skipping modpack dependency %s, only mods can be exported", dep.Name)))
+	}
+	return files
+}
+
+func exportMrpack(instance *instances.Instance, output string) error {
+	lock := instance.Lockfile.PlatformLock()
+	pack := &mrpack.Pack{
+		Name:    instance.Manifest.Package.Name,
+		Version: exportVersion(instance),
+		Summary: instance.Manifest.Package.Description,
+		Dependencies: map[string]string{
+			mrpack.DependencyMinecraft: lock.MinecraftVersion(),
+		},
+		Overrides: instance.OverwritesDir(),
+	}
+	switch lock.PlatformName() {
+	case manifest.PlatformFabric:
+		pack.Dependencies[mrpack.DependencyFabricLoader] = lock.PlatformVersion()
+	case manifest.PlatformForge:
+		pack.Dependencies[mrpack.DependencyForge] = lock.PlatformVersion()
+	}
+
+	for _, file := range exportFiles(instance) {
+		pack.Mods = append(pack.Mods, mrpack.Mod{
+			Path:     file.Path,
+			Filename: file.Lock.Filename(),
+			URL:      file.Lock.URL,
+		})
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	index, err := mrpack.Write(f, pack)
+	if err != nil {
+		f.Close()
+		os.Remove(output)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	embedded := len(pack.Mods) - len(index.Files)
+	fmt.Printf("Exported %s (%d downloads, %d embedded mods)\n", gchalk.Bold(output), len(index.Files), embedded)
+	return nil
+}
+
+func exportCurseForge(instance *instances.Instance, output string) error {
+	lock := instance.Lockfile.PlatformLock()
+	pack := &curseforge.Pack{
+		Name:      instance.Manifest.Package.Name,
+		Version:   exportVersion(instance),
+		Author:    instance.Manifest.AuthorName(),
+		Minecraft: lock.MinecraftVersion(),
+		Overrides: instance.OverwritesDir(),
+	}
+	if lock.PlatformVersion() != "" {
+		pack.Loader = curseforge.LoaderID(lock.PlatformName(), lock.PlatformVersion())
+	}
+
+	for _, file := range exportFiles(instance) {
+		if ref, ok := curseForgeFile(instance, file.Lock); ok {
+			pack.Files = append(pack.Files, ref)
+			continue
+		}
+		pack.Embedded = append(pack.Embedded, curseforge.EmbeddedMod{
+			Path:     file.Path,
+			Filename: file.Lock.Filename(),
+		})
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if _, err := curseforge.Write(f, pack); err != nil {
+		f.Close()
+		os.Remove(output)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %s (%d CurseForge mods, %d embedded mods)\n", gchalk.Bold(output), len(pack.Files), len(pack.Embedded))
+	return nil
+}
+
+// curseForgeFile returns the CurseForge file of a `curseforge:` dependency
+func curseForgeFile(instance *instances.Instance, lock *manifest.DependencyLock) (curseforge.ManifestFile, bool) {
+	if lock.Provider != "curseforge" {
+		return curseforge.ManifestFile{}, false
+	}
+	source := strings.TrimPrefix(instance.Manifest.Dependencies[lock.Name], "curseforge:")
+	projectID, fileID, err := curseforge.ParseSource(source)
+	if err != nil {
+		return curseforge.ManifestFile{}, false
+	}
+	return curseforge.ManifestFile{ProjectID: projectID, FileID: fileID, Required: true}, true
+}
+
+func exportPrism(instance *instances.Instance, output string) error {
+	lock := instance.Lockfile.PlatformLock()
+	prismInstance := &prism.Instance{
+		Name:            instance.Manifest.Package.Name,
+		Minecraft:       lock.MinecraftVersion(),
+		Platform:        lock.PlatformName(),
+		PlatformVersion: lock.PlatformVersion(),
+		Launch:          instance.Manifest.Launch,
+		Overrides:       instance.OverwritesDir(),
+	}
+	for _, file := range exportFiles(instance) {
+		prismInstance.Mods = append(prismInstance.Mods, prism.Mod{
+			Path:     file.Path,
+			Filename: file.Lock.Filename(),
+		})
+	}
+
+	if !strings.HasSuffix(output, ".zip") {
+		if _, err := os.Stat(output); err == nil {
+			return fmt.Errorf("%s already exists", output)
+		}
+		if err := prism.WriteDir(output, prismInstance); err != nil {
+			return err
+		}
+		fmt.Printf("Exported %s (%d mods). Move it into the instances directory of Prism Launcher or MultiMC\n", gchalk.Bold(output), len(prismInstance.Mods))
+		return nil
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := prism.Write(f, prismInstance); err != nil {
+		f.Close()
+		os.Remove(output)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %s (%d mods). Import it with \"Add Instance\" → \"Import\" in Prism Launcher or MultiMC\n", gchalk.Bold(output), len(prismInstance.Mods))
+	return nil
+}
+
+func exportPackwiz(instance *instances.Instance, output string) error {
+	if _, err := os.Stat(output); err == nil {
+		return fmt.Errorf("%s already exists", output)
+	}
+	lock := instance.Lockfile.PlatformLock()
+	export := &packwiz.Export{
+		Name:    instance.Manifest.Package.Name,
+		Author:  instance.Manifest.AuthorName(),
+		Version: exportVersion(instance),
+		Versions: map[string]string{
+			packwiz.VersionMinecraft: lock.MinecraftVersion(),
+		},
+		Overrides: instance.OverwritesDir(),
+	}
+	switch lock.PlatformName() {
+	case manifest.PlatformFabric:
+		export.Versions[packwiz.VersionFabric] = lock.PlatformVersion()
+	case manifest.PlatformForge:
+		export.Versions[packwiz.VersionForge] = lock.PlatformVersion()
+	}
+
+	for _, file := range exportFiles(instance) {
+		mod := packwiz.Mod{
+			Name:     file.Lock.Name,
+			Path:     file.Path,
+			Filename: file.Lock.Filename(),
+		}
+		if strings.HasPrefix(file.Lock.URL, "https://") {
+			mod.URL = file.Lock.URL
+		}
+		if ref, ok := curseForgeFile(instance, file.Lock); ok {
+			mod.Update = &packwiz.Update{CurseForge: &packwiz.CurseForgeUpdate{ProjectID: ref.ProjectID, FileID: ref.FileID}}
+		} else if update, ok := modrinthUpdate(file.Lock); ok {
+			mod.Update = &packwiz.Update{Modrinth: update}
+		}
+		export.Mods = append(export.Mods, mod)
+	}
+
+	if err := os.MkdirAll(output, os.ModePerm); err != nil {
+		return err
+	}
+	if _, err := packwiz.Write(output, export); err != nil {
+		os.RemoveAll(output)
+		return err
+	}
+
+	metafiles := 0
+	for _, mod := range export.Mods {
+		if mod.URL != "" {
+			metafiles++
+		}
+	}
+	fmt.Printf("Exported %s (%d metafiles, %d embedded mods)\n", gchalk.Bold(output), metafiles, len(export.Mods)-metafiles)
+	return nil
+}
+
+// modrinthUpdate returns the Modrinth project and version of a `modrinth:` dependency.
+// They are part of the download url (https://cdn.modrinth.com/data/<project>/versions/<version>/<file>)
+func modrinthUpdate(lock *manifest.DependencyLock) (*packwiz.ModrinthUpdate, bool) {
+	if lock.Provider != "modrinth" {
+		return nil, false
+	}
+	parts := strings.Split(strings.TrimPrefix(lock.URL, "https://cdn.modrinth.com/data/"), "/")
+	if len(parts) < 4 || parts[1] != "versions" || parts[0] == "" || parts[2] == "" {
+		return nil, false
+	}
+	return &packwiz.ModrinthUpdate{ModID: parts[0], Version: parts[2]}, true
+}
@@ -0,0 +1,482 @@
+package cmd
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/curseforge"
+	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/modrinth"
+	"github.com/minepkg/minepkg/internals/mrpack"
+	"github.com/minepkg/minepkg/internals/packwiz"
+	"github.com/minepkg/minepkg/internals/prism"
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &importRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "import <file/url/slug>",
+		Short: "Creates a minepkg modpack in the current directory from a modpack of another launcher",
+		Long: `Creates a minepkg.toml (and minepkg-lock.toml) in the current directory from a modpack.
+Supported formats:
+  mrpack       Modrinth modpack (.mrpack file, url or the slug of a Modrinth modpack)
+  curseforge   CurseForge modpack zip (file or url)
+  prism        Prism Launcher / MultiMC instance (directory, zip or url)
+  packwiz      packwiz pack (directory or url of the pack.toml)
+
+The format is detected from the content of the file if --format is not set.
+Mods that are published on Modrinth are added as "modrinth:" dependencies, mods of CurseForge
+packs as "curseforge:" dependencies and all other mods are downloaded from their url.
+Mods of instances are identified on Modrinth by their hash, unknown mods are copied.
+packwiz metafiles with Modrinth or CurseForge metadata become "modrinth:" or "curseforge:" dependencies.
+Overrides are unpacked into the "overwrites" directory.`,
+		Example: `  minepkg import my-pack.mrpack
+  minepkg import fabulously-optimized
+  minepkg import --format curseforge my-pack.zip
+  minepkg import ~/.local/share/PrismLauncher/instances/my-pack
+  minepkg import --format packwiz https://example.com/my-pack/pack.toml`,
+		Args: cobra.ExactArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.force, "force", "f", false, "Overwrite the minepkg.toml if one exists")
+	cmd.Flags().StringVar(&runner.format, "format", "", "Format of the modpack: mrpack, curseforge, prism or packwiz (detected if not set)")
+
+	rootCmd.AddCommand(cmd.Command)
+}
+
+var importFormats = map[string]bool{"mrpack": true, "curseforge": true, "prism": true, "packwiz": true}
+
+type importRunner struct {
+	force  bool
+	format string
+}
+
+func (i *importRunner) RunE(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat("minepkg.toml"); err == nil && !i.force {
+		return &commands.CliError{
+			Text:        "this directory already contains a minepkg.toml",
+			Suggestions: []string{"Run this in an empty directory", "Use --force to overwrite it"},
+		}
+	}
+	if i.format != "" && !importFormats[i.format] {
+		return &commands.CliError{
+			Text:        fmt.Sprintf("unsupported import format %q", i.format),
+			Suggestions: []string{"Use --format mrpack, --format curseforge, --format prism or --format packwiz"},
+		}
+	}
+
+	ctx := context.Background()
+	client := modrinth.New()
+
+	// packwiz packs are read file by file, so they are not downloaded first
+	if i.format == "packwiz" || isPackwizPack(args[0]) {
+		return importPackwiz(ctx, args[0])
+	}
+
+	file, cleanup, err := i.packFile(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	format := i.format
+	if format == "" {
+		if format, err = detectImportFormat(file); err != nil {
+			return err
+		}
+	}
+
+	switch format {
+	case "curseforge":
+		return importCurseForge(ctx, file)
+	case "prism":
+		return importPrism(ctx, client, file)
+	default:
+		return importMrpack(ctx, client, file)
+	}
+}
+
+// isPackwizPack returns true if location is a `pack.toml`, its url or a directory containing one
+func isPackwizPack(location string) bool {
+	if path.Base(location) == packwiz.PackFile || filepath.Base(location) == packwiz.PackFile {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(location, packwiz.PackFile))
+	return err == nil
+}
+
+// detectImportFormat returns the format of the modpack by looking at the files it contains
+func detectImportFormat(file string) (string, error) {
+	if info, err := os.Stat(file); err == nil && info.IsDir() {
+		source, err := prism.Open(file)
+		if err != nil {
+			return "", &commands.CliError{
+				Text:        fmt.Sprintf("%s is not a modpack: %s", file, err),
+				Suggestions: []string{"Pass the directory of a Prism Launcher or MultiMC instance"},
+			}
+		}
+		source.Close()
+		return "prism", nil
+	}
+
+	archive, err := zip.OpenReader(file)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a modpack: %w", file, err)
+	}
+	defer archive.Close()
+
+	for _, f := range archive.File {
+		switch f.Name {
+		case mrpack.IndexFile:
+			return "mrpack", nil
+		case curseforge.ManifestName:
+			return "curseforge", nil
+		}
+		// instances can also be in a subdirectory of the zip
+		if path.Base(f.Name) == prism.PackFile && strings.Count(f.Name, "/") <= 1 {
+			return "prism", nil
+		}
+	}
+	return "", &commands.CliError{
+		Text:        fmt.Sprintf("%s is neither a Modrinth or CurseForge modpack nor an instance", file),
+		Suggestions: []string{"Only Modrinth and CurseForge modpacks and Prism Launcher or MultiMC instances can be imported"},
+	}
+}
+
+// saveImportedManifest writes the manifest to the current directory and removes an old lockfile
+func saveImportedManifest(man *manifest.Manifest) (*instances.Instance, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	instance := instances.New()
+	instance.Directory = wd
+	instance.Manifest = man
+	if err := instance.SaveManifest(); err != nil {
+		return nil, err
+	}
+	// a lockfile of a previous pack would pin outdated versions
+	if err := os.Remove(instance.LockfilePath()); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// resolveImported reopens the instance in the current directory and generates its lockfile
+func resolveImported() (*instances.Instance, error) {
+	instance, err := root.LocalInstance()
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveModpack(instance); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+func importMrpack(ctx context.Context, client *modrinth.Client, file string) error {
+	archive, err := mrpack.Open(file)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	fmt.Printf("Importing %s %s\n", gchalk.Bold(archive.Index.Name), archive.Index.VersionID)
+	imported, err := archive.Index.Import(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	instance, err := saveImportedManifest(imported.Manifest)
+	if err != nil {
+		return err
+	}
+
+	if err := archive.ExtractOverrides(instance.OverwritesDir()); err != nil {
+		return fmt.Errorf("could not extract overrides: %w", err)
+	}
+	for _, other := range imported.Other {
+		target, err := other.Target(instance.OverwritesDir())
+		if err != nil {
+			return err
+		}
+		if err := other.Download(ctx, root.HTTPClient, target); err != nil {
+			return err
+		}
+	}
+
+	if instance, err = resolveImported(); err != nil {
+		return err
+	}
+	mismatches := importMismatches(instance, imported)
+
+	fmt.Println(gchalk.Green("✓"), "Created minepkg.toml")
+	fmt.Printf("  %d Modrinth mods, %d mods from urls, %d files in overwrites\n", len(imported.Modrinth), len(imported.HTTPS), len(imported.Other))
+	for _, skipped := range imported.Skipped {
+		fmt.Println(gchalk.Yellow(fmt.Sprintf("  Skipped %s (server only or no https download)", skipped.Path)))
+	}
+	for _, name := range mismatches {
+		fmt.Println(gchalk.Yellow(fmt.Sprintf("  %s resolved to a different file than the one in the pack", name)))
+	}
+	return nil
+}
+
+func importCurseForge(ctx context.Context, file string) error {
+	modpack, err := curseforge.Open(file)
+	if err != nil {
+		return err
+	}
+	defer modpack.Close()
+
+	fmt.Printf("Importing %s %s\n", gchalk.Bold(modpack.Manifest.Name), modpack.Manifest.Version)
+	imported, err := modpack.Manifest.Import(ctx, globals.CurseForgeClient)
+	if errors.Is(err, curseforge.ErrNoAPIKey) {
+		return &commands.CliError{
+			Text: "importing CurseForge modpacks needs a CurseForge API key",
+			Suggestions: []string{
+				fmt.Sprintf("Set the %s environment variable", curseforge.APIKeyEnv),
+				"Or run: minepkg config set curseforge.apiKey <key>",
+			},
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	instance, err := saveImportedManifest(imported.Manifest)
+	if err != nil {
+		return err
+	}
+	if err := modpack.ExtractOverrides(instance.OverwritesDir()); err != nil {
+		return fmt.Errorf("could not extract overrides: %w", err)
+	}
+
+	if _, err := resolveImported(); err != nil {
+		return err
+	}
+
+	fmt.Println(gchalk.Green("✓"), "Created minepkg.toml")
+	fmt.Printf("  %d CurseForge mods\n", len(imported.Manifest.Dependencies))
+	if len(imported.Unresolved) != 0 {
+		fmt.Println(gchalk.Yellow(fmt.Sprintf("  %d files could not be added:", len(imported.Unresolved))))
+		for _, unresolved := range imported.Unresolved {
+			name := unresolved.Name
+			if name == "" {
+				name = "unknown mod"
+			}
+			fmt.Printf("    %s (project %d, file %d): %s\n", name, unresolved.ProjectID, unresolved.FileID, unresolved.Reason)
+		}
+		fmt.Println("  Add them to the overwrites/mods directory or replace them with other dependencies")
+	}
+	return nil
+}
+
+func importPrism(ctx context.Context, client *modrinth.Client, file string) error {
+	source, err := prism.Open(file)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	fmt.Printf("Importing instance %s\n", gchalk.Bold(source.Name))
+	imported, err := source.Import(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	instance, err := saveImportedManifest(imported.Manifest)
+	if err != nil {
+		return err
+	}
+	if err := source.Extract(instance.OverwritesDir(), imported); err != nil {
+		return fmt.Errorf("could not copy the instance files: %w", err)
+	}
+
+	if _, err := resolveImported(); err != nil {
+		return err
+	}
+
+	fmt.Println(gchalk.Green("✓"), "Created minepkg.toml")
+	fmt.Printf("  %d Modrinth mods\n", len(imported.Modrinth))
+	if len(imported.Unidentified) != 0 {
+		fmt.Println(gchalk.Yellow(fmt.Sprintf("  %d mods are not on Modrinth and were copied to overwrites/mods:", len(imported.Unidentified))))
+		for _, mod := range imported.Unidentified {
+			fmt.Println("    " + mod)
+		}
+	}
+	return nil
+}
+
+func importPackwiz(ctx context.Context, location string) error {
+	modpack, err := packwiz.Open(ctx, location, root.HTTPClient)
+	if errors.Is(err, packwiz.ErrNoPack) {
+		return &commands.CliError{
+			Text:        fmt.Sprintf("%s is not a packwiz pack", location),
+			Suggestions: []string{"Pass the directory of the pack or the url of its pack.toml"},
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Importing %s %s\n", gchalk.Bold(modpack.Pack.Name), modpack.Pack.Version)
+	imported, err := modpack.Import(ctx)
+	if errors.Is(err, packwiz.ErrUnsupportedLoader) {
+		return &commands.CliError{
+			Text:        err.Error(),
+			Suggestions: []string{"Only vanilla, Fabric and Forge packs can be imported"},
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	instance, err := saveImportedManifest(imported.Manifest)
+	if err != nil {
+		return err
+	}
+	for i := range imported.Files {
+		if err := modpack.CopyFile(ctx, &imported.Files[i], instance.OverwritesDir()); err != nil {
+			return fmt.Errorf("could not copy %s: %w", imported.Files[i].File, err)
+		}
+	}
+	for _, other := range imported.Downloads {
+		if err := modpack.Download(ctx, other.Metafile, other.Dir, instance.OverwritesDir()); err != nil {
+			return err
+		}
+	}
+
+	if instance, err = resolveImported(); err != nil {
+		return err
+	}
+
+	fmt.Println(gchalk.Green("✓"), "Created minepkg.toml")
+	fmt.Printf("  %d mods, %d files in overwrites\n", len(imported.Metafiles), len(imported.Files)+len(imported.Downloads))
+	for _, unresolved := range imported.Unresolved {
+		fmt.Println(gchalk.Yellow(fmt.Sprintf("  Skipped %s (%s)", unresolved.File, unresolved.Reason)))
+	}
+	for _, name := range packwizMismatches(instance, imported) {
+		fmt.Println(gchalk.Yellow(fmt.Sprintf("  %s resolved to a different file than the one in the pack", name)))
+	}
+	return nil
+}
+
+// packwizMismatches returns the dependencies that were locked to another file than the one in the metafile
+func packwizMismatches(instance *instances.Instance, imported *packwiz.Import) []string {
+	mismatches := []string{}
+	for name, meta := range imported.Metafiles {
+		lock := instance.Lockfile.Dependencies[name]
+		if lock == nil || meta.Download.Hash == "" {
+			continue
+		}
+		locked := ""
+		switch meta.Download.HashFormat {
+		case "sha1":
+			locked = lock.Sha1
+		case "sha256":
+			locked = lock.Sha256
+		case "sha512":
+			locked = lock.Sha512
+		}
+		if locked != "" && !strings.EqualFold(locked, meta.Download.Hash) {
+			mismatches = append(mismatches, name)
+		}
+	}
+	sort.Strings(mismatches)
+	return mismatches
+}
+
+// packFile returns the path of the .mrpack file. Urls and Modrinth slugs are downloaded to a temporary file
+func (i *importRunner) packFile(ctx context.Context, client *modrinth.Client, arg string) (string, func(), error) {
+	noop := func() {}
+	if _, err := os.Stat(arg); err == nil {
+		return arg, noop, nil
+	}
+	isURL := strings.HasPrefix(arg, "https://")
+	if !isURL && (strings.HasSuffix(arg, ".mrpack") || strings.HasSuffix(arg, ".zip")) {
+		return "", noop, fmt.Errorf("%s does not exist", arg)
+	}
+	if !isURL && i.format != "" && i.format != "mrpack" {
+		return "", noop, fmt.Errorf("%s does not exist", arg)
+	}
+
+	url := arg
+	if !isURL {
+		versions, err := client.ListProjectVersion(ctx, arg, nil)
+		if err != nil {
+			return "", noop, &commands.CliError{
+				Text:        fmt.Sprintf("could not find the modpack %q on Modrinth: %s", arg, err),
+				Suggestions: []string{"Check the slug in the url of the modpack on modrinth.com"},
+			}
+		}
+		if len(versions) == 0 || len(versions[0].Files) == 0 {
+			return "", noop, fmt.Errorf("the modpack %q has no files", arg)
+		}
+		file := versions[0].Files[0]
+		for _, f := range versions[0].Files {
+			if f.Primary {
+				file = f
+				break
+			}
+		}
+		fmt.Printf("Downloading %s (%s)\n", file.Filename, versions[0].VersionNumber)
+		url = file.URL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", noop, err
+	}
+	res, err := root.HTTPClient.Do(req)
+	if err != nil {
+		return "", noop, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", noop, fmt.Errorf("could not download %s: unexpected status code %d", url, res.StatusCode)
+	}
+
+	tmp, err := ioutil.TempFile("", "minepkg-import-*")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+	if _, err := io.Copy(tmp, res.Body); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", noop, err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return tmp.Name(), cleanup, nil
+}
+
+// importMismatches returns the Modrinth dependencies that were locked to another file than the one in the pack
+func importMismatches(instance *instances.Instance, imported *mrpack.Import) []string {
+	mismatches := []string{}
+	for _, name := range imported.Modrinth {
+		lock := instance.Lockfile.Dependencies[name]
+		file := imported.Files[name]
+		if lock != nil && file.Hashes.Sha512 != "" && lock.Sha512 != file.Hashes.Sha512 {
+			mismatches = append(mismatches, name)
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,43 @@
+package instanceCmd
+
+import (
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func newClone() *cobra.Command {
+	cmd := commands.New(&cobra.Command{
+		Use:     "clone <name> <new-name>",
+		Aliases: []string{"cp"},
+		Short:   "Copies an instance with all its worlds and settings",
+		Args:    cobra.ExactArgs(2),
+	}, &cloneRunner{})
+
+	return cmd.Command
+}
+
+type cloneRunner struct{}
+
+func (c *cloneRunner) RunE(cmd *cobra.Command, args []string) error {
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	if _, err := index.Get(args[0]); err != nil {
+		return indexError(args[0], err)
+	}
+
+	fmt.Printf("Copying %s …\n", args[0])
+	if _, err := index.Clone(args[0], args[1]); err != nil {
+		return indexError(args[1], err)
+	}
+	if err := index.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s. Start it with %s\n", args[1], gchalk.Bold("minepkg launch "+args[1]))
+	return nil
+}
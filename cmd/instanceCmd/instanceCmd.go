@@ -0,0 +1,90 @@
+package instanceCmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "instance",
+		Aliases: []string{"instances"},
+		Short:   "Manage the instances created by join and launch",
+		Long: `Manages the instances in the minepkg instances directory.
+They are created by "minepkg join" and "minepkg launch <modpack>" and can be launched by name:
+
+  minepkg launch <instance>`,
+	}
+
+	cmd.AddCommand(newList())
+	cmd.AddCommand(newShow())
+	cmd.AddCommand(newRename())
+	cmd.AddCommand(newClone())
+	cmd.AddCommand(newRemove())
+	cmd.AddCommand(newPath())
+
+	return cmd
+}
+
+// loadIndex reads the index of the global instances directory
+func loadIndex() (*instances.Index, error) {
+	return instances.LoadIndex(instances.New().InstancesDir())
+}
+
+// indexError turns index errors into helpful cli errors
+func indexError(name string, err error) error {
+	switch {
+	case errors.Is(err, instances.ErrInstanceNotFound):
+		return &commands.CliError{
+			Text: fmt.Sprintf("there is no instance named %s", name),
+			Suggestions: []string{
+				fmt.Sprintf("Run %s to list all instances", gchalk.Bold("minepkg instance list")),
+			},
+		}
+	case errors.Is(err, instances.ErrInstanceExists):
+		return &commands.CliError{
+			Text:        fmt.Sprintf("there already is an instance named %s", name),
+			Suggestions: []string{"Choose another name"},
+		}
+	case errors.Is(err, instances.ErrInvalidInstanceName):
+		return &commands.CliError{
+			Text:        fmt.Sprintf("%s can not be used as instance name", name),
+			Suggestions: []string{"Use letters, numbers, ., - and _ only"},
+		}
+	}
+	return err
+}
+
+// ensureStopped returns an error if the instance runs in the background or in the foreground
+func ensureStopped(index *instances.Index, name string) error {
+	instance, err := index.Instance(name)
+	if err != nil {
+		return indexError(name, err)
+	}
+	if _, err := instance.RunInfo(); err == nil {
+		return &commands.CliError{
+			Text:        fmt.Sprintf("%s is running", name),
+			Suggestions: []string{fmt.Sprintf("Stop it first with %s", gchalk.Bold("minepkg stop "+instance.Name()))},
+		}
+	}
+	if info, err := instance.ForegroundRunInfo(); err == nil {
+		return &commands.CliError{
+			Text:        fmt.Sprintf("%s is running in another terminal (minepkg pid %d)", name, info.PID),
+			Suggestions: []string{"Stop it first"},
+		}
+	}
+	return nil
+}
+
+// minecraftVersion returns the locked Minecraft version or the required one if the instance was never launched
+func minecraftVersion(instance *instances.Instance) string {
+	if instance.Lockfile != nil && instance.Lockfile.MinecraftVersion() != "" {
+		return instance.Lockfile.MinecraftVersion()
+	}
+	return instance.Manifest.Requirements.Minecraft
+}
@@ -0,0 +1,59 @@
+package instanceCmd
+
+import (
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func newList() *cobra.Command {
+	cmd := commands.New(&cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "Lists all instances",
+		Args:    cobra.NoArgs,
+	}, &listRunner{})
+
+	return cmd.Command
+}
+
+type listRunner struct{}
+
+func (l *listRunner) RunE(cmd *cobra.Command, args []string) error {
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	if len(index.Instances) == 0 {
+		fmt.Println(gchalk.Gray("No instances yet. Create one with ") + gchalk.Bold("minepkg launch <modpack>"))
+		return nil
+	}
+	// new instances might have been found
+	if err := index.Save(); err != nil {
+		return err
+	}
+
+	fmt.Println(gchalk.Bold(fmt.Sprintf("%-32s %-24s %-10s %9s  %s", "NAME", "PACK", "MINECRAFT", "SIZE", "LAST PLAYED")))
+	for _, entry := range index.Instances {
+		instance, err := index.Instance(entry.Name)
+		if err != nil {
+			fmt.Printf("%-32s %s\n", entry.Name, gchalk.Red("broken: "+err.Error()))
+			continue
+		}
+		lastPlayed := "never"
+		if !entry.LastPlayed.IsZero() {
+			lastPlayed = entry.LastPlayed.Format("2006-01-02 15:04")
+		}
+		fmt.Printf(
+			"%-32s %-24s %-10s %9s  %s\n",
+			entry.Name,
+			instance.Name(),
+			minecraftVersion(instance),
+			commands.HumanSize(commands.DirSize(index.Dir(entry))),
+			lastPlayed,
+		)
+	}
+	return nil
+}
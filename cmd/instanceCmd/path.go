@@ -0,0 +1,34 @@
+package instanceCmd
+
+import (
+	"fmt"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func newPath() *cobra.Command {
+	cmd := commands.New(&cobra.Command{
+		Use:     "path <name>",
+		Short:   "Prints the directory of an instance",
+		Example: `  cd "$(minepkg instance path my-pack)"`,
+		Args:    cobra.ExactArgs(1),
+	}, &pathRunner{})
+
+	return cmd.Command
+}
+
+type pathRunner struct{}
+
+func (p *pathRunner) RunE(cmd *cobra.Command, args []string) error {
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	entry, err := index.Get(args[0])
+	if err != nil {
+		return indexError(args[0], err)
+	}
+	fmt.Println(index.Dir(entry))
+	return nil
+}
@@ -0,0 +1,57 @@
+package instanceCmd
+
+import (
+	"fmt"
+
+	"github.com/erikgeiser/promptkit/confirmation"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func newRemove() *cobra.Command {
+	runner := &removeRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Deletes an instance including all its worlds",
+		Args:    cobra.ExactArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.yes, "yes", "y", false, "Do not ask for confirmation")
+
+	return cmd.Command
+}
+
+type removeRunner struct {
+	yes bool
+}
+
+func (r *removeRunner) RunE(cmd *cobra.Command, args []string) error {
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	if err := ensureStopped(index, args[0]); err != nil {
+		return err
+	}
+
+	if !r.yes && !viper.GetBool("nonInteractive") {
+		input := confirmation.New(fmt.Sprintf("Delete %s including all its worlds?", args[0]), confirmation.No)
+		ok, err := input.RunPrompt()
+		if err != nil || !ok {
+			fmt.Println("Aborting")
+			return nil
+		}
+	}
+
+	if err := index.Remove(args[0]); err != nil {
+		return indexError(args[0], err)
+	}
+	if err := index.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed %s\n", args[0])
+	return nil
+}
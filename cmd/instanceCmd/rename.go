@@ -0,0 +1,41 @@
+package instanceCmd
+
+import (
+	"fmt"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func newRename() *cobra.Command {
+	cmd := commands.New(&cobra.Command{
+		Use:     "rename <name> <new-name>",
+		Aliases: []string{"mv"},
+		Short:   "Renames an instance and its directory",
+		Args:    cobra.ExactArgs(2),
+	}, &renameRunner{})
+
+	return cmd.Command
+}
+
+type renameRunner struct{}
+
+func (r *renameRunner) RunE(cmd *cobra.Command, args []string) error {
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	if err := ensureStopped(index, args[0]); err != nil {
+		return err
+	}
+
+	if err := index.Rename(args[0], args[1]); err != nil {
+		return indexError(args[1], err)
+	}
+	if err := index.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Renamed %s to %s\n", args[0], args[1])
+	return nil
+}
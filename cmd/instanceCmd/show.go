@@ -0,0 +1,56 @@
+package instanceCmd
+
+import (
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func newShow() *cobra.Command {
+	cmd := commands.New(&cobra.Command{
+		Use:   "show <name>",
+		Short: "Shows the details of an instance",
+		Args:  cobra.ExactArgs(1),
+	}, &showRunner{})
+
+	return cmd.Command
+}
+
+type showRunner struct{}
+
+func (s *showRunner) RunE(cmd *cobra.Command, args []string) error {
+	index, err := loadIndex()
+	if err != nil {
+		return err
+	}
+	entry, err := index.Get(args[0])
+	if err != nil {
+		return indexError(args[0], err)
+	}
+	instance, err := index.Instance(entry.Name)
+	if err != nil {
+		return err
+	}
+
+	lastPlayed := "never"
+	if !entry.LastPlayed.IsZero() {
+		lastPlayed = entry.LastPlayed.Format("2006-01-02 15:04:05")
+	}
+	status := "stopped"
+	if info, err := instance.RunInfo(); err == nil {
+		status = fmt.Sprintf("running (pid %d)", info.PID)
+	}
+
+	fmt.Println(gchalk.Bold(entry.Name))
+	fmt.Printf("  Pack:        %s\n", instance.Name())
+	fmt.Printf("  Platform:    %s\n", instance.Manifest.PlatformString())
+	fmt.Printf("  Minecraft:   %s\n", minecraftVersion(instance))
+	fmt.Printf("  Directory:   %s\n", instance.Directory)
+	fmt.Printf("  Size:        %s\n", commands.HumanSize(commands.DirSize(instance.Directory)))
+	fmt.Printf("  Created:     %s\n", entry.Created.Format("2006-01-02 15:04:05"))
+	fmt.Printf("  Last played: %s\n", lastPlayed)
+	fmt.Printf("  Status:      %s\n", status)
+	return nil
+}
@@ -0,0 +1,54 @@
+package javaCmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func newInstall() *cobra.Command {
+	runner := &installRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:     "install <version>",
+		Short:   "Downloads a Java runtime",
+		Example: "  minepkg java install 17-jre-hotspot\n  minepkg java install 8-jdk\n  minepkg java install 17-jdk-hotspot-zulu",
+		Args:    cobra.ExactArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.force, "force", "f", false, "Download again, even if this version is already installed")
+
+	return cmd.Command
+}
+
+type installRunner struct {
+	force bool
+}
+
+func (i *installRunner) RunE(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	factory, err := newFactory()
+	if err != nil {
+		return err
+	}
+
+	j, err := factory.Version(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	if !j.NeedsDownloading() && !i.force {
+		fmt.Printf("Java %s (%s) is already installed\n", j.Identifier(), j.Version())
+		return nil
+	}
+
+	fmt.Printf("Downloading Java %s …\n", j.Identifier())
+	if err := j.Update(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("Installed Java %s (%s) to %s\n", j.Identifier(), j.Version(), j.Dir())
+
+	return nil
+}
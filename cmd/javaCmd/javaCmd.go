@@ -0,0 +1,27 @@
+package javaCmd
+
+import (
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/java"
+	"github.com/minepkg/minepkg/internals/launcher"
+	"github.com/spf13/cobra"
+)
+
+func New() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "java",
+		Short: "Manage the Java runtimes used to launch Minecraft",
+	}
+
+	cmd.AddCommand(newList())
+	cmd.AddCommand(newInstall())
+	cmd.AddCommand(newRemove())
+	cmd.AddCommand(newUse())
+
+	return cmd
+}
+
+// newFactory returns a java factory using the global java directory
+func newFactory() (*java.Factory, error) {
+	return launcher.NewJavaFactory(instances.New().JavaDir())
+}
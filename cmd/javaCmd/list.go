@@ -0,0 +1,77 @@
+package javaCmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/java"
+	"github.com/spf13/cobra"
+)
+
+func newList() *cobra.Command {
+	runner := &listRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:     "list",
+		Short:   "Lists installed and detected Java runtimes",
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+	}, runner)
+
+	cmd.Flags().BoolVar(&runner.noSystem, "no-system", false, "Do not search for Java runtimes installed on this system")
+
+	return cmd.Command
+}
+
+type listRunner struct {
+	noSystem bool
+}
+
+func (l *listRunner) RunE(cmd *cobra.Command, args []string) error {
+	factory, err := newFactory()
+	if err != nil {
+		return err
+	}
+
+	installed, err := factory.Installed()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(gchalk.Bold("Installed by minepkg"))
+	if len(installed) == 0 {
+		fmt.Println(gchalk.Gray("  none"))
+	}
+	for _, j := range installed {
+		fmt.Printf(
+			"  %-27s %-16s %-10s %8s  %s\n",
+			j.Identifier(),
+			j.Version(),
+			j.Vendor(),
+			commands.HumanSize(commands.DirSize(j.Dir())),
+			gchalk.Gray(j.Dir()),
+		)
+	}
+
+	if l.noSystem {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(gchalk.Bold("Detected on this system"))
+	system := java.DetectSystem(context.Background())
+	if len(system) == 0 {
+		fmt.Println(gchalk.Gray("  none"))
+	}
+	for _, j := range system {
+		fmt.Printf(
+			"  %-27s %-16s %s\n",
+			j.Version,
+			j.Source,
+			gchalk.Gray(j.Bin),
+		)
+	}
+
+	return nil
+}
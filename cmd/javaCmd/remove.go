@@ -0,0 +1,49 @@
+package javaCmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/java"
+	"github.com/spf13/cobra"
+)
+
+func newRemove() *cobra.Command {
+	cmd := commands.New(&cobra.Command{
+		Use:     "remove <version...>",
+		Short:   "Removes installed Java runtimes",
+		Aliases: []string{"rm", "uninstall"},
+		Args:    cobra.MinimumNArgs(1),
+	}, &removeRunner{})
+
+	return cmd.Command
+}
+
+type removeRunner struct{}
+
+func (r *removeRunner) RunE(cmd *cobra.Command, args []string) error {
+	factory, err := newFactory()
+	if err != nil {
+		return err
+	}
+
+	for _, version := range args {
+		err := factory.Remove(version)
+		if errors.Is(err, java.ErrNotInstalled) {
+			return &commands.CliError{
+				Text: fmt.Sprintf("java %s is not installed", version),
+				Suggestions: []string{
+					fmt.Sprintf("Run %s to see all installed versions", gchalk.Bold("minepkg java list")),
+				},
+			}
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed Java %s\n", version)
+	}
+
+	return nil
+}
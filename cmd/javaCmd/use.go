@@ -0,0 +1,85 @@
+package javaCmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/java"
+	"github.com/spf13/cobra"
+)
+
+func newUse() *cobra.Command {
+	runner := &useRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "use [version|system|path]",
+		Short: "Pins the Java runtime used to launch the instance in this directory",
+		Long: `Pins the Java runtime used to launch the instance in the current directory.
+The setting is saved in the .minepkg-local.toml file and is not published.
+Run without arguments to show the current setting.`,
+		Example: "  minepkg java use 17-jre\n  minepkg java use system\n  minepkg java use /usr/lib/jvm/java-17-openjdk/bin/java",
+		Args:    cobra.MaximumNArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVar(&runner.reset, "reset", false, "Remove the pinned runtime and use the default again")
+
+	return cmd.Command
+}
+
+type useRunner struct {
+	reset bool
+}
+
+func (u *useRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	settings, err := instance.LocalSettings()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case u.reset:
+		settings.Java = ""
+	case len(args) == 0:
+		if settings.Java == "" {
+			fmt.Println("No Java runtime pinned. The version required by Minecraft will be used")
+		} else {
+			fmt.Println("Pinned Java runtime: " + settings.Java)
+		}
+		return nil
+	case args[0] == "system":
+		settings.Java = "system"
+	case filepath.IsAbs(args[0]):
+		if _, err := os.Stat(args[0]); err != nil {
+			return err
+		}
+		if _, err := java.ReadSystemJava(context.Background(), args[0]); err != nil {
+			return fmt.Errorf("%s does not seem to be a working java binary: %w", args[0], err)
+		}
+		settings.Java = args[0]
+	default:
+		identifier, err := java.NormalizeVersion(args[0])
+		if err != nil {
+			return err
+		}
+		settings.Java = identifier
+	}
+
+	if err := instance.SaveLocalSettings(settings); err != nil {
+		return err
+	}
+
+	if settings.Java == "" {
+		fmt.Println("Removed pinned Java runtime")
+	} else {
+		fmt.Println("This instance will now be launched with Java " + settings.Java)
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/minepkg/minepkg/internals/globals"
 	"github.com/minepkg/minepkg/internals/instances"
 	"github.com/minepkg/minepkg/internals/launcher"
+	"github.com/minepkg/minepkg/internals/logparser"
 	"github.com/minepkg/minepkg/pkg/manifest"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -26,9 +27,10 @@ import (
 func init() {
 	runner := &launchRunner{}
 	cmd := commands.New(&cobra.Command{
-		Use:   "launch [modpack]",
+		Use:   "launch [instance|modpack]",
 		Short: "Launch the given or local modpack.",
-		Long: `If a modpack name or URL is supplied, that modpack will be launched.
+		Long: `If an instance name (see "minepkg instance list") is supplied, that instance will be launched.
+If a modpack name or URL is supplied, that modpack will be launched.
 Alternatively: Can be used in directories containing a minepkg.toml manifest to launch that modpack.
 		`,
 		Aliases: []string{"run", "start", "play"},
@@ -43,6 +45,16 @@ Alternatively: Can be used in directories containing a minepkg.toml manifest to
 	cmd.Flags().BoolVar(&runner.crashTest, "crashtest", false, "Stop server after it's online (can be used for testing)")
 	cmd.Flags().BoolVar(&runner.noBuild, "no-build", false, "Skip build (if any)")
 	cmd.Flags().BoolVar(&runner.clean, "clean", false, "Removes any instance data except for savegames")
+	cmd.Flags().StringVar(&runner.logFormat, "log-format", "", "Parse the Minecraft output and print it as \"text\" or \"json\" lines")
+	cmd.Flags().StringVar(&runner.logLevel, "log-level", "", "Only print Minecraft output with this level or higher (trace, debug, info, warn, error, fatal)")
+	cmd.Flags().StringArrayVar(&runner.logTags, "log-tag", nil, "Only print Minecraft output with this tag (can be used multiple times)")
+	cmd.Flags().DurationVar(&runner.backupEvery, "backup-every", 0, "Backup the worlds in this interval while the server runs (server only, eg. 6h)")
+	cmd.Flags().BoolVar(&runner.supervise, "supervise", false, "Restart the server after crashes and stop it gracefully on ctrl-c (server only)")
+	cmd.Flags().DurationVar(&runner.stopTimeout, "stop-timeout", time.Minute, "Time the supervised server gets to stop before it is killed")
+	cmd.Flags().IntVar(&runner.maxCrashes, "max-crashes", 5, "Stop restarting the supervised server after this many crashes within 10 minutes")
+	cmd.Flags().StringVar(&runner.account, "account", "", "Launch with this account instead of the default one (see \"minepkg account list\")")
+	cmd.Flags().StringVar(&runner.offlinePlayer, "offline-player", "", "Launch the client as this offline player without logging in (singleplayer and offline servers only)")
+	cmd.Flags().BoolVarP(&runner.detach, "detach", "d", false, "Start in the background. Use \"minepkg ps\", \"minepkg logs\" and \"minepkg stop\" to manage it")
 	runner.overwrites = launcher.CmdOverwriteFlags(cmd.Command)
 
 	rootCmd.AddCommand(cmd.Command)
@@ -57,6 +69,18 @@ type launchRunner struct {
 	noBuild     bool
 	forceUpdate bool
 	clean       bool
+	detach      bool
+	backupEvery time.Duration
+	supervise   bool
+	stopTimeout time.Duration
+	maxCrashes  int
+	account     string
+
+	offlinePlayer string
+
+	logFormat string
+	logLevel  string
+	logTags   []string
 
 	overwrites *launcher.OverwriteFlags
 
@@ -85,7 +109,7 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	} else {
-		l.instance, err = l.instanceFromModpack(args[0])
+		l.instance, err = l.instanceFromArg(args[0])
 		if err != nil {
 			return err
 		}
@@ -94,6 +118,30 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 	switch {
 	case l.crashTest && !l.serverMode:
 		logger.Fail("Can only crashtest servers. append --server to crashtest")
+	case l.crashTest && l.detach:
+		logger.Fail("Can not crashtest detached instances")
+	case l.backupEvery != 0 && !l.serverMode:
+		logger.Fail("Can only schedule backups for servers. append --server to schedule backups")
+	case l.backupEvery != 0 && l.detach:
+		return &commands.CliError{
+			Text: "--backup-every can not be used with --detach",
+			Suggestions: []string{
+				fmt.Sprintf("Run %s regularly (with cron for example) instead", gchalk.Bold("minepkg backup create")),
+			},
+		}
+	case l.supervise && !l.serverMode:
+		logger.Fail("Can only supervise servers. append --server to supervise")
+	case l.supervise && (l.detach || l.crashTest):
+		return &commands.CliError{
+			Text: "--supervise can not be used with --detach or --crashtest",
+			Suggestions: []string{
+				"Use a service manager like systemd to run supervised servers in the background",
+			},
+		}
+	case l.offlinePlayer != "" && l.serverMode:
+		logger.Fail("Can only launch clients as offline player. Use --offline to start the server in offline mode")
+	case l.offlinePlayer != "" && l.account != "":
+		logger.Fail("Can not use --offline-player and --account together")
 	case l.instance.Manifest.PlatformString() == "forge":
 		logger.Fail("Can not launch forge modpacks for now. Sorry.")
 	}
@@ -101,7 +149,7 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 	// we need login credentials to launch the client
 	// the server needs no creds
 	if !l.serverMode {
-		creds, err := root.getLaunchCredentialsOrLogin()
+		creds, err := l.launchCredentials()
 		if err != nil {
 			return err
 		}
@@ -134,6 +182,14 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 		MinepkgVersion: rootCmd.Version,
 		NonInteractive: viper.GetBool("nonInteractive"),
 		UseSystemJava:  viper.GetBool("useSystemJava"),
+		BackupInterval: l.backupEvery,
+	}
+
+	if l.supervise {
+		cliLauncher.Supervise = &launcher.SuperviseOptions{
+			StopTimeout: l.stopTimeout,
+			MaxCrashes:  l.maxCrashes,
+		}
 	}
 
 	cliLauncher.ApplyOverWrites(l.overwrites)
@@ -167,6 +223,28 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 		Server:         l.serverMode,
 		Debug:          l.debugMode,
 		RamMiB:         l.overwrites.Ram,
+		JVMArgs:        l.overwrites.JVMArgs,
+	}
+
+	logWriter, err := l.logWriter()
+	if err != nil {
+		return err
+	}
+	if logWriter != nil {
+		defer logWriter.Close()
+		opts.Stdout = logWriter
+		opts.Stderr = logWriter
+	}
+
+	if l.detach {
+		info, err := cliLauncher.RunDetached(opts)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Started %s in the background (pid %d)\n", info.Name, info.PID)
+		fmt.Printf("  Logs: %s\n", gchalk.Bold("minepkg logs -f "+info.Name))
+		fmt.Printf("  Stop: %s\n", gchalk.Bold("minepkg stop "+info.Name))
+		return nil
 	}
 
 	launchErr := make(chan error)
@@ -226,6 +304,31 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// logWriter returns a writer that parses and filters the Minecraft output.
+// Returns nil if no log flag was used
+func (l *launchRunner) logWriter() (*logparser.Writer, error) {
+	if l.logFormat == "" && l.logLevel == "" && len(l.logTags) == 0 {
+		return nil, nil
+	}
+	if l.detach {
+		return nil, &commands.CliError{
+			Text: "the log flags can not be used with --detach",
+			Suggestions: []string{
+				fmt.Sprintf("Use %s to read the output of detached instances", gchalk.Bold("minepkg logs")),
+			},
+		}
+	}
+
+	writer, err := logparser.NewWriter(os.Stdout, l.logFormat, &logparser.Filter{
+		MinLevel: l.logLevel,
+		Tags:     l.logTags,
+	})
+	if err != nil {
+		return nil, &commands.CliError{Text: err.Error()}
+	}
+	return writer, nil
+}
+
 func crashTest() error {
 	tries := 0
 
@@ -259,6 +362,42 @@ func crashTest() error {
 	}
 }
 
+// launchCredentials returns the credentials of the offline player or of the selected account
+func (l *launchRunner) launchCredentials() (*instances.LaunchCredentials, error) {
+	player := l.offlinePlayer
+	if player == "" && l.account == "" {
+		if settings, err := l.instance.LocalSettings(); err == nil && settings.Dev != nil {
+			player = settings.Dev.OfflinePlayer
+		}
+	}
+	if player != "" {
+		return root.offlineCredentials(player)
+	}
+
+	if err := root.selectAccount(l.instance, l.account); err != nil {
+		return nil, err
+	}
+	return root.getLaunchCredentialsOrLogin()
+}
+
+// instanceFromArg returns the instance with the given name. Otherwise the modpack with that name is fetched
+func (l *launchRunner) instanceFromArg(arg string) (*instances.Instance, error) {
+	index, err := instances.LoadIndex(instances.New().InstancesDir())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := index.Get(arg); err != nil {
+		return l.instanceFromModpack(arg)
+	}
+
+	instance, err := index.Instance(arg)
+	if err != nil {
+		return nil, err
+	}
+	instance.MinepkgAPI = globals.ApiClient
+	return instance, nil
+}
+
 func (l *launchRunner) instanceFromModpack(modpack string) (*instances.Instance, error) {
 	apiClient := globals.ApiClient
 
@@ -2,13 +2,16 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/manifoldco/promptui"
 	"github.com/minepkg/minepkg/internals/auth"
+	"github.com/minepkg/minepkg/internals/commands"
 	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/minecraft"
 	"github.com/minepkg/minepkg/internals/minecraft/microsoft"
 	"github.com/minepkg/minepkg/internals/minecraft/mojang"
 	"github.com/spf13/cobra"
@@ -16,10 +19,56 @@ import (
 )
 
 func init() {
+	loginCmd.Flags().Bool("device", false, "Sign in with a code on another device instead of opening a browser (for SSH sessions)")
 	rootCmd.AddCommand(loginCmd)
 }
 
+// useAccount selects the account used to launch Minecraft. An empty name selects the default account
+func (r *Root) useAccount(name string) error {
+	accounts, err := auth.LoadAccounts(r.globalDir)
+	if err != nil {
+		return fmt.Errorf("could not read accounts: %w", err)
+	}
+	r.accounts = accounts
+
+	account, err := accounts.Get(name)
+	if err != nil {
+		// no account yet, login creates one
+		if name == "" {
+			return nil
+		}
+		return accountError(name, err)
+	}
+
+	r.account = account
+	r.minecraftAuthStore = accounts.Store(account)
+	r.authProvider = nil
+	return nil
+}
+
+// selectAccount selects the account used to launch instance. name wins over the `account` local setting
+func (r *Root) selectAccount(instance *instances.Instance, name string) error {
+	if name == "" {
+		if settings, err := instance.LocalSettings(); err == nil {
+			name = settings.Account
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	return r.useAccount(name)
+}
+
 func (r *Root) restoreAuth() {
+	if r.account == nil {
+		if err := r.useAccount(""); err != nil {
+			log.Println("Failed to restore auth data:", err)
+		}
+		if r.account == nil {
+			log.Println("No account to restore found")
+			return
+		}
+	}
 	authStore := r.minecraftAuthStore
 
 	authData := &auth.PersistentCredentials{}
@@ -101,13 +150,59 @@ func (r *Root) getLaunchCredentialsOrLogin() (*instances.LaunchCredentials, erro
 	}
 
 	return &instances.LaunchCredentials{
+		Account:     r.account.Name,
+		PlayerName:  creds.GetPlayerName(),
+		UUID:        creds.GetUUID(),
+		AccessToken: creds.GetAccessToken(),
+	}, nil
+}
+
+// offlineCredentials returns the credentials of an offline player that needs no login
+func (r *Root) offlineCredentials(player string) (*instances.LaunchCredentials, error) {
+	r.authProvider = &auth.Offline{PlayerName: player}
+	creds, err := r.authProvider.LaunchAuthData()
+	if errors.Is(err, minecraft.ErrInvalidPlayerName) {
+		return nil, &commands.CliError{
+			Text:        fmt.Sprintf("%s can not be used as player name", player),
+			Suggestions: []string{"Use 3 to 16 letters, numbers and _"},
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &instances.LaunchCredentials{
+		Account:     "offline",
 		PlayerName:  creds.GetPlayerName(),
 		UUID:        creds.GetUUID(),
 		AccessToken: creds.GetAccessToken(),
 	}, nil
 }
 
+// ensureAccount selects the default account or creates it on the first login
+func (r *Root) ensureAccount() error {
+	if r.account == nil {
+		if err := r.useAccount(""); err != nil {
+			return err
+		}
+	}
+	// first login ever
+	if r.account == nil {
+		account, err := r.accounts.Add("default")
+		if err != nil {
+			return err
+		}
+		r.account = account
+		r.minecraftAuthStore = r.accounts.Store(account)
+	}
+	return nil
+}
+
 func (r *Root) login() error {
+	if err := r.ensureAccount(); err != nil {
+		return err
+	}
+
 	methodP := promptui.Select{
 		Label: "Please choose a login method",
 		Items: []string{"Microsoft Account (Opens in Browser)", "Mojang Account (Email & Password)"},
@@ -126,7 +221,7 @@ func (r *Root) login() error {
 			return fmt.Errorf("ms login failed: %w", err)
 		}
 
-		return nil
+		return r.saveAccount("microsoft")
 	}
 
 	// Mojang login
@@ -138,7 +233,30 @@ func (r *Root) login() error {
 
 	fmt.Println("Login successful")
 
-	return nil
+	return r.saveAccount("mojang")
+}
+
+// deviceLogin signs in with a Microsoft account on another device. Works without a browser (over SSH for example)
+func (r *Root) deviceLogin() error {
+	if err := r.ensureAccount(); err != nil {
+		return err
+	}
+
+	r.useMicrosoftAuth().Device = true
+	if err := r.authProvider.Prompt(); err != nil {
+		return fmt.Errorf("ms login failed: %w", err)
+	}
+	return r.saveAccount("microsoft")
+}
+
+// saveAccount stores the profile of the logged in account
+func (r *Root) saveAccount(provider string) error {
+	r.account.Provider = provider
+	if creds, err := r.authProvider.LaunchAuthData(); err == nil {
+		r.account.PlayerName = creds.GetPlayerName()
+		r.account.UUID = creds.GetUUID()
+	}
+	return r.accounts.Save()
 }
 
 var loginCmd = &cobra.Command{
@@ -147,7 +265,11 @@ var loginCmd = &cobra.Command{
 	Short:   "Sign in with Microsoft or Mojang in order to start Minecraft",
 	Args:    cobra.ExactArgs(0),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := root.login()
+		login := root.login
+		if device, _ := cmd.Flags().GetBool("device"); device {
+			login = root.deviceLogin
+		}
+		err := login()
 		if err != nil {
 			return err
 		}
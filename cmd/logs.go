@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &logsRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "logs [instance]",
+		Short: "Shows the output of an instance running in the background",
+		Long: `Shows the output of an instance that was started with "minepkg launch --detach".
+The instance can be a name or pid as listed by "minepkg ps". Defaults to the instance in the current directory.`,
+		Example: "  minepkg logs -f my-modpack",
+		Args:    cobra.MaximumNArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.follow, "follow", "f", false, "Keep printing new output until the instance stops")
+	cmd.Flags().IntVarP(&runner.tail, "tail", "n", 0, "Only show the last n lines (0 shows everything)")
+
+	rootCmd.AddCommand(cmd.Command)
+}
+
+type logsRunner struct {
+	follow bool
+	tail   int
+}
+
+func (l *logsRunner) RunE(cmd *cobra.Command, args []string) error {
+	logFile, info, err := l.findLogFile(args)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(logFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := printTail(f, l.tail); err != nil {
+		return err
+	}
+
+	if !l.follow || info == nil {
+		return nil
+	}
+
+	// poll for new output until the instance stopped
+	reader := bufio.NewReader(f)
+	for {
+		if _, err := io.Copy(os.Stdout, reader); err != nil {
+			return err
+		}
+		if !info.Alive() {
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// findLogFile returns the log file of the running instance. Falls back to the log
+// of the last detached launch if the instance is not running anymore
+func (l *logsRunner) findLogFile(args []string) (string, *instances.RunInfo, error) {
+	info, err := findRunning(args)
+	if err == nil {
+		return info.LogFile, info, nil
+	}
+
+	var dir string
+	if len(args) == 0 {
+		instance, localErr := root.LocalInstance()
+		if localErr != nil {
+			return "", nil, localErr
+		}
+		dir = instance.Directory
+	} else {
+		matches, _ := filepath.Glob(filepath.Join(instances.New().InstancesDir(), args[0]+"_*"))
+		if len(matches) != 1 {
+			return "", nil, err
+		}
+		dir = matches[0]
+	}
+
+	logFile := filepath.Join(dir, "minepkg.log")
+	if _, statErr := os.Stat(logFile); statErr != nil {
+		return "", nil, err
+	}
+	if l.follow {
+		fmt.Fprintln(os.Stderr, "Instance is not running. Showing the output of the last detached launch")
+	}
+	return logFile, nil, nil
+}
+
+// printTail prints the last n lines of r. It prints everything if n is 0
+func printTail(r io.Reader, n int) error {
+	if n <= 0 {
+		_, err := io.Copy(os.Stdout, r)
+		return err
+	}
+
+	lines := make([]string, 0, n)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, scanner.Text())
+	}
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	return scanner.Err()
+}
@@ -450,28 +450,16 @@ func (s *statsCollector) Watch(p *os.Process) {
 	}
 
 	collect := func() {
-		v, _ := mem.VirtualMemory()
-		memP, _ := monitor.MemoryPercent()
-		memInfo, _ := monitor.MemoryInfo()
-		// cpuP, e := monitor.CPUPercent()
-		// cpuT, _ := monitor.Times()
-		cpuWAT, _ := monitor.Percent(time.Millisecond * 1000)
-		// cpuG, _ := cpu.Times(false)
-
+		stats := sampleStats(monitor, time.Millisecond*1000)
 		// should probably check errors, but eh
-		if memInfo == nil {
+		if stats == nil {
 			return
 		}
 
-		s.Thing.State.Stats.Memory = append(s.Thing.State.Stats.Memory, float32(memInfo.RSS/1024/1024))
-		s.Thing.State.Stats.CPU = append(s.Thing.State.Stats.CPU, float32(cpuWAT))
+		s.Thing.State.Stats.Memory = append(s.Thing.State.Stats.Memory, stats.ProcessMemoryMiB)
+		s.Thing.State.Stats.CPU = append(s.Thing.State.Stats.CPU, float32(stats.ProcessCPUPercent))
 
-		s.Thing.Send("GameStats", &StatsEvent{
-			Memory:               v,
-			ProcessMemoryPercent: memP,
-			ProcessMemoryMiB:     float32(memInfo.RSS / 1024 / 1024),
-			ProcessCPUPercent:    cpuWAT,
-		})
+		s.Thing.Send("GameStats", stats)
 	}
 
 	for {
@@ -489,6 +477,26 @@ func (s *statsCollector) Stop() {
 	close(s.stop)
 }
 
+// sampleStats collects memory & cpu usage of the given process. The cpu usage is measured over `interval`.
+// Returns nil if the process info could not be read
+func sampleStats(monitor *process.Process, interval time.Duration) *StatsEvent {
+	v, _ := mem.VirtualMemory()
+	memP, _ := monitor.MemoryPercent()
+	memInfo, _ := monitor.MemoryInfo()
+	cpuWAT, _ := monitor.Percent(interval)
+
+	if memInfo == nil {
+		return nil
+	}
+
+	return &StatsEvent{
+		Memory:               v,
+		ProcessMemoryPercent: memP,
+		ProcessMemoryMiB:     float32(memInfo.RSS / 1024 / 1024),
+		ProcessCPUPercent:    cpuWAT,
+	}
+}
+
 func newInstanceFromManifest(release *manifest.Manifest) (*instances.Instance, error) {
 	// set instance details
 	instance := instances.New()
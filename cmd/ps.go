@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &psRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "ps",
+		Short: "Lists instances running in the background",
+		Long:  `Lists all instances that were started with "minepkg launch --detach"`,
+		Args:  cobra.NoArgs,
+	}, runner)
+
+	rootCmd.AddCommand(cmd.Command)
+}
+
+type psRunner struct{}
+
+func (p *psRunner) RunE(cmd *cobra.Command, args []string) error {
+	running, err := instances.New().Running()
+	if err != nil {
+		return err
+	}
+
+	if len(running) == 0 {
+		fmt.Println("No instances are running in the background")
+		return nil
+	}
+
+	// cpu usage is measured over a second, so we do all of them at once
+	stats := make([]*StatsEvent, len(running))
+	var wg sync.WaitGroup
+	for i, info := range running {
+		wg.Add(1)
+		go func(i int, info *instances.RunInfo) {
+			defer wg.Done()
+			if monitor, err := info.Process(); err == nil {
+				stats[i] = sampleStats(monitor, time.Second)
+			}
+		}(i, info)
+	}
+	wg.Wait()
+
+	fmt.Println(gchalk.Bold(fmt.Sprintf("%-24s %-8s %-7s %-10s %9s %6s", "NAME", "PID", "TYPE", "UPTIME", "MEMORY", "CPU")))
+	for i, info := range running {
+		kind := "client"
+		if info.Server {
+			kind = "server"
+		}
+		memory, cpu := "-", "-"
+		if stats[i] != nil {
+			memory = fmt.Sprintf("%.0f MiB", stats[i].ProcessMemoryMiB)
+			cpu = fmt.Sprintf("%.0f%%", stats[i].ProcessCPUPercent)
+		}
+		fmt.Printf(
+			"%-24s %-8d %-7s %-10s %9s %6s\n",
+			info.Name,
+			info.PID,
+			kind,
+			info.Uptime().Round(time.Second),
+			memory,
+			cpu,
+		)
+	}
+
+	return nil
+}
+
+// findRunning returns the running instance matching the name, directory name or pid.
+// The instance in the current directory is used if no name is given
+func findRunning(args []string) (*instances.RunInfo, error) {
+	if len(args) == 0 {
+		instance, err := root.LocalInstance()
+		if err != nil {
+			return nil, err
+		}
+		info, err := instance.RunInfo()
+		if err == instances.ErrNotRunning {
+			return nil, errNotRunning(instance.Name())
+		}
+		return info, err
+	}
+
+	running, err := instances.New().Running()
+	if err != nil {
+		return nil, err
+	}
+
+	query := args[0]
+	matches := make([]*instances.RunInfo, 0, 1)
+	for _, info := range running {
+		if info.Name == query || filepath.Base(info.Directory) == query || strconv.Itoa(info.PID) == query {
+			matches = append(matches, info)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, errNotRunning(query)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, &commands.CliError{
+			Text: fmt.Sprintf("%s matches multiple running instances", query),
+			Suggestions: []string{
+				fmt.Sprintf("Use the pid instead. Run %s to list them", gchalk.Bold("minepkg ps")),
+			},
+		}
+	}
+}
+
+func errNotRunning(name string) error {
+	return &commands.CliError{
+		Text: fmt.Sprintf("%s is not running in the background", name),
+		Suggestions: []string{
+			fmt.Sprintf("Run %s to list all running instances", gchalk.Bold("minepkg ps")),
+			fmt.Sprintf("Start it with %s", gchalk.Bold("minepkg launch --detach")),
+		},
+	}
+}
@@ -12,10 +12,14 @@ import (
 	"strings"
 
 	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/cmd/backupCmd"
 	"github.com/minepkg/minepkg/cmd/bump"
 	"github.com/minepkg/minepkg/cmd/config"
+	"github.com/minepkg/minepkg/cmd/crashCmd"
 	"github.com/minepkg/minepkg/cmd/dev"
 	"github.com/minepkg/minepkg/cmd/initCmd"
+	"github.com/minepkg/minepkg/cmd/instanceCmd"
+	"github.com/minepkg/minepkg/cmd/javaCmd"
 	"github.com/minepkg/minepkg/internals/api"
 	"github.com/minepkg/minepkg/internals/auth"
 	"github.com/minepkg/minepkg/internals/cmdlog"
@@ -44,6 +48,8 @@ type Root struct {
 	HTTPClient         *http.Client
 	MinepkgAPI         *api.MinepkgAPI
 	authProvider       auth.AuthProvider
+	accounts           *auth.Accounts
+	account            *auth.Account
 	minecraftAuthStore *credentials.Store
 	minepkgAuthStore   *credentials.Store
 	globalDir          string
@@ -168,6 +174,10 @@ func initRoot() {
 	rootCmd.AddCommand(config.SubCmd)
 	rootCmd.AddCommand(initCmd.New())
 	rootCmd.AddCommand(bump.New())
+	rootCmd.AddCommand(javaCmd.New())
+	rootCmd.AddCommand(crashCmd.New())
+	rootCmd.AddCommand(backupCmd.New())
+	rootCmd.AddCommand(instanceCmd.New())
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -216,6 +226,10 @@ func initConfig() {
 		globals.ApiClient.APIUrl = viper.GetString("apiUrl")
 	}
 
+	if viper.GetString("curseforge.apiKey") != "" {
+		globals.CurseForgeClient.APIKey = viper.GetString("curseforge.apiKey")
+	}
+
 	homeConfigs, err := os.UserConfigDir()
 	if err != nil {
 		panic(err)
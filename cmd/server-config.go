@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Reads and changes the server.properties of a server",
+		Long: `Reads and changes the server.properties of a server. Comments and the order of the file are kept.
+Properties can also be set in the [server.properties] table of the minepkg.toml, they are applied on every launch.`,
+	}
+	configCmd.PersistentFlags().String("instance", "", "Name, pid or directory of the instance (defaults to the current directory)")
+
+	getCmd := commands.New(&cobra.Command{
+		Use:   "get [key]",
+		Short: "Prints a property or all properties if no key is given",
+		Args:  cobra.MaximumNArgs(1),
+	}, &serverConfigGetRunner{})
+
+	setCmd := commands.New(&cobra.Command{
+		Use:     "set <key> <value>",
+		Short:   "Sets a property",
+		Example: `  minepkg server config set motd "Welcome = have fun"`,
+		Args:    cobra.ExactArgs(2),
+	}, &serverConfigSetRunner{})
+
+	unsetCmd := commands.New(&cobra.Command{
+		Use:   "unset <key>",
+		Short: "Removes a property, so the server uses its default",
+		Args:  cobra.ExactArgs(1),
+	}, &serverConfigUnsetRunner{})
+
+	configCmd.AddCommand(getCmd.Command, setCmd.Command, unsetCmd.Command)
+	serverCmd.AddCommand(configCmd)
+}
+
+type serverConfigGetRunner struct{}
+
+func (s *serverConfigGetRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+	props, err := instance.ServerProperties()
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		for _, key := range props.Keys() {
+			fmt.Printf("%s=%s\n", key, props.Value(key))
+		}
+		return nil
+	}
+
+	value, ok := props.Get(args[0])
+	if !ok {
+		return &commands.CliError{
+			Text: fmt.Sprintf("%s is not set", args[0]),
+			Suggestions: []string{
+				fmt.Sprintf("Run %s to list all properties", gchalk.Bold("minepkg server config get")),
+			},
+		}
+	}
+	fmt.Println(value)
+	return nil
+}
+
+type serverConfigSetRunner struct{}
+
+func (s *serverConfigSetRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+	props, err := instance.ServerProperties()
+	if err != nil {
+		return err
+	}
+
+	props.Set(args[0], args[1])
+	if err := instance.SaveServerProperties(props); err != nil {
+		return err
+	}
+	printConfigHints(instance, args[0])
+	return nil
+}
+
+type serverConfigUnsetRunner struct{}
+
+func (s *serverConfigUnsetRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+	props, err := instance.ServerProperties()
+	if err != nil {
+		return err
+	}
+
+	if !props.Unset(args[0]) {
+		fmt.Printf("%s was not set\n", args[0])
+		return nil
+	}
+	if err := instance.SaveServerProperties(props); err != nil {
+		return err
+	}
+	printConfigHints(instance, args[0])
+	return nil
+}
+
+func instanceFlag(cmd *cobra.Command) string {
+	query, _ := cmd.Flags().GetString("instance")
+	return query
+}
+
+// printConfigHints tells the user when a changed property will not have an effect right away
+func printConfigHints(instance *instances.Instance, key string) {
+	if _, ok := instance.Manifest.Server.FlatProperties()[key]; ok {
+		fmt.Println(gchalk.Yellow(fmt.Sprintf("%s is also set in the minepkg.toml and will be overwritten on the next launch", key)))
+	}
+	if _, err := instance.RunInfo(); err == nil {
+		fmt.Println(gchalk.Gray("The server is running. Restart it to apply the change"))
+	}
+}
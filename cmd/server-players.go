@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/minecraft"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	whitelistCmd := &cobra.Command{
+		Use:   "whitelist",
+		Short: "Manages the whitelist of a server",
+		Long: `Manages the whitelist.json of a server. Names are resolved to UUIDs with the Mojang API
+(configure another one with the "server.profileApi" config key) or derived from the name for servers with online-mode=false.
+Changes are sent to the server over RCON if it is running.`,
+	}
+	whitelistCmd.PersistentFlags().String("instance", "", "Name, pid or directory of the instance (defaults to the current directory)")
+	whitelistCmd.AddCommand(
+		commands.New(&cobra.Command{
+			Use:   "add <player...>",
+			Short: "Adds players to the whitelist",
+			Args:  cobra.MinimumNArgs(1),
+		}, &whitelistAddRunner{}).Command,
+		commands.New(&cobra.Command{
+			Use:   "remove <player...>",
+			Short: "Removes players from the whitelist",
+			Args:  cobra.MinimumNArgs(1),
+		}, &whitelistRemoveRunner{}).Command,
+		commands.New(&cobra.Command{
+			Use:   "list",
+			Short: "Lists all whitelisted players",
+			Args:  cobra.NoArgs,
+		}, &whitelistListRunner{}).Command,
+	)
+
+	opCmd := &cobra.Command{
+		Use:   "op",
+		Short: "Manages the operators of a server",
+		Long: `Manages the ops.json of a server. Names are resolved like for "minepkg server whitelist".
+Changes are sent to the server over RCON if it is running.`,
+	}
+	opCmd.PersistentFlags().String("instance", "", "Name, pid or directory of the instance (defaults to the current directory)")
+	opAdd := commands.New(&cobra.Command{
+		Use:   "add <player...>",
+		Short: "Makes players operators",
+		Args:  cobra.MinimumNArgs(1),
+	}, &opAddRunner{})
+	opAdd.Flags().Int("level", 0, "Permission level from 1 to 4 (defaults to op-permission-level). Running servers apply it after a restart")
+	opAdd.Flags().Bool("bypass-player-limit", false, "Allows the operators to join when the server is full")
+	opCmd.AddCommand(
+		opAdd.Command,
+		commands.New(&cobra.Command{
+			Use:   "remove <player...>",
+			Short: "Removes operators",
+			Args:  cobra.MinimumNArgs(1),
+		}, &opRemoveRunner{}).Command,
+		commands.New(&cobra.Command{
+			Use:   "list",
+			Short: "Lists all operators",
+			Args:  cobra.NoArgs,
+		}, &opListRunner{}).Command,
+	)
+
+	serverCmd.AddCommand(whitelistCmd, opCmd)
+}
+
+type whitelistAddRunner struct{}
+
+func (w *whitelistAddRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range args {
+		player, err := resolvePlayer(cmd.Context(), instance, name)
+		if err != nil {
+			return err
+		}
+		if err := instance.AddToWhitelist(player); err != nil {
+			return err
+		}
+		fmt.Printf("Added %s (%s) to the whitelist\n", player.Name, gchalk.Gray(player.UUID))
+	}
+
+	notifyServer(instance, "whitelist reload")
+	return nil
+}
+
+type whitelistRemoveRunner struct{}
+
+func (w *whitelistRemoveRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range args {
+		removed, err := instance.RemoveFromWhitelist(name)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			fmt.Printf("%s is not whitelisted\n", name)
+			continue
+		}
+		fmt.Printf("Removed %s from the whitelist\n", name)
+	}
+
+	notifyServer(instance, "whitelist reload")
+	return nil
+}
+
+type whitelistListRunner struct{}
+
+func (w *whitelistListRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+	players, err := instance.Whitelist()
+	if err != nil {
+		return err
+	}
+
+	if len(players) == 0 {
+		fmt.Println("The whitelist is empty")
+		return nil
+	}
+	for _, p := range players {
+		fmt.Printf("%-16s %s\n", p.Name, gchalk.Gray(p.UUID))
+	}
+	return nil
+}
+
+type opAddRunner struct{}
+
+func (o *opAddRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+
+	level, _ := cmd.Flags().GetInt("level")
+	bypass, _ := cmd.Flags().GetBool("bypass-player-limit")
+	defaultLevel := defaultOpLevel(instance)
+	if level == 0 {
+		level = defaultLevel
+	}
+	custom := level != defaultLevel || bypass
+	if level < 1 || level > 4 {
+		return &commands.CliError{Text: "the op level has to be between 1 and 4"}
+	}
+
+	for _, name := range args {
+		player, err := resolvePlayer(cmd.Context(), instance, name)
+		if err != nil {
+			return err
+		}
+		op := &minecraft.Op{UUID: player.UUID, Name: player.Name, Level: level, BypassesPlayerLimit: bypass}
+		if err := instance.AddOp(op); err != nil {
+			return err
+		}
+		fmt.Printf("Made %s (%s) an operator with level %d\n", player.Name, gchalk.Gray(player.UUID), level)
+		if notifyServer(instance, "op "+player.Name) && custom {
+			// the server adds ops with the default level and rewrites the ops.json
+			if err := instance.AddOp(op); err != nil {
+				return err
+			}
+			fmt.Println(gchalk.Yellow("The running server uses the default op level until it is restarted"))
+		}
+	}
+	return nil
+}
+
+type opRemoveRunner struct{}
+
+func (o *opRemoveRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range args {
+		removed, err := instance.RemoveOp(name)
+		if err != nil {
+			return err
+		}
+		if removed == nil {
+			fmt.Printf("%s is no operator\n", name)
+			continue
+		}
+		fmt.Printf("%s is no operator anymore\n", removed.Name)
+		// name could be a UUID, the command needs the name
+		notifyServer(instance, "deop "+removed.Name)
+	}
+	return nil
+}
+
+type opListRunner struct{}
+
+func (o *opListRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := findServer(instanceFlag(cmd))
+	if err != nil {
+		return err
+	}
+	ops, err := instance.Ops()
+	if err != nil {
+		return err
+	}
+
+	if len(ops) == 0 {
+		fmt.Println("There are no operators")
+		return nil
+	}
+	for _, op := range ops {
+		fmt.Printf("%-16s level %d %s\n", op.Name, op.Level, gchalk.Gray(op.UUID))
+	}
+	return nil
+}
+
+// resolvePlayer resolves name to a player, see `Instance.ResolvePlayer`
+func resolvePlayer(ctx context.Context, instance *instances.Instance, name string) (*minecraft.Player, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	lookup := &minecraft.ProfileLookup{URL: viper.GetString("server.profileApi"), HTTP: root.HTTPClient}
+	player, err := instance.ResolvePlayer(ctx, lookup, name)
+	switch {
+	case errors.Is(err, minecraft.ErrProfileNotFound):
+		return nil, &commands.CliError{
+			Text:        fmt.Sprintf("there is no Minecraft account named %s", name),
+			Suggestions: []string{"Check the spelling of the name"},
+		}
+	case errors.Is(err, minecraft.ErrInvalidPlayerName):
+		return nil, &commands.CliError{Text: fmt.Sprintf("%s: %s", name, err)}
+	case err != nil:
+		return nil, fmt.Errorf("could not look up %s: %w", name, err)
+	}
+	return player, nil
+}
+
+// defaultOpLevel returns the op-permission-level of the server
+func defaultOpLevel(instance *instances.Instance) int {
+	props, err := instance.ServerProperties()
+	if err != nil {
+		return 4
+	}
+	level, err := strconv.Atoi(props.Value("op-permission-level"))
+	if err != nil || level < 1 || level > 4 {
+		return 4
+	}
+	return level
+}
+
+// notifyServer sends command to the server over RCON if it is running, so the change is applied right away.
+// Returns true if the server executed the command
+func notifyServer(instance *instances.Instance, command string) bool {
+	info, err := instance.RunInfo()
+	if err != nil {
+		// servers started with "minepkg launch" have RCON enabled as well
+		info, err = instance.ForegroundRunInfo()
+	}
+	if err != nil || !info.Server {
+		return false
+	}
+	client, err := instance.DialRcon(5 * time.Second)
+	if err != nil {
+		fmt.Println(gchalk.Yellow("The server is running but RCON is not available. Restart it to apply the change"))
+		return false
+	}
+	defer client.Close()
+	res, err := client.Exec(command)
+	if err != nil {
+		fmt.Println(gchalk.Yellow("Could not apply the change to the running server: " + err.Error()))
+		return false
+	}
+	if res != "" {
+		fmt.Println(gchalk.Gray(res))
+	}
+	return true
+}
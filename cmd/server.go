@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/rcon"
+	"github.com/spf13/cobra"
+)
+
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Manage Minecraft servers started with minepkg",
+}
+
+func init() {
+	execCmd := commands.New(&cobra.Command{
+		Use:   "exec [instance] <command>",
+		Short: "Runs a command on a running server",
+		Long: `Runs a command on a running server using RCON and prints the response.
+The instance can be a name or pid as listed by "minepkg ps" or a path to an instance directory.
+Defaults to the instance in the current directory. Quote commands with spaces.`,
+		Example: `  minepkg server exec "say hi"
+  minepkg server exec my-pack "whitelist add Notch"`,
+		Args: cobra.RangeArgs(1, 2),
+	}, &serverExecRunner{})
+
+	consoleCmd := commands.New(&cobra.Command{
+		Use:   "console [instance]",
+		Short: "Opens an interactive console to a running server",
+		Long:  `Opens an interactive RCON console to a running server. Type "exit" or press ctrl-d to leave.`,
+		Args:  cobra.MaximumNArgs(1),
+	}, &serverConsoleRunner{})
+
+	serverCmd.AddCommand(execCmd.Command)
+	serverCmd.AddCommand(consoleCmd.Command)
+	rootCmd.AddCommand(serverCmd)
+}
+
+type serverExecRunner struct{}
+
+func (s *serverExecRunner) RunE(cmd *cobra.Command, args []string) error {
+	query := ""
+	if len(args) == 2 {
+		query = args[0]
+	}
+	client, _, err := dialServer(query)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	res, err := client.Exec(args[len(args)-1])
+	if err != nil {
+		return err
+	}
+	if res != "" {
+		fmt.Println(strings.TrimRight(res, "\n"))
+	}
+	return nil
+}
+
+type serverConsoleRunner struct{}
+
+func (s *serverConsoleRunner) RunE(cmd *cobra.Command, args []string) error {
+	query := ""
+	if len(args) == 1 {
+		query = args[0]
+	}
+	client, instance, err := dialServer(query)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	fmt.Printf("Connected to %s. Type %s to leave\n", instance.Name(), gchalk.Bold("exit"))
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print(gchalk.Gray("> "))
+		if !scanner.Scan() {
+			fmt.Println()
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+			continue
+		case "exit", "quit":
+			return nil
+		}
+
+		// commands in the console usually start with a slash, rcon does not want that
+		res, err := client.Exec(strings.TrimPrefix(line, "/"))
+		if err != nil {
+			return err
+		}
+		if res != "" {
+			fmt.Println(strings.TrimRight(res, "\n"))
+		}
+	}
+}
+
+// findServer returns the instance matching the name or pid of a running instance or the instance in
+// the directory query. The instance in the current directory is used if query is empty
+func findServer(query string) (*instances.Instance, error) {
+	if query == "" {
+		return root.LocalInstance()
+	}
+
+	if info, err := findRunning([]string{query}); err == nil {
+		return instances.NewFromDir(info.Directory)
+	}
+
+	if dir, err := filepath.Abs(query); err == nil {
+		if _, err := os.Stat(filepath.Join(dir, "minepkg.toml")); err == nil {
+			return instances.NewFromDir(dir)
+		}
+	}
+
+	return nil, &commands.CliError{
+		Text: fmt.Sprintf("could not find the instance %s", query),
+		Suggestions: []string{
+			fmt.Sprintf("Run %s to list all running instances", gchalk.Bold("minepkg ps")),
+			"Pass the path to the instance directory instead",
+		},
+	}
+}
+
+// dialServer connects to the rcon server of the instance matching query
+func dialServer(query string) (*rcon.Client, *instances.Instance, error) {
+	instance, err := findServer(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := instance.DialRcon(10 * time.Second)
+	if errors.Is(err, instances.ErrRconDisabled) {
+		return nil, nil, &commands.CliError{
+			Text: fmt.Sprintf("RCON is not enabled for %s", instance.Name()),
+			Suggestions: []string{
+				fmt.Sprintf("Start the server with %s once, it enables RCON", gchalk.Bold("minepkg launch --server")),
+			},
+		}
+	}
+	if errors.Is(err, rcon.ErrAuthFailed) {
+		return nil, nil, &commands.CliError{
+			Text:        "the server rejected the RCON password",
+			Suggestions: []string{"Restart the server, it might still use an old rcon.password"},
+		}
+	}
+	if err != nil {
+		return nil, nil, &commands.CliError{
+			Text: fmt.Sprintf("could not connect to %s: %s", instance.Name(), err),
+			Suggestions: []string{
+				"Check if the server is running and done starting",
+				fmt.Sprintf("Start it with %s", gchalk.Bold("minepkg launch --server --detach")),
+			},
+		}
+	}
+	return client, instance, nil
+}
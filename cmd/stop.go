@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &stopRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "stop [instance]",
+		Short: "Stops an instance running in the background",
+		Long: `Stops an instance that was started with "minepkg launch --detach".
+The instance can be a name or pid as listed by "minepkg ps". Defaults to the instance in the current directory.
+Minecraft gets some time to save and stop. It is killed if it does not stop in time.`,
+		Args: cobra.MaximumNArgs(1),
+	}, runner)
+
+	cmd.Flags().DurationVarP(&runner.timeout, "timeout", "t", 60*time.Second, "Time to wait for Minecraft to stop before killing it")
+
+	rootCmd.AddCommand(cmd.Command)
+}
+
+type stopRunner struct {
+	timeout time.Duration
+}
+
+func (s *stopRunner) RunE(cmd *cobra.Command, args []string) error {
+	info, err := findRunning(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Stopping %s (pid %d) …\n", info.Name, info.PID)
+	if err := info.Stop(s.timeout); err != nil {
+		return err
+	}
+	fmt.Printf("%s was stopped\n", info.Name)
+	return nil
+}
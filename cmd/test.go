@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/launcher"
+	"github.com/minepkg/minepkg/internals/smoketest"
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	runner := &testRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "test",
+		Short: "Starts the local modpack or mod as server and checks that it runs without errors",
+		Long: `Starts a dedicated server with the local minepkg.toml, waits until it is ready and stops it again.
+The test fails if the server does not start in time, crashes or logs errors.
+
+A script with console commands can be run after the server started. Every step can expect
+a log message matching a regular expression:
+
+  [[step]]
+  command = "list"
+  expect = "There are \\d+ of a max"
+  timeout = "5s"
+
+The results can be written as JUnit XML or JSON for CI systems.`,
+		Example: `  minepkg test --accept-eula --junit report.xml
+  minepkg test --script smoketest.toml --error-tag FabricLoader`,
+		Args: cobra.NoArgs,
+	}, runner)
+
+	cmd.Flags().DurationVar(&runner.timeout, "timeout", 5*time.Minute, "Time the server gets to start")
+	cmd.Flags().DurationVar(&runner.stopTimeout, "stop-timeout", time.Minute, "Time the server gets to stop")
+	cmd.Flags().StringVar(&runner.script, "script", "", "toml file with console commands to run after the server started")
+	cmd.Flags().StringVar(&runner.junit, "junit", "", "Write a JUnit XML report to this file")
+	cmd.Flags().StringVar(&runner.json, "json", "", "Write a JSON report to this file")
+	cmd.Flags().StringArrayVar(&runner.errorTags, "error-tag", nil, "Only fail on error log lines with this tag (can be used multiple times)")
+	cmd.Flags().BoolVar(&runner.acceptEula, "accept-eula", false, "Accept the Minecraft EULA for the test server")
+	cmd.Flags().BoolVar(&runner.noBuild, "no-build", false, "Skip build (if any)")
+	runner.overwrites = launcher.CmdOverwriteFlags(cmd.Command)
+
+	rootCmd.AddCommand(cmd.Command)
+}
+
+type testRunner struct {
+	timeout     time.Duration
+	stopTimeout time.Duration
+	script      string
+	junit       string
+	json        string
+	errorTags   []string
+	acceptEula  bool
+	noBuild     bool
+
+	overwrites *launcher.OverwriteFlags
+}
+
+func (t *testRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := root.LocalInstance()
+	if err != nil {
+		return err
+	}
+	if err := root.validateManifest(instance.Manifest); err != nil {
+		return err
+	}
+	if instance.Manifest.PlatformString() == "forge" {
+		return &commands.CliError{Text: "can not test forge packages for now"}
+	}
+
+	test := &smoketest.Test{
+		Name:           instance.Manifest.Package.Name,
+		StartupTimeout: t.timeout,
+		StopTimeout:    t.stopTimeout,
+		ErrorTags:      t.errorTags,
+	}
+	if t.script != "" {
+		if test.Script, err = smoketest.ReadScript(t.script); err != nil {
+			return &commands.CliError{Text: fmt.Sprintf("invalid test script %s: %s", t.script, err)}
+		}
+	}
+
+	if t.acceptEula {
+		viper.Set("acceptMinecraftEula", true)
+	}
+	if !viper.GetBool("acceptMinecraftEula") {
+		return &commands.CliError{
+			Text: "the test server can not start without accepting the Minecraft EULA",
+			Suggestions: []string{
+				fmt.Sprintf("Read https://account.mojang.com/documents/minecraft_eula and add %s", gchalk.Bold("--accept-eula")),
+			},
+		}
+	}
+
+	cliLauncher := launcher.Launcher{
+		Instance:       instance,
+		ServerMode:     true,
+		MinepkgVersion: rootCmd.Version,
+		NonInteractive: true,
+		UseSystemJava:  viper.GetBool("useSystemJava"),
+	}
+	cliLauncher.ApplyOverWrites(t.overwrites)
+
+	if err := cliLauncher.Prepare(); err != nil {
+		return err
+	}
+	if instance.Manifest.Package.Type == manifest.TypeMod {
+		if err := (&launchRunner{instance: instance, noBuild: t.noBuild}).buildMod(); err != nil {
+			return err
+		}
+	}
+
+	report, err := cliLauncher.SmokeTest(&instances.LaunchOptions{
+		LaunchManifest: cliLauncher.LaunchManifest,
+		Server:         true,
+		RamMiB:         t.overwrites.Ram,
+		JVMArgs:        t.overwrites.JVMArgs,
+	}, test)
+	if err != nil {
+		return err
+	}
+
+	if err := t.writeReports(report); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	for _, c := range report.Cases {
+		if c.Failure == "" {
+			fmt.Printf("%s %s %s\n", gchalk.Green("✓"), c.Name, gchalk.Gray(c.Duration.Round(time.Millisecond).String()))
+		} else {
+			fmt.Printf("%s %s\n  %s\n", gchalk.Red("✗"), c.Name, c.Failure)
+		}
+	}
+
+	if failed := report.Failed(); len(failed) != 0 {
+		return &commands.CliError{Text: fmt.Sprintf("%d of %d checks failed", len(failed), len(report.Cases))}
+	}
+	fmt.Println(gchalk.Green("Smoke test passed"))
+	return nil
+}
+
+func (t *testRunner) writeReports(report *smoketest.Report) error {
+	write := func(path string, fn func(f *os.File) error) error {
+		if path == "" {
+			return nil
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := fn(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	if err := write(t.junit, func(f *os.File) error { return report.WriteJUnit(f) }); err != nil {
+		return err
+	}
+	return write(t.json, func(f *os.File) error { return report.WriteJSON(f) })
+}
@@ -161,6 +161,7 @@ func (t *tryRunner) RunE(cmd *cobra.Command, args []string) error {
 		Offline:        t.offlineMode,
 		StartSave:      startSave,
 		RamMiB:         t.overwrites.Ram,
+		JVMArgs:        t.overwrites.JVMArgs,
 	}
 	err = cliLauncher.Run(opts)
 	if err != nil {
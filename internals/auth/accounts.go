@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/minepkg/minepkg/internals/credentials"
+)
+
+// legacyStoreName is the credentials store that was used before multiple accounts were supported
+const legacyStoreName = "minecraft_auth"
+
+var (
+	// ErrAccountNotFound is returned if no account with the given name exists
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrAccountExists is returned when adding an account with a name that is already used
+	ErrAccountExists = errors.New("an account with this name already exists")
+	// ErrInvalidAccountName is returned for names that can not be used as account name
+	ErrInvalidAccountName = errors.New("invalid account name (use letters, numbers, - and _)")
+)
+
+var accountNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Account is a named Minecraft login. The tokens of every account are stored separately in the credentials store
+type Account struct {
+	// Name is the name the user chose for this account
+	Name string `json:"name"`
+	// Provider is the login provider ("microsoft" or "mojang")
+	Provider string `json:"provider,omitempty"`
+	// PlayerName is the Minecraft name of the account (as of the last login)
+	PlayerName string `json:"playerName,omitempty"`
+	// UUID is the Minecraft profile id
+	UUID string `json:"uuid,omitempty"`
+	// StoreName is the name of the credentials store entry that holds the tokens of this account
+	StoreName string `json:"store"`
+}
+
+// Accounts is the list of all accounts. It contains no secrets and is stored in `accounts.json`
+type Accounts struct {
+	// Default is the name of the account that is used if no other is selected
+	Default  string     `json:"default,omitempty"`
+	Accounts []*Account `json:"accounts"`
+
+	globalDir string
+}
+
+// LoadAccounts reads the accounts from the global minepkg dir.
+// The login of older minepkg versions is imported as "default" account
+func LoadAccounts(globalDir string) (*Accounts, error) {
+	accounts := &Accounts{Accounts: []*Account{}, globalDir: globalDir}
+
+	raw, err := ioutil.ReadFile(accounts.path())
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, accounts); err != nil {
+			return nil, err
+		}
+		return accounts, nil
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	legacy := &PersistentCredentials{}
+	if err := credentials.New(globalDir, legacyStoreName).Get(legacy); err == nil && legacy.Provider != "" {
+		accounts.Accounts = append(accounts.Accounts, &Account{
+			Name:      "default",
+			Provider:  legacy.Provider,
+			StoreName: legacyStoreName,
+		})
+		accounts.Default = "default"
+	}
+	return accounts, nil
+}
+
+func (a *Accounts) path() string {
+	return filepath.Join(a.globalDir, "accounts.json")
+}
+
+// Save writes the accounts to disk
+func (a *Accounts) Save() error {
+	raw, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(a.globalDir, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(a.path(), raw, 0600)
+}
+
+// Get returns the account with the given name. An empty name returns the default account
+func (a *Accounts) Get(name string) (*Account, error) {
+	if name == "" {
+		name = a.Default
+	}
+	for _, account := range a.Accounts {
+		if account.Name == name {
+			return account, nil
+		}
+	}
+	return nil, ErrAccountNotFound
+}
+
+// Add adds a new account without credentials. The first account becomes the default account
+func (a *Accounts) Add(name string) (*Account, error) {
+	if !accountNameRegex.MatchString(name) {
+		return nil, ErrInvalidAccountName
+	}
+	if _, err := a.Get(name); err == nil {
+		return nil, ErrAccountExists
+	}
+
+	account := &Account{Name: name, StoreName: legacyStoreName + "_" + name}
+	a.Accounts = append(a.Accounts, account)
+	if a.Default == "" {
+		a.Default = name
+	}
+	return account, nil
+}
+
+// Remove removes the account and deletes its credentials.
+// If it was the default account, the first remaining account becomes the default
+func (a *Accounts) Remove(name string) error {
+	account, err := a.Get(name)
+	if err != nil {
+		return err
+	}
+	if err := a.Store(account).Delete(); err != nil {
+		return err
+	}
+
+	remaining := make([]*Account, 0, len(a.Accounts))
+	for _, other := range a.Accounts {
+		if other != account {
+			remaining = append(remaining, other)
+		}
+	}
+	a.Accounts = remaining
+
+	if a.Default == account.Name {
+		a.Default = ""
+		if len(remaining) != 0 {
+			a.Default = remaining[0].Name
+		}
+	}
+	return nil
+}
+
+// Use makes the account with the given name the default account
+func (a *Accounts) Use(name string) error {
+	if _, err := a.Get(name); err != nil {
+		return err
+	}
+	a.Default = name
+	return nil
+}
+
+// Store returns the credentials store that holds the tokens of account
+func (a *Accounts) Store(account *Account) *credentials.Store {
+	return credentials.New(a.globalDir, account.StoreName)
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/credentials"
+	"github.com/zalando/go-keyring"
+)
+
+func TestAccounts(t *testing.T) {
+	keyring.MockInit()
+	dir := t.TempDir()
+
+	accounts, err := LoadAccounts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := accounts.Get(""); !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("expected no default account, got %v", err)
+	}
+
+	main, err := accounts.Add("main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alt, err := accounts.Add("alt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := accounts.Add("alt"); !errors.Is(err, ErrAccountExists) {
+		t.Fatalf("expected ErrAccountExists, got %v", err)
+	}
+	if _, err := accounts.Add("no spaces"); !errors.Is(err, ErrInvalidAccountName) {
+		t.Fatalf("expected ErrInvalidAccountName, got %v", err)
+	}
+	if accounts.Default != "main" {
+		t.Fatalf("expected first account to be default, got %q", accounts.Default)
+	}
+
+	// every account has its own credentials
+	if err := accounts.Store(main).Set(&PersistentCredentials{Provider: "microsoft"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.Store(alt).Set(&PersistentCredentials{Provider: "mojang"}); err != nil {
+		t.Fatal(err)
+	}
+	creds := &PersistentCredentials{}
+	if err := accounts.Store(alt).Get(creds); err != nil || creds.Provider != "mojang" {
+		t.Fatalf("unexpected credentials %+v (%v)", creds, err)
+	}
+
+	if err := accounts.Use("alt"); err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err = LoadAccounts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account, _ := accounts.Get(""); account == nil || account.Name != "alt" {
+		t.Fatalf("expected alt to be default, got %+v", account)
+	}
+
+	if err := accounts.Remove("alt"); err != nil {
+		t.Fatal(err)
+	}
+	if accounts.Default != "main" || len(accounts.Accounts) != 1 {
+		t.Fatalf("unexpected accounts after remove %+v", accounts)
+	}
+	creds = &PersistentCredentials{}
+	if err := accounts.Store(alt).Get(creds); err != nil || creds.Provider != "" {
+		t.Fatalf("expected credentials of alt to be deleted, got %+v (%v)", creds, err)
+	}
+	if err := accounts.Remove("alt"); !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+func TestLoadAccounts_Legacy(t *testing.T) {
+	keyring.MockInit()
+	dir := t.TempDir()
+
+	legacy := credentials.New(dir, legacyStoreName)
+	if err := legacy.Set(&PersistentCredentials{Provider: "microsoft"}); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err := LoadAccounts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := accounts.Get("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Name != "default" || account.StoreName != legacyStoreName || account.Provider != "microsoft" {
+		t.Fatalf("unexpected imported account %+v", account)
+	}
+}
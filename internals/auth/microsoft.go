@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
@@ -16,6 +17,8 @@ type Microsoft struct {
 	*microsoft.MicrosoftClient
 	authData *microsoft.Credentials
 	Store    *credentials.Store
+	// Device uses the device code flow instead of opening a browser. Useful over SSH
+	Device bool
 }
 
 // MicrosoftCredentialStorage is used to trim down the auth data to the minimum required
@@ -47,7 +50,11 @@ func (m *Microsoft) SetAuthState(authData *MicrosoftCredentialStorage) error {
 
 func (m *Microsoft) Prompt() error {
 	ctx := context.Background()
-	if err := m.Oauth(context.Background()); err != nil {
+	if m.Device {
+		if err := m.deviceLogin(ctx); err != nil {
+			return err
+		}
+	} else if err := m.Oauth(context.Background()); err != nil {
 		return err
 	}
 
@@ -62,6 +69,17 @@ func (m *Microsoft) Prompt() error {
 	return nil
 }
 
+// deviceLogin prints a code the user enters on another device and waits for the sign in
+func (m *Microsoft) deviceLogin(ctx context.Context) error {
+	code, err := m.RequestDeviceCode(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("To sign in, open %s on any device and enter the code %s\n", code.VerificationURI, code.UserCode)
+	fmt.Println("Waiting for you to sign in …")
+	return m.PollDeviceToken(ctx, code)
+}
+
 func (m *Microsoft) LaunchAuthData() (minecraft.LaunchAuthData, error) {
 	// not auth data or it is expired
 	if m.authData == nil || m.authData.IsExpired() {
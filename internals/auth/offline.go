@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/minecraft"
+)
+
+// OfflineAccessToken is the access token of offline players. It is no secret
+const OfflineAccessToken = "offline"
+
+// Offline is an auth provider for development that needs no login.
+// The player gets the UUID an offline mode server would assign, so it only works
+// in singleplayer and on servers with `online-mode=false`
+type Offline struct {
+	// PlayerName is the name the player uses in game
+	PlayerName string
+}
+
+// OfflineAuthData are the launch credentials of an offline player
+type OfflineAuthData struct {
+	PlayerName string
+	UUID       string
+}
+
+func (o *OfflineAuthData) GetAccessToken() string { return OfflineAccessToken }
+func (o *OfflineAuthData) GetPlayerName() string  { return o.PlayerName }
+func (o *OfflineAuthData) GetUUID() string        { return o.UUID }
+
+// Prompt does nothing, offline players need no login
+func (o *Offline) Prompt() error {
+	return nil
+}
+
+// LaunchAuthData returns the name and offline UUID of the player
+func (o *Offline) LaunchAuthData() (minecraft.LaunchAuthData, error) {
+	if !minecraft.ValidPlayerName(o.PlayerName) {
+		return nil, minecraft.ErrInvalidPlayerName
+	}
+	return &OfflineAuthData{
+		PlayerName: o.PlayerName,
+		// the launcher uses undashed UUIDs, like the Mojang API
+		UUID: strings.ReplaceAll(minecraft.OfflineUUID(o.PlayerName), "-", ""),
+	}, nil
+}
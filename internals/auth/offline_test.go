@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/minecraft"
+)
+
+func TestOffline_LaunchAuthData(t *testing.T) {
+	creds, err := (&Offline{PlayerName: "Notch"}).LaunchAuthData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.GetPlayerName() != "Notch" || creds.GetUUID() != "b50ad385829d3141a2167e7d7539ba7f" {
+		t.Fatalf("unexpected credentials %s %s", creds.GetPlayerName(), creds.GetUUID())
+	}
+	if creds.GetAccessToken() == "" {
+		t.Fatal("expected an access token")
+	}
+
+	if _, err := (&Offline{PlayerName: "Dev 1"}).LaunchAuthData(); !errors.Is(err, minecraft.ErrInvalidPlayerName) {
+		t.Fatalf("expected ErrInvalidPlayerName, got %v", err)
+	}
+}
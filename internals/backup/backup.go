@@ -0,0 +1,457 @@
+// Package backup creates, restores and rotates world backups
+package backup
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mholt/archiver/v3"
+)
+
+// Format is the archive format of a backup
+type Format string
+
+const (
+	// FormatZip creates zip archives
+	FormatZip Format = "zip"
+	// FormatTarZstd creates zstd compressed tar archives. They are smaller & faster than zip
+	FormatTarZstd Format = "tar.zst"
+)
+
+// timeFormat is used to name the backups
+const timeFormat = "2006-01-02_15.04.05"
+
+// metaFile lists the paths contained in a backup. They get replaced on restore
+const metaFile = ".minepkg-backup"
+
+var (
+	// ErrInvalidFormat is returned for unknown archive formats
+	ErrInvalidFormat = errors.New("invalid backup format (use zip or tar.zst)")
+	// ErrNotFound is returned if a backup does not exist
+	ErrNotFound = errors.New("backup not found")
+	// ErrNothingToBackup is returned if none of the paths to backup exist
+	ErrNothingToBackup = errors.New("nothing to backup")
+)
+
+// ParseFormat returns the format for the given name. An empty name returns `FormatZip`
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.TrimPrefix(name, ".")) {
+	case "", FormatZip:
+		return FormatZip, nil
+	case FormatTarZstd, "tzst":
+		return FormatTarZstd, nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+// Backup is a single backup archive
+type Backup struct {
+	// Name is the file name of the backup without extension
+	Name string
+	// Path is the path of the archive
+	Path string
+	// Time is the time the backup was created
+	Time time.Time
+	// Size is the size of the archive in bytes
+	Size int64
+	// Format is the archive format
+	Format Format
+}
+
+// Store manages backups in a directory
+type Store struct {
+	Dir string
+}
+
+// NewStore returns a store that keeps its backups in dir
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Create backs up the given paths (relative to root) into a new archive.
+// Paths that do not exist are skipped
+func (s *Store) Create(root string, paths []string, format Format) (*Backup, error) {
+	existing := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if _, err := os.Stat(filepath.Join(root, p)); err == nil {
+			existing = append(existing, filepath.ToSlash(p))
+		}
+	}
+	if len(existing) == 0 {
+		return nil, ErrNothingToBackup
+	}
+
+	if err := os.MkdirAll(s.Dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	name := now.Format(timeFormat)
+	// avoid overwriting a backup created in the same second
+	for i := 1; fileExists(filepath.Join(s.Dir, name+"."+string(format))); i++ {
+		name = fmt.Sprintf("%s-%d", now.Format(timeFormat), i)
+	}
+	target := filepath.Join(s.Dir, name+"."+string(format))
+
+	if err := writeArchive(target+".part", root, existing, format); err != nil {
+		os.Remove(target + ".part")
+		return nil, err
+	}
+	if err := os.Rename(target+".part", target); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	return &Backup{Name: name, Path: target, Time: now, Size: info.Size(), Format: format}, nil
+}
+
+// List returns all backups, oldest first
+func (s *Store) List() ([]*Backup, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Backup{}, nil
+		}
+		return nil, err
+	}
+
+	backups := make([]*Backup, 0, len(entries))
+	for _, e := range entries {
+		b := s.fromEntry(e)
+		if b != nil {
+			backups = append(backups, b)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].Time.Equal(backups[j].Time) {
+			// backups created in the same second have a "-n" suffix
+			return len(backups[i].Name) < len(backups[j].Name) || (len(backups[i].Name) == len(backups[j].Name) && backups[i].Name < backups[j].Name)
+		}
+		return backups[i].Time.Before(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// Get returns the backup with the given name. "latest" returns the newest backup
+func (s *Store) Get(name string) (*Backup, error) {
+	backups, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	if name == "latest" {
+		if len(backups) == 0 {
+			return nil, ErrNotFound
+		}
+		return backups[len(backups)-1], nil
+	}
+	for _, b := range backups {
+		if b.Name == name || filepath.Base(b.Path) == name {
+			return b, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Remove deletes the backup
+func (s *Store) Remove(b *Backup) error {
+	return os.Remove(b.Path)
+}
+
+func (s *Store) fromEntry(e fs.DirEntry) *Backup {
+	if e.IsDir() {
+		return nil
+	}
+	var format Format
+	switch {
+	case strings.HasSuffix(e.Name(), "."+string(FormatTarZstd)):
+		format = FormatTarZstd
+	case strings.HasSuffix(e.Name(), "."+string(FormatZip)):
+		format = FormatZip
+	default:
+		return nil
+	}
+	info, err := e.Info()
+	if err != nil {
+		return nil
+	}
+
+	name := strings.TrimSuffix(e.Name(), "."+string(format))
+	created := info.ModTime()
+	if len(name) >= len(timeFormat) {
+		if t, err := time.ParseInLocation(timeFormat, name[:len(timeFormat)], time.Local); err == nil {
+			created = t
+		}
+	}
+	return &Backup{Name: name, Path: filepath.Join(s.Dir, e.Name()), Time: created, Size: info.Size(), Format: format}
+}
+
+// Restore replaces the backed up paths in root with the content of the backup
+func Restore(b *Backup, root string) error {
+	paths, err := readPaths(b)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		target := filepath.Join(root, filepath.FromSlash(p))
+		if err := checkPath(root, target); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+	}
+
+	return walkArchive(b, func(name string, f fs.FileInfo, r io.Reader) error {
+		if name == metaFile {
+			return nil
+		}
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if err := checkPath(root, target); err != nil {
+			return err
+		}
+		if f.IsDir() {
+			return os.MkdirAll(target, os.ModePerm)
+		}
+		if !f.Mode().IsRegular() {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, f.Mode().Perm()|0200)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, r)
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			os.Chtimes(target, f.ModTime(), f.ModTime())
+		}
+		return err
+	})
+}
+
+// readPaths returns the paths that were backed up. Falls back to the top level entries
+// for archives without meta file
+func readPaths(b *Backup) ([]string, error) {
+	var paths []string
+	topLevel := map[string]bool{}
+	err := walkArchive(b, func(name string, f fs.FileInfo, r io.Reader) error {
+		if name == metaFile {
+			scanner := bufio.NewScanner(r)
+			for scanner.Scan() {
+				if line := strings.TrimSpace(scanner.Text()); line != "" {
+					paths = append(paths, line)
+				}
+			}
+			return scanner.Err()
+		}
+		topLevel[strings.SplitN(name, "/", 2)[0]] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if paths == nil {
+		for p := range topLevel {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+func writeArchive(target string, root string, paths []string, format Format) error {
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	w, err := newWriter(format)
+	if err != nil {
+		return err
+	}
+	if err := w.Create(out); err != nil {
+		return err
+	}
+
+	meta := strings.Join(paths, "\n") + "\n"
+	err = w.Write(archiver.File{
+		FileInfo: archiver.FileInfo{
+			FileInfo:   fakeInfo{name: metaFile, size: int64(len(meta))},
+			CustomName: metaFile,
+		},
+		ReadCloser: archiver.ReadFakeCloser{Reader: strings.NewReader(meta)},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		err := filepath.Walk(filepath.Join(root, filepath.FromSlash(p)), func(file string, info fs.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			// the session lock is held by the running game and can not be read on windows
+			if info.Name() == "session.lock" || !(info.IsDir() || info.Mode().IsRegular()) {
+				return nil
+			}
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				return err
+			}
+
+			f := archiver.File{
+				FileInfo: archiver.FileInfo{FileInfo: info, CustomName: filepath.ToSlash(rel)},
+			}
+			if !info.IsDir() {
+				rc, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				defer rc.Close()
+				f.ReadCloser = rc
+			}
+			return w.Write(f)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// walkArchive calls fn for every file in the backup
+func walkArchive(b *Backup, fn func(name string, info fs.FileInfo, r io.Reader) error) error {
+	if b.Format == FormatZip {
+		return walkZip(b.Path, fn)
+	}
+
+	in, err := os.Open(b.Path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	r, err := newReader(b.Format)
+	if err != nil {
+		return err
+	}
+	if err := r.Open(in, 0); err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for {
+		f, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := f.Name()
+		if h, ok := f.Header.(*tar.Header); ok {
+			name = h.Name
+		}
+		err = fn(cleanName(name), f.FileInfo, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// walkZip uses the zip reader of the standard library, the one of archiver does not expose full names
+func walkZip(archive string, fn func(name string, info fs.FileInfo, r io.Reader) error) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = fn(cleanName(f.Name), f.FileInfo(), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanName returns the archive entry name without leading or trailing slashes
+func cleanName(name string) string {
+	return strings.TrimSuffix(path.Clean("/" + name)[1:], "/")
+}
+
+func newWriter(format Format) (archiver.Writer, error) {
+	switch format {
+	case FormatZip:
+		return archiver.NewZip(), nil
+	case FormatTarZstd:
+		return archiver.NewTarZstd(), nil
+	}
+	return nil, ErrInvalidFormat
+}
+
+func newReader(format Format) (archiver.Reader, error) {
+	switch format {
+	case FormatTarZstd:
+		return archiver.NewTarZstd(), nil
+	}
+	return nil, ErrInvalidFormat
+}
+
+// checkPath makes sure target is inside of root
+func checkPath(root string, target string) error {
+	rel, err := filepath.Rel(root, target)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("%s: illegal path in backup", target)
+	}
+	return nil
+}
+
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+// fakeInfo is the file info of a file that only exists in memory
+type fakeInfo struct {
+	name string
+	size int64
+}
+
+func (f fakeInfo) Name() string       { return f.name }
+func (f fakeInfo) Size() int64        { return f.size }
+func (f fakeInfo) Mode() fs.FileMode  { return 0644 }
+func (f fakeInfo) ModTime() time.Time { return time.Now() }
+func (f fakeInfo) IsDir() bool        { return false }
+func (f fakeInfo) Sys() interface{}   { return nil }
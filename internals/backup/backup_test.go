@@ -0,0 +1,183 @@
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStore_CreateRestore(t *testing.T) {
+	for _, format := range []Format{FormatZip, FormatTarZstd} {
+		t.Run(string(format), func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, map[string]string{
+				"saves/One/level.dat":        "one",
+				"saves/One/region/r.0.0.mca": "region",
+				"saves/Two/level.dat":        "two",
+				"world/level.dat":            "server",
+				"world/session.lock":         "lock",
+				"options.txt":                "not backed up",
+			})
+			store := NewStore(filepath.Join(root, "backups"))
+
+			b, err := store.Create(root, []string{"saves/One", "world", "missing"}, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.Format != format || b.Size == 0 {
+				t.Fatalf("unexpected backup %+v", b)
+			}
+
+			// change the worlds
+			writeFiles(t, root, map[string]string{
+				"saves/One/level.dat":    "changed",
+				"saves/One/new-file.dat": "new",
+				"saves/Two/level.dat":    "two changed",
+				"world/level.dat":        "changed",
+				"options.txt":            "changed",
+			})
+
+			latest, err := store.Get("latest")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Restore(latest, root); err != nil {
+				t.Fatal(err)
+			}
+
+			want := map[string]string{
+				"saves/One/level.dat":        "one",
+				"saves/One/region/r.0.0.mca": "region",
+				"world/level.dat":            "server",
+				// not part of the backup, so they stay untouched
+				"saves/Two/level.dat": "two changed",
+				"options.txt":         "changed",
+			}
+			for name, content := range want {
+				got, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+				if err != nil || string(got) != content {
+					t.Errorf("%s: got %q (%v), want %q", name, got, err, content)
+				}
+			}
+			for _, name := range []string{"saves/One/new-file.dat", "world/session.lock", metaFile} {
+				if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(name))); !os.IsNotExist(err) {
+					t.Errorf("%s should not exist after restore", name)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_CreateNothing(t *testing.T) {
+	root := t.TempDir()
+	_, err := NewStore(filepath.Join(root, "backups")).Create(root, []string{"world"}, FormatZip)
+	if !errors.Is(err, ErrNothingToBackup) {
+		t.Fatalf("expected ErrNothingToBackup, got %v", err)
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"2021-05-02_10.00.00.zip":     "",
+		"2021-05-01_10.00.00.tar.zst": "",
+		"2021-05-02_10.00.00-1.zip":   "",
+		"notes.txt":                   "",
+	})
+
+	backups, err := NewStore(dir).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(backups))
+	for i, b := range backups {
+		names[i] = b.Name
+	}
+	want := []string{"2021-05-01_10.00.00", "2021-05-02_10.00.00", "2021-05-02_10.00.00-1"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+	if backups[0].Format != FormatTarZstd {
+		t.Errorf("unexpected format %s", backups[0].Format)
+	}
+
+	if _, err := NewStore(dir).Get("nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPolicy_Expired(t *testing.T) {
+	base := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	backups := make([]*Backup, 0)
+	// a backup every 30 minutes for 3 days
+	for i := 0; i < 3*48; i++ {
+		backups = append(backups, &Backup{Name: string(rune(i)), Time: base.Add(time.Duration(i) * 30 * time.Minute)})
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		kept   int
+	}{
+		{"keep last", Policy{KeepLast: 5}, 5},
+		{"keep hourly", Policy{KeepHourly: 10}, 10},
+		{"keep daily", Policy{KeepDaily: 2}, 2},
+		// newest 2 backups are the newest of the last hour & day as well
+		{"combined", Policy{KeepLast: 2, KeepHourly: 3, KeepDaily: 3}, 2 + 2 + 2},
+		{"keep more than exist", Policy{KeepLast: 1000}, len(backups)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired := tt.policy.Expired(backups)
+			if kept := len(backups) - len(expired); kept != tt.kept {
+				t.Fatalf("kept %d backups, want %d", kept, tt.kept)
+			}
+			for _, b := range expired {
+				if b == backups[len(backups)-1] {
+					t.Fatal("newest backup should never expire")
+				}
+			}
+		})
+	}
+}
+
+type fakeServer struct {
+	commands []string
+}
+
+func (f *fakeServer) Exec(command string) (string, error) {
+	f.commands = append(f.commands, command)
+	return "", nil
+}
+
+func TestPauseSaving(t *testing.T) {
+	server := &fakeServer{}
+	resume, err := PauseSaving(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resume(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"save-off", "save-all flush", "save-on"}
+	if !reflect.DeepEqual(server.commands, want) {
+		t.Fatalf("got %v, want %v", server.commands, want)
+	}
+}
@@ -0,0 +1,73 @@
+package backup
+
+import (
+	"time"
+)
+
+// Policy decides which backups are kept when pruning.
+// A backup is kept if any of the rules wants to keep it
+type Policy struct {
+	// KeepLast keeps the newest n backups
+	KeepLast int
+	// KeepHourly keeps the newest backup of each of the last n hours that have backups
+	KeepHourly int
+	// KeepDaily keeps the newest backup of each of the last n days that have backups
+	KeepDaily int
+}
+
+// IsZero returns true if the policy has no rules (and would remove all backups)
+func (p Policy) IsZero() bool {
+	return p.KeepLast <= 0 && p.KeepHourly <= 0 && p.KeepDaily <= 0
+}
+
+// Expired returns the backups that are not kept by this policy.
+// backups has to be sorted oldest first (like `Store.List` returns them)
+func (p Policy) Expired(backups []*Backup) []*Backup {
+	keep := make(map[*Backup]bool, len(backups))
+
+	keepBuckets := func(n int, bucket func(t time.Time) string) {
+		seen := map[string]bool{}
+		for i := len(backups) - 1; i >= 0 && len(seen) < n; i-- {
+			key := bucket(backups[i].Time)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			keep[backups[i]] = true
+		}
+	}
+
+	for i := len(backups) - 1; i >= 0 && i >= len(backups)-p.KeepLast; i-- {
+		keep[backups[i]] = true
+	}
+	keepBuckets(p.KeepHourly, func(t time.Time) string { return t.Format("2006-01-02 15") })
+	keepBuckets(p.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") })
+
+	expired := make([]*Backup, 0)
+	for _, b := range backups {
+		if !keep[b] {
+			expired = append(expired, b)
+		}
+	}
+	return expired
+}
+
+// Prune removes all backups that are not kept by the policy and returns them.
+// Nothing is removed for a policy without rules
+func (s *Store) Prune(p Policy) ([]*Backup, error) {
+	if p.IsZero() {
+		return []*Backup{}, nil
+	}
+	backups, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	expired := p.Expired(backups)
+	for _, b := range expired {
+		if err := s.Remove(b); err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
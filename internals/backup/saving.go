@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Commander runs commands on a running server. `rcon.Client` is a Commander
+type Commander interface {
+	Exec(command string) (string, error)
+}
+
+// StdinCommander writes commands to the stdin of a server process.
+// The server does not answer, so it waits `Wait` after every command
+type StdinCommander struct {
+	W    io.Writer
+	Wait time.Duration
+
+	mu sync.Mutex
+}
+
+// Exec writes command to the server stdin
+func (s *StdinCommander) Exec(command string) (string, error) {
+	s.mu.Lock()
+	_, err := fmt.Fprintln(s.W, command)
+	s.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	time.Sleep(s.Wait)
+	return "", nil
+}
+
+// Write passes p to the server stdin. It can be used to forward user input while
+// commands are also sent
+func (s *StdinCommander) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.W.Write(p)
+}
+
+// PauseSaving turns off auto saving of the server and saves all worlds to disk, so they
+// can be copied safely. The returned function turns auto saving on again
+func PauseSaving(c Commander) (func() error, error) {
+	if _, err := c.Exec("save-off"); err != nil {
+		return nil, err
+	}
+	resume := func() error {
+		_, err := c.Exec("save-on")
+		return err
+	}
+	if _, err := c.Exec("save-all flush"); err != nil {
+		resume()
+		return nil, err
+	}
+	return resume, nil
+}
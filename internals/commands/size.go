@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// DirSize returns the size of all files in the given directory in bytes
+func DirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// HumanSize formats bytes as KiB, MiB or GiB
+func HumanSize(bytes int64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.0f MiB", float64(bytes)/(1<<20))
+	default:
+		return fmt.Sprintf("%d KiB", bytes/1024)
+	}
+}
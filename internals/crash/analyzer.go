@@ -0,0 +1,185 @@
+// Package crash analyzes Minecraft crash reports and logs to find out why Minecraft crashed
+package crash
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// Finding is a single problem that was detected
+type Finding struct {
+	// Rule is the name of the rule that detected this problem
+	Rule string
+	// Problem describes what went wrong
+	Problem string
+	// ModIDs are the ids of the mods that most likely caused this problem
+	ModIDs []string
+	// Packages are the lockfile packages of `ModIDs` (if they could be found)
+	Packages []string
+	// Suggestions are things the user can do to fix this
+	Suggestions []string
+	// Evidence is the line that lead to this finding
+	Evidence string
+}
+
+// Rule detects a single kind of problem
+type Rule struct {
+	// Name identifies this rule (eg. "mixin")
+	Name string
+	// Check returns all problems found in the input
+	Check func(in *Input) []*Finding
+}
+
+// Input is the text that gets analyzed
+type Input struct {
+	// Lines of the crash report and log
+	Lines []string
+
+	mods     *ModIndex
+	lockfile *manifest.Lockfile
+}
+
+// Package returns the name of the lockfile package providing the mod.
+// Returns the mod id if no package was found
+func (in *Input) Package(modID string) string {
+	if name := in.packageOf(modID); name != "" {
+		return name
+	}
+	return modID
+}
+
+func (in *Input) packageOf(modID string) string {
+	if in.lockfile == nil {
+		return ""
+	}
+	if in.mods != nil {
+		if file, ok := in.mods.Files[modID]; ok {
+			for _, dep := range in.lockfile.Dependencies {
+				if dep.Filename() == file {
+					return dep.Name
+				}
+			}
+		}
+	}
+	if dep, ok := in.lockfile.Dependencies[modID]; ok {
+		return dep.Name
+	}
+	return ""
+}
+
+// ModForClass returns the id of the mod containing the given class (like "com.example.Mod").
+// Returns an empty string if it is unknown
+func (in *Input) ModForClass(class string) string {
+	if in.mods == nil {
+		return ""
+	}
+	return in.mods.ModForClass(class)
+}
+
+// ModForMixinConfig returns the id of the mod that contains the given mixin config (like "example.mixins.json")
+func (in *Input) ModForMixinConfig(config string) string {
+	if in.mods != nil {
+		if id, ok := in.mods.MixinConfigs[config]; ok {
+			return id
+		}
+	}
+	// most mods name their config like "modid.mixins.json" or "modid-common.mixins.json"
+	name := strings.TrimSuffix(config, ".json")
+	name = strings.TrimSuffix(name, ".mixins")
+	name = strings.TrimSuffix(name, "-mixins")
+	return strings.SplitN(name, ".", 2)[0]
+}
+
+// Analyzer runs rules on crash reports and logs
+type Analyzer struct {
+	// Rules are the rules used by `Analyze`. Defaults to `DefaultRules`
+	Rules []Rule
+	// Mods is used to find the mods that belong to classes & mixin configs. Can be nil
+	Mods *ModIndex
+	// Lockfile is used to map mod ids to packages. Can be nil
+	Lockfile *manifest.Lockfile
+}
+
+// New returns an analyzer using the default rules
+func New(mods *ModIndex, lockfile *manifest.Lockfile) *Analyzer {
+	return &Analyzer{Rules: DefaultRules, Mods: mods, Lockfile: lockfile}
+}
+
+// Analyze runs all rules on the given texts (usually a crash report and the latest log)
+func (a *Analyzer) Analyze(texts ...string) []*Finding {
+	in := &Input{mods: a.Mods, lockfile: a.Lockfile}
+	for _, text := range texts {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+		in.Lines = append(in.Lines, strings.Split(text, "\n")...)
+	}
+
+	findings := make([]*Finding, 0)
+	seen := make(map[string]bool)
+	for _, rule := range a.Rules {
+		for _, f := range rule.Check(in) {
+			// the same problem usually is in the crash report and the log
+			key := rule.Name + "\x00" + f.Problem
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			f.Rule = rule.Name
+			for _, id := range f.ModIDs {
+				if name := in.packageOf(id); name != "" {
+					f.Packages = append(f.Packages, name)
+				}
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	return findings
+}
+
+// AnalyzeDir analyzes the newest crash report and the latest log in the given minecraft directory.
+// Crash reports older than `since` are ignored
+func (a *Analyzer) AnalyzeDir(mcDir string, since time.Time) ([]*Finding, error) {
+	texts := make([]string, 0, 2)
+
+	if report, err := LatestReport(mcDir); err == nil && !modifiedBefore(report, since) {
+		raw, err := ioutil.ReadFile(report)
+		if err != nil {
+			return nil, err
+		}
+		texts = append(texts, string(raw))
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(mcDir, "logs/latest.log"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	texts = append(texts, string(raw))
+
+	return a.Analyze(texts...), nil
+}
+
+// LatestReport returns the path of the newest crash report in the minecraft directory
+func LatestReport(mcDir string) (string, error) {
+	reports, err := filepath.Glob(filepath.Join(mcDir, "crash-reports", "*.txt"))
+	if err != nil {
+		return "", err
+	}
+	if len(reports) == 0 {
+		return "", os.ErrNotExist
+	}
+	// names look like "crash-2022-06-20_13.46.33-client.txt" so they sort by date
+	sort.Strings(reports)
+	return reports[len(reports)-1], nil
+}
+
+func modifiedBefore(file string, t time.Time) bool {
+	stat, err := os.Stat(file)
+	return err != nil || stat.ModTime().Before(t)
+}
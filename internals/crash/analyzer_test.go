@@ -0,0 +1,198 @@
+package crash
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+func testLockfile() *manifest.Lockfile {
+	lock := manifest.NewLockfile()
+	for _, dep := range []*manifest.DependencyLock{
+		{Name: "better-chat", Version: "1.4.0", Type: "mod"},
+		{Name: "waystones", Version: "2.0.0", Type: "mod"},
+		{Name: "modmenu", Version: "4.0.0", Type: "mod"},
+		{Name: "lithium", Version: "0.7.10", Type: "mod"},
+	} {
+		lock.AddDependency(dep)
+	}
+	return lock
+}
+
+func testMods() *ModIndex {
+	return &ModIndex{
+		Files: map[string]string{
+			"betterchat": "better-chat-1.4.0.jar",
+			"waystones":  "waystones-2.0.0.jar",
+		},
+		MixinConfigs: map[string]string{
+			"betterchat.mixins.json": "betterchat",
+		},
+		Packages: map[string]string{
+			"com/example/waystones/block": "waystones",
+		},
+	}
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		rule        string
+		modIDs      []string
+		packages    []string
+		problem     string
+		findings    int
+		suggestions string
+	}{
+		{
+			fixture:  "fabric-mixin.txt",
+			rule:     "mixin",
+			modIDs:   []string{"betterchat"},
+			packages: []string{"better-chat"},
+			problem:  "betterchat could not apply its mixin ChatHudMixin (betterchat.mixins.json)",
+			findings: 1,
+		},
+		{
+			fixture:     "fabric-missing-dependency.log",
+			rule:        "missing-dependency",
+			modIDs:      []string{"modmenu"},
+			packages:    []string{"modmenu"},
+			problem:     "modmenu requires any version of fabric-api, which is missing",
+			findings:    2,
+			suggestions: `minepkg install fabric-api`,
+		},
+		{
+			fixture:  "fabric-duplicate-mod.log",
+			rule:     "duplicate-mod",
+			modIDs:   []string{"lithium"},
+			packages: []string{"lithium"},
+			problem:  "lithium is installed more than once",
+			findings: 1,
+		},
+		{
+			fixture:     "no-such-method.txt",
+			rule:        "no-such-method",
+			modIDs:      []string{"waystones"},
+			packages:    []string{"waystones"},
+			problem:     "waystones calls dev.architectury.registry.registries.Registries.getId which does not exist in the installed version of dev.architectury.registry.registries",
+			findings:    1,
+			suggestions: "Update waystones",
+		},
+		{
+			fixture:     "no-such-method-obfuscated.txt",
+			rule:        "no-such-method",
+			modIDs:      []string{"waystones"},
+			packages:    []string{"waystones"},
+			problem:     "waystones calls bib.z which does not exist in the installed version of minecraft",
+			findings:    1,
+			suggestions: "Update waystones",
+		},
+		{
+			fixture:     "out-of-memory.log",
+			rule:        "out-of-memory",
+			problem:     "Minecraft ran out of memory (Java heap space)",
+			findings:    1,
+			suggestions: "--ram",
+		},
+		{
+			fixture:     "forge-missing-mods.log",
+			rule:        "missing-dependency",
+			modIDs:      []string{"jei"},
+			problem:     "jei requires forge@14.23.5",
+			findings:    1,
+			suggestions: "minepkg install forge",
+		},
+		{
+			fixture:  "clean.log",
+			findings: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			findings := New(testMods(), testLockfile()).Analyze(string(raw))
+			if len(findings) != tt.findings {
+				for _, f := range findings {
+					t.Logf("%+v", f)
+				}
+				t.Fatalf("expected %d findings, got %d", tt.findings, len(findings))
+			}
+			if tt.findings == 0 {
+				return
+			}
+
+			f := findings[0]
+			if f.Rule != tt.rule {
+				t.Errorf("rule: got %s, want %s", f.Rule, tt.rule)
+			}
+			if f.Problem != tt.problem {
+				t.Errorf("problem: got %q, want %q", f.Problem, tt.problem)
+			}
+			if !reflect.DeepEqual(f.ModIDs, tt.modIDs) {
+				t.Errorf("mod ids: got %v, want %v", f.ModIDs, tt.modIDs)
+			}
+			if !reflect.DeepEqual(f.Packages, tt.packages) {
+				t.Errorf("packages: got %v, want %v", f.Packages, tt.packages)
+			}
+			if tt.suggestions != "" && !strings.Contains(strings.Join(f.Suggestions, "\n"), tt.suggestions) {
+				t.Errorf("suggestions %q do not contain %q", f.Suggestions, tt.suggestions)
+			}
+		})
+	}
+}
+
+func TestInput_ModForMixinConfig(t *testing.T) {
+	in := &Input{}
+	for config, want := range map[string]string{
+		"sodium.mixins.json":         "sodium",
+		"lithium-common.mixins.json": "lithium-common",
+		"iris-mixins.json":           "iris",
+	} {
+		if got := in.ModForMixinConfig(config); got != want {
+			t.Errorf("%s: got %s, want %s", config, got, want)
+		}
+	}
+}
+
+func TestScanMods(t *testing.T) {
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "better-chat-1.4.0.jar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	files := map[string]string{
+		"fabric.mod.json":                      `{"id": "betterchat", "mixins": ["betterchat.mixins.json", {"config": "betterchat.client.mixins.json", "environment": "client"}]}`,
+		"com/example/betterchat/ChatHud.class": "",
+	}
+	for name, body := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(body))
+	}
+	zw.Close()
+	f.Close()
+
+	index, err := ScanMods(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Files["betterchat"] != "better-chat-1.4.0.jar" {
+		t.Errorf("unexpected files %v", index.Files)
+	}
+	if index.MixinConfigs["betterchat.client.mixins.json"] != "betterchat" || index.MixinConfigs["betterchat.mixins.json"] != "betterchat" {
+		t.Errorf("unexpected mixin configs %v", index.MixinConfigs)
+	}
+	if got := index.ModForClass("com.example.betterchat.ChatHud$Inner"); got != "betterchat" {
+		t.Errorf("ModForClass: got %q", got)
+	}
+}
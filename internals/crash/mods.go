@@ -0,0 +1,147 @@
+package crash
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// forgeModID matches the mod id in forge `META-INF/mods.toml` files
+var forgeModID = regexp.MustCompile(`(?m)^\s*modId\s*=\s*"([^"]+)"`)
+
+// ModIndex knows which mod contains which classes and mixin configs
+type ModIndex struct {
+	// Files maps mod ids to their jar file name
+	Files map[string]string
+	// MixinConfigs maps mixin config names (like "example.mixins.json") to mod ids
+	MixinConfigs map[string]string
+	// Packages maps java packages (like "com/example/mod") to mod ids
+	Packages map[string]string
+}
+
+// ScanMods reads all mod jars in the given directory
+func ScanMods(modsDir string) (*ModIndex, error) {
+	index := &ModIndex{
+		Files:        make(map[string]string),
+		MixinConfigs: make(map[string]string),
+		Packages:     make(map[string]string),
+	}
+
+	entries, err := os.ReadDir(modsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".jar") {
+			continue
+		}
+		// broken jars are not our problem here
+		index.addJar(filepath.Join(modsDir, e.Name()))
+	}
+
+	return index, nil
+}
+
+// fabricModJSON contains the parts of the `fabric.mod.json` we need
+type fabricModJSON struct {
+	ID     string            `json:"id"`
+	Mixins []json.RawMessage `json:"mixins"`
+}
+
+func (m *ModIndex) addJar(jar string) error {
+	r, err := zip.OpenReader(jar)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	modID := ""
+	var mixins []json.RawMessage
+	for _, f := range r.File {
+		switch f.Name {
+		case "fabric.mod.json":
+			meta := fabricModJSON{}
+			if err := readZipJSON(f, &meta); err == nil {
+				modID = meta.ID
+				mixins = meta.Mixins
+			}
+		case "META-INF/mods.toml":
+			if raw, err := readZipFile(f); err == nil {
+				if found := forgeModID.FindSubmatch(raw); found != nil {
+					modID = string(found[1])
+				}
+			}
+		}
+	}
+	if modID == "" {
+		return nil
+	}
+
+	m.Files[modID] = filepath.Base(jar)
+
+	// mixin entries are either a string or {"config": "x.mixins.json", "environment": "client"}
+	for _, raw := range mixins {
+		var config string
+		if err := json.Unmarshal(raw, &config); err != nil {
+			obj := struct{ Config string }{}
+			json.Unmarshal(raw, &obj)
+			config = obj.Config
+		}
+		if config != "" {
+			m.MixinConfigs[config] = modID
+		}
+	}
+
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, ".class") || strings.HasPrefix(f.Name, "META-INF/") {
+			continue
+		}
+		pkg := filepath.ToSlash(filepath.Dir(f.Name))
+		if _, ok := m.Packages[pkg]; !ok {
+			m.Packages[pkg] = modID
+		}
+	}
+
+	return nil
+}
+
+// ModForClass returns the id of the mod containing the class (like "com.example.mod.Mixin$Inner").
+// Returns an empty string if it is unknown
+func (m *ModIndex) ModForClass(class string) string {
+	pkg := strings.ReplaceAll(class, ".", "/")
+	for {
+		i := strings.LastIndex(pkg, "/")
+		if i == -1 {
+			return ""
+		}
+		pkg = pkg[:i]
+		if id, ok := m.Packages[pkg]; ok {
+			return id
+		}
+	}
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+func readZipJSON(f *zip.File, v interface{}) error {
+	raw, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
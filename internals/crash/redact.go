@@ -0,0 +1,90 @@
+package crash
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// uuids with and without dashes
+	uuidRegex = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}\b`)
+	// jwt like tokens (minecraft access tokens are jwts)
+	tokenRegex = regexp.MustCompile(`\beyJ[\w-]+\.[\w-]+\.[\w-]+`)
+	// values of token arguments like "--accessToken abc" or "accessToken=abc"
+	tokenArgRegex = regexp.MustCompile(`(?i)((?:access_?token|session|password|secret)["']?\s*[:= ]\s*["']?)[^\s"',;]+`)
+	ipv4Regex     = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d{1,5})?\b`)
+	ipv6Regex     = regexp.MustCompile(`\[?\b(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}\b\]?(?::\d{1,5})?`)
+	// lines of server logs that contain player names
+	playerLogRegex = regexp.MustCompile(`UUID of player (\w{3,16}) is|\]: (\w{3,16}) (?:joined|left) the game`)
+)
+
+// Redactor removes private information from logs
+type Redactor struct {
+	// Secrets are strings that are always removed (like the access token)
+	Secrets []string
+	// Players are player names that are replaced with "<player>"
+	Players []string
+	// Home is the home directory of the user. It is replaced with "~"
+	Home string
+}
+
+// LogPlayers returns the names of the players that joined or left a server according to its log
+func LogPlayers(log string) []string {
+	seen := make(map[string]bool)
+	players := []string{}
+	for _, match := range playerLogRegex.FindAllStringSubmatch(log, -1) {
+		name := match[1] + match[2]
+		if !seen[name] {
+			seen[name] = true
+			players = append(players, name)
+		}
+	}
+	return players
+}
+
+// Redact removes access tokens, player names, UUIDs, IPs and home directory paths from text
+func (r *Redactor) Redact(text string) string {
+	for _, secret := range r.Secrets {
+		if secret != "" {
+			text = strings.ReplaceAll(text, secret, "<redacted>")
+		}
+	}
+
+	text = tokenRegex.ReplaceAllString(text, "<redacted>")
+	text = tokenArgRegex.ReplaceAllString(text, "${1}<redacted>")
+	text = uuidRegex.ReplaceAllString(text, "<uuid>")
+	text = ipv6Regex.ReplaceAllString(text, "<ip>")
+	text = ipv4Regex.ReplaceAllStringFunc(text, func(ip string) string {
+		// keep localhost, it is not private and helps debugging
+		if strings.HasPrefix(ip, "127.0.0.1") || strings.HasPrefix(ip, "0.0.0.0") {
+			return ip
+		}
+		// versions like "1.2.3.4000" are no ips
+		for _, octet := range strings.Split(strings.SplitN(ip, ":", 2)[0], ".") {
+			if n, _ := strconv.Atoi(octet); n > 255 {
+				return ip
+			}
+		}
+		return "<ip>"
+	})
+
+	if r.Home != "" && len(r.Home) > 1 {
+		text = strings.ReplaceAll(text, r.Home, "~")
+		// windows paths might also show up with forward slashes
+		text = strings.ReplaceAll(text, strings.ReplaceAll(r.Home, `\`, "/"), "~")
+	}
+
+	// replace longer names first, in case one contains another
+	players := append([]string{}, r.Players...)
+	sort.Slice(players, func(i, j int) bool { return len(players[i]) > len(players[j]) })
+	for _, player := range players {
+		if len(player) < 3 {
+			continue
+		}
+		text = regexp.MustCompile(`\b`+regexp.QuoteMeta(player)+`\b`).ReplaceAllString(text, "<player>")
+	}
+
+	return text
+}
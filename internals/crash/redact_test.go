@@ -0,0 +1,55 @@
+package crash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	r := &Redactor{
+		Secrets: []string{"s3cr3t-token"},
+		Players: []string{"Notch", "Al"},
+		Home:    "/home/notch",
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"secret", "using token s3cr3t-token now", "using token <redacted> now"},
+		{"jwt", "token eyJhbGciOi.eyJzdWIiOi.SflKxwRJSM end", "token <redacted> end"},
+		{"token arg", "--accessToken abc123 --version 1.18", "--accessToken <redacted> --version 1.18"},
+		{"uuid", "Player uuid 069a79f4-44e9-4726-a5be-fca90e38aaf5", "Player uuid <uuid>"},
+		{"uuid no dashes", "uuid 069a79f444e94726a5befca90e38aaf5", "uuid <uuid>"},
+		{"ipv4", "Connecting to 192.168.2.14:25565", "Connecting to <ip>"},
+		{"localhost", "Starting server on 127.0.0.1:25565", "Starting server on 127.0.0.1:25565"},
+		{"version is no ip", "loaded fabric 1.2.3.4000", "loaded fabric 1.2.3.4000"},
+		{"ipv6", "from [2001:0db8:0000:0000:0000:8a2e:0370:7334]:25565", "from <ip>"},
+		{"home", "at /home/notch/.minecraft/mods/a.jar", "at ~/.minecraft/mods/a.jar"},
+		{"player", "Notch joined the game", "<player> joined the game"},
+		{"player in word", "Notchy joined the game", "Notchy joined the game"},
+		{"short player name is kept", "Al joined", "Al joined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Redact(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogPlayers(t *testing.T) {
+	log := `[12:00:01] [User Authenticator #1/INFO]: UUID of player Notch is 069a79f4-44e9-4726-a5be-fca90e38aaf5
+[12:00:02] [Server thread/INFO]: Notch joined the game
+[12:00:09] [Server thread/INFO]: jeb_ joined the game
+[12:01:00] [Server thread/INFO]: jeb_ left the game
+[12:01:01] [Server thread/INFO]: Preparing spawn area: joined the game`
+
+	got := LogPlayers(log)
+	if !reflect.DeepEqual(got, []string{"Notch", "jeb_"}) {
+		t.Fatalf("unexpected players %v", got)
+	}
+}
@@ -0,0 +1,231 @@
+package crash
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/forge"
+	"github.com/minepkg/minepkg/internals/logparser"
+)
+
+// DefaultRules are the rules used by `New`
+var DefaultRules = []Rule{
+	{Name: "mixin", Check: checkMixin},
+	{Name: "missing-dependency", Check: checkMissingDependency},
+	{Name: "duplicate-mod", Check: checkDuplicateMod},
+	{Name: "no-such-method", Check: checkNoSuchMethod},
+	{Name: "out-of-memory", Check: checkOutOfMemory},
+}
+
+var (
+	mixinPatterns = []*regexp.Regexp{
+		// Mixin apply for mod example failed example.mixins.json:ExampleMixin from mod example -> net.minecraft.class_1
+		regexp.MustCompile(`Mixin apply for mod ([\w-]+) failed ([\w.-]+\.json):([\w.$/]+)`),
+		// Mixin [example.mixins.json:ExampleMixin] from phase [DEFAULT] in config [example.mixins.json] FAILED during APPLY
+		regexp.MustCompile(`Mixin \[([\w.-]+\.json):([\w.$/]+)\] from phase \[\w+\] in config \[[\w.-]+\](?: from mod \[?([\w-]+)\]?)? FAILED`),
+		// InvalidInjectionException: Critical injection failure: … in example.mixins.json:ExampleMixin from mod example
+		regexp.MustCompile(`InvalidInjectionException: .* in ([\w.-]+\.json):([\w.$/]+)(?: from mod ([\w-]+))?`),
+	}
+	missingDependency = regexp.MustCompile(`Mod '[^']*' \(([\w-]+)\) \S+ requires (.+?) of (?:mod )?(?:'[^']*' \(([\w-]+)\)|([\w-]+)), (which is missing|but only the wrong version is present: \S+)`)
+	duplicatePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`Duplicate mod ID: ([\w-]+)`),
+		regexp.MustCompile(`Mod ID '([\w-]+)' from mod files`),
+		regexp.MustCompile(`Found a duplicate mod ([\w-]+) at`),
+	}
+	noSuchMethod = regexp.MustCompile(`java\.lang\.NoSuchMethodError: '?(?:[\w.$\[\]]+ )?([\w.$]+)\.([\w$<>]+)\(`)
+	stackFrame   = regexp.MustCompile(`^\s+at ([\w.$/]+)\.[\w$<>]+\(`)
+	outOfMemory  = regexp.MustCompile(`java\.lang\.OutOfMemoryError: (.+)`)
+)
+
+func checkMixin(in *Input) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, line := range in.Lines {
+		for i, pattern := range mixinPatterns {
+			found := pattern.FindStringSubmatch(line)
+			if found == nil {
+				continue
+			}
+
+			var modID, config, mixin string
+			if i == 0 {
+				modID, config, mixin = found[1], found[2], found[3]
+			} else {
+				config, mixin, modID = found[1], found[2], found[3]
+			}
+			if modID == "" {
+				modID = in.ModForMixinConfig(config)
+			}
+
+			pkg := in.Package(modID)
+			findings = append(findings, &Finding{
+				Problem: fmt.Sprintf("%s could not apply its mixin %s (%s)", modID, mixin, config),
+				ModIDs:  []string{modID},
+				Suggestions: []string{
+					fmt.Sprintf("Update %s. It might not support this Minecraft version", pkg),
+					fmt.Sprintf("Check if %s is incompatible with another mod", pkg),
+				},
+				Evidence: strings.TrimSpace(line),
+			})
+			break
+		}
+	}
+	return findings
+}
+
+func checkMissingDependency(in *Input) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, line := range in.Lines {
+		if found := missingDependency.FindStringSubmatch(line); found != nil {
+			modID, version, dep := found[1], found[2], found[3]
+			if dep == "" {
+				dep = found[4]
+			}
+
+			problem := fmt.Sprintf("%s requires %s of %s, which is missing", modID, version, dep)
+			suggestion := fmt.Sprintf("Add it with \"minepkg install %s\"", in.Package(dep))
+			if strings.HasPrefix(found[5], "but only") {
+				problem = fmt.Sprintf("%s requires %s of %s, %s", modID, version, dep, found[5])
+				suggestion = fmt.Sprintf("Install a matching version of %s", in.Package(dep))
+			}
+			findings = append(findings, &Finding{
+				Problem:     problem,
+				ModIDs:      []string{modID},
+				Suggestions: []string{suggestion, fmt.Sprintf("Or remove %s", in.Package(modID))},
+				Evidence:    strings.TrimSpace(line),
+			})
+			continue
+		}
+
+		if !strings.Contains(line, "MissingModsException") {
+			continue
+		}
+		var missing *forge.ErrorMissingMods
+		if err := forge.ParseException(&logparser.LogLine{Message: strings.TrimSpace(line)}); err != forge.ErrorUnknown {
+			missing = err.(*forge.ErrorMissingMods)
+		}
+		if missing == nil {
+			continue
+		}
+		suggestions := make([]string, len(missing.Requires))
+		for i, req := range missing.Requires {
+			suggestions[i] = fmt.Sprintf("Add it with \"minepkg install %s\"", in.Package(req.Name))
+		}
+		findings = append(findings, &Finding{
+			Problem:     missing.Error(),
+			ModIDs:      []string{missing.ModID},
+			Suggestions: suggestions,
+			Evidence:    strings.TrimSpace(line),
+		})
+	}
+	return findings
+}
+
+func checkDuplicateMod(in *Input) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, line := range in.Lines {
+		for _, pattern := range duplicatePatterns {
+			found := pattern.FindStringSubmatch(line)
+			if found == nil {
+				continue
+			}
+			findings = append(findings, &Finding{
+				Problem: fmt.Sprintf("%s is installed more than once", found[1]),
+				ModIDs:  []string{found[1]},
+				Suggestions: []string{
+					fmt.Sprintf("Remove one of the %s mods. It might already be included in another mod or modpack", in.Package(found[1])),
+				},
+				Evidence: strings.TrimSpace(line),
+			})
+			break
+		}
+	}
+	return findings
+}
+
+func checkNoSuchMethod(in *Input) []*Finding {
+	findings := make([]*Finding, 0)
+	for i, line := range in.Lines {
+		found := noSuchMethod.FindStringSubmatch(line)
+		if found == nil {
+			continue
+		}
+		owner, method := found[1], found[2]
+
+		library := in.ModForClass(owner)
+		switch {
+		case strings.HasPrefix(owner, "net.minecraft.") || strings.HasPrefix(owner, "com.mojang."):
+			library = "minecraft"
+		case !strings.Contains(owner, "."):
+			// classes without a package are obfuscated Minecraft classes (before 1.14)
+			library = "minecraft"
+		case library == "":
+			library = owner[:strings.LastIndex(owner, ".")]
+		}
+
+		// the first frame of a mod (that is not the library itself) is the caller
+		caller := ""
+		for _, frame := range in.Lines[i+1:] {
+			match := stackFrame.FindStringSubmatch(frame)
+			// end of the stacktrace
+			if match == nil {
+				break
+			}
+			if id := in.ModForClass(match[1]); id != "" && id != library {
+				caller = id
+				break
+			}
+		}
+
+		finding := &Finding{
+			Problem:  fmt.Sprintf("%s.%s does not exist in the installed version of %s", owner, method, library),
+			Evidence: strings.TrimSpace(line),
+			Suggestions: []string{
+				fmt.Sprintf("A mod was built for a different version of %s", library),
+			},
+		}
+		if caller != "" {
+			pkg := in.Package(caller)
+			finding.Problem = fmt.Sprintf("%s calls %s.%s which does not exist in the installed version of %s", caller, owner, method, library)
+			finding.ModIDs = []string{caller}
+			finding.Suggestions = []string{
+				fmt.Sprintf("%s was built for a different version of %s. Update %s", pkg, library, pkg),
+				fmt.Sprintf("Or install the version of %s that %s requires", library, pkg),
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+func checkOutOfMemory(in *Input) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, line := range in.Lines {
+		found := outOfMemory.FindStringSubmatch(line)
+		if found == nil {
+			continue
+		}
+		kind := strings.TrimSpace(found[1])
+
+		suggestions := []string{
+			"Start Minecraft with more memory, for example with \"minepkg launch --ram 6144\"",
+			"Or set maxMemory in the [launch] section of the minepkg.toml",
+		}
+		if strings.Contains(kind, "Metaspace") {
+			suggestions = []string{
+				"Too many classes were loaded. Remove some mods",
+				"Or raise the limit with \"--jvm-arg -XX:MaxMetaspaceSize=1G\"",
+			}
+		}
+		if strings.Contains(kind, "native thread") {
+			suggestions = []string{"The system ran out of threads or memory. Close other programs"}
+		}
+
+		findings = append(findings, &Finding{
+			Problem:     fmt.Sprintf("Minecraft ran out of memory (%s)", kind),
+			Suggestions: suggestions,
+			Evidence:    strings.TrimSpace(line),
+		})
+	}
+	return findings
+}
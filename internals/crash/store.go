@@ -0,0 +1,117 @@
+package crash
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/api"
+)
+
+// ErrReportNotFound is returned if no saved report with the given id exists
+var ErrReportNotFound = errors.New("crash report not found")
+
+// SavedReport is a crash report that was saved locally
+type SavedReport struct {
+	// ID is the name of the report (based on the time of the crash)
+	ID string `json:"id"`
+	// Time is the time of the crash
+	Time time.Time `json:"time"`
+	// Submitted is true if this report was sent to minepkg.io
+	Submitted bool `json:"submitted"`
+	// Report is the (redacted) report that is or would be submitted
+	Report *api.CrashReport `json:"report"`
+	// Findings are the problems the analyzer found
+	Findings []*Finding `json:"findings,omitempty"`
+}
+
+// Store saves crash reports in a directory
+type Store struct {
+	Dir string
+}
+
+// NewStore returns a store that saves reports in dir
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Save writes the report to the store. A new ID is set if the report has none
+func (s *Store) Save(r *SavedReport) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	if r.ID == "" {
+		r.ID = r.Time.Format("2006-01-02_15.04.05")
+	}
+	if err := os.MkdirAll(s.Dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	raw, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	// reports might still contain private info, so only the user can read them
+	return ioutil.WriteFile(filepath.Join(s.Dir, r.ID+".json"), raw, 0600)
+}
+
+// List returns all saved reports, oldest first
+func (s *Store) List() ([]*SavedReport, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*SavedReport{}, nil
+		}
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			ids = append(ids, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+	sort.Strings(ids)
+
+	reports := make([]*SavedReport, 0, len(ids))
+	for _, id := range ids {
+		r, err := s.Get(id)
+		if err != nil {
+			continue
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// Get returns the report with the given id. "latest" returns the newest report
+func (s *Store) Get(id string) (*SavedReport, error) {
+	if id == "latest" {
+		reports, err := s.List()
+		if err != nil {
+			return nil, err
+		}
+		if len(reports) == 0 {
+			return nil, ErrReportNotFound
+		}
+		return reports[len(reports)-1], nil
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(s.Dir, filepath.Base(id)+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrReportNotFound
+		}
+		return nil, err
+	}
+	r := &SavedReport{}
+	if err := json.Unmarshal(raw, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
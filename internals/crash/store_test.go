@@ -0,0 +1,79 @@
+package crash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/api"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "crashes"))
+
+	if reports, err := store.List(); err != nil || len(reports) != 0 {
+		t.Fatalf("expected empty list, got %v %v", reports, err)
+	}
+	if _, err := store.Get("latest"); !errors.Is(err, ErrReportNotFound) {
+		t.Fatalf("expected ErrReportNotFound, got %v", err)
+	}
+
+	first := &SavedReport{
+		Time:   time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC),
+		Report: &api.CrashReport{ExitCode: 1},
+	}
+	second := &SavedReport{
+		Time:     time.Date(2021, 5, 2, 10, 0, 0, 0, time.UTC),
+		Report:   &api.CrashReport{ExitCode: 2},
+		Findings: []*Finding{{Rule: "mixin", Problem: "broken"}},
+	}
+	for _, r := range []*SavedReport{second, first} {
+		if err := store.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if first.ID != "2021-05-01_10.00.00" {
+		t.Errorf("unexpected id %s", first.ID)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(store.Dir, first.ID+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+		}
+	}
+
+	reports, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[0].ID != first.ID || reports[1].ID != second.ID {
+		t.Fatalf("unexpected list %v", reports)
+	}
+
+	latest, err := store.Get("latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Report.ExitCode != 2 || len(latest.Findings) != 1 {
+		t.Errorf("unexpected latest report %+v", latest)
+	}
+
+	latest.Submitted = true
+	if err := store.Save(latest); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Submitted {
+		t.Error("expected report to be submitted")
+	}
+}
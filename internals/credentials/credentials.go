@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 
 	"github.com/zalando/go-keyring"
 )
@@ -26,30 +27,73 @@ func New(globalDir string, name string) *Store {
 	return store
 }
 
+const keyringService = "minepkg_auth_data"
+
 // Get tries to find existing credentials
 func (s *Store) Get(target interface{}) error {
-	// find minepkg credentials
-	minepkgAuth, err := keyring.Get("minepkg_auth_data", s.Name)
-	switch err {
-	case nil:
-		return json.Unmarshal([]byte(minepkgAuth), target)
-	case keyring.ErrNotFound:
-		// empty result
-		return nil
-	default:
-		log.Println("Could not use key store, will default to file store for secrets.", err)
-		NoKeyRingMode = true
-		return s.findFromFiles(target)
+	raw, err := s.read()
+	if err != nil || raw == nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
+
+// read returns the raw credentials or nil if there are none
+func (s *Store) read() ([]byte, error) {
+	if !NoKeyRingMode {
+		secret, err := keyring.Get(keyringService, s.Name)
+		switch err {
+		case nil:
+			return []byte(secret), nil
+		case keyring.ErrNotFound:
+			// might have been moved to a file (see `MigrateToFile`)
+		default:
+			log.Println("Could not use key store, will default to file store for secrets.", err)
+			NoKeyRingMode = true
+		}
 	}
+	return s.readFile()
 }
 
 func (s *Store) localFilename() string {
 	return "minepkg-credentials-" + s.Name + ".json"
 }
 
-// findFromFiles is the same as Find but reads from plain files instead
-func (s *Store) findFromFiles(target interface{}) error {
-	return s.readCredentialFile(s.localFilename(), &target)
+func (s *Store) encryptedFilename() string {
+	return "minepkg-credentials-" + s.Name + ".enc"
+}
+
+// readFile reads the credentials from the encrypted file.
+// Falls back to the plain file older minepkg versions used
+func (s *Store) readFile() ([]byte, error) {
+	content, err := s.readCredentialFile(s.encryptedFilename())
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		// plain files are encrypted on the next write
+		return s.readCredentialFile(s.localFilename())
+	}
+
+	passphrase, err := getPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := decrypt(passphrase, content)
+	if err == ErrWrongPassphrase {
+		forgetPassphrase()
+	}
+	return plaintext, err
+}
+
+// inFile returns true if the credentials of this store are saved in a file instead of the keyring
+func (s *Store) inFile() bool {
+	for _, name := range []string{s.encryptedFilename(), s.localFilename()} {
+		if _, err := os.Stat(filepath.Join(s.globalDir, name)); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
 // Set sets the credentials and persists it to disk
@@ -59,40 +103,150 @@ func (s *Store) Set(data interface{}) error {
 	if err != nil {
 		return err
 	}
+	if NoKeyRingMode || s.inFile() {
+		return s.writeFile(jsonBlob)
+	}
+	if err := keyring.Set(keyringService, s.Name, string(jsonBlob)); err != nil {
+		log.Println("Could not use key store, will default to file store for secrets.", err)
+		NoKeyRingMode = true
+		return s.writeFile(jsonBlob)
+	}
+	return nil
+}
+
+// writeFile encrypts the credentials and writes them to the encrypted file
+func (s *Store) writeFile(plaintext []byte) error {
+	// new passphrases have to be confirmed
+	existing, _ := filepath.Glob(filepath.Join(s.globalDir, "minepkg-credentials-*.enc"))
+	passphrase, err := getPassphrase(len(existing) == 0)
+	if err != nil {
+		return err
+	}
+	content, err := encrypt(passphrase, plaintext)
+	if err != nil {
+		return err
+	}
+	if err := s.writeCredentialFile(s.encryptedFilename(), content); err != nil {
+		return err
+	}
+	return removeIfExists(filepath.Join(s.globalDir, s.localFilename()))
+}
+
+// Delete removes the credentials from the keyring and the file store
+func (s *Store) Delete() error {
+	err := keyring.Delete(keyringService, s.Name)
+	if err == keyring.ErrNotFound {
+		err = nil
+	}
 	if NoKeyRingMode {
-		return s.writeCredentialFile(s.localFilename(), jsonBlob)
+		err = nil
+	}
+	if ferr := s.removeFiles(); ferr != nil {
+		return ferr
+	}
+	return err
+}
+
+func (s *Store) removeFiles() error {
+	if err := removeIfExists(filepath.Join(s.globalDir, s.encryptedFilename())); err != nil {
+		return err
 	}
-	return keyring.Set("minepkg_auth_data", s.Name, string(jsonBlob))
+	return removeIfExists(filepath.Join(s.globalDir, s.localFilename()))
 }
 
-// readCredentialFile is a helper that reads a file from the minepkg config dir
-func (s *Store) readCredentialFile(location string, v interface{}) error {
+// MigrateToFile moves the credentials from the keyring into the encrypted file.
+// Plain files are encrypted. Returns false if there was nothing to move
+func (s *Store) MigrateToFile() (bool, error) {
+	secret, err := keyring.Get(keyringService, s.Name)
+	if err == nil {
+		if err := s.writeFile([]byte(secret)); err != nil {
+			return false, err
+		}
+		return true, keyring.Delete(keyringService, s.Name)
+	}
+	// no keyring also means nothing to move from it
+	if err != keyring.ErrNotFound {
+		log.Println("Could not use key store:", err)
+	}
+
+	plain, err := s.readCredentialFile(s.localFilename())
+	if err != nil || plain == nil {
+		return false, err
+	}
+	return true, s.writeFile(plain)
+}
+
+// MigrateToKeyring moves the credentials from the file into the keyring. Returns false if there was nothing to move
+func (s *Store) MigrateToKeyring() (bool, error) {
+	raw, err := s.readFile()
+	if err != nil || raw == nil {
+		return false, err
+	}
+	if err := keyring.Set(keyringService, s.Name, string(raw)); err != nil {
+		return false, err
+	}
+	return true, s.removeFiles()
+}
+
+// readCredentialFile is a helper that reads a file from the minepkg config dir.
+// Returns nil if the file does not exist
+func (s *Store) readCredentialFile(location string) ([]byte, error) {
 	file := filepath.Join(s.globalDir, location)
-	rawCreds, err := ioutil.ReadFile(file)
+	content, err := ioutil.ReadFile(file)
 	switch {
 	case err == nil:
-		// parse json
-		if err := json.Unmarshal(rawCreds, &v); err != nil {
+		if err := enforcePermissions(file); err != nil {
+			return nil, err
+		}
+		if !json.Valid(content) {
 			// ignore error. this usually happens if the disk runs out of space
 			// by ignoring it we can let the user login again after sufficient
 			// space exists again
-			fmt.Printf("WARNING: A credentials file was corrupted. ignoring")
-			return nil
+			fmt.Println("WARNING: A credentials file was corrupted. ignoring")
+			return nil, nil
 		}
-		// parsed as expected
-		return nil
+		return content, nil
 	case os.IsNotExist(err):
 		// no file is fine
-		return nil
+		return nil, nil
 	default:
 		// everything else is not
-		return err
+		return nil, err
 	}
 }
 
 // writeCredentialFile is a helper that writes a file to the minepkg config dir
 func (s *Store) writeCredentialFile(location string, content []byte) error {
-	os.MkdirAll(s.globalDir, os.ModePerm)
+	if err := os.MkdirAll(s.globalDir, os.ModePerm); err != nil {
+		return err
+	}
 	credFile := filepath.Join(s.globalDir, location)
-	return ioutil.WriteFile(credFile, content, 0700)
+	if err := ioutil.WriteFile(credFile, content, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the permissions of existing files
+	return enforcePermissions(credFile)
+}
+
+// enforcePermissions makes sure only the owner can read the file
+func enforcePermissions(file string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm() != 0600 {
+		log.Printf("Fixing permissions of %s (was %s)", file, info.Mode().Perm())
+		return os.Chmod(file, 0600)
+	}
+	return nil
+}
+
+func removeIfExists(file string) error {
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,131 @@
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+type testCreds struct {
+	Token string
+}
+
+func usePassphrase(t *testing.T, passphrase string) {
+	t.Helper()
+	forgetPassphrase()
+	Passphrase = func(confirm bool) (string, error) { return passphrase, nil }
+	t.Cleanup(forgetPassphrase)
+}
+
+func useFileMode(t *testing.T) {
+	t.Helper()
+	NoKeyRingMode = true
+	t.Cleanup(func() { NoKeyRingMode = false })
+}
+
+func TestStore_EncryptedFile(t *testing.T) {
+	useFileMode(t)
+	usePassphrase(t, "correct horse")
+	dir := t.TempDir()
+	store := New(dir, "test")
+
+	if err := store.Set(&testCreds{Token: "super-secret-token"}); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "minepkg-credentials-test.enc")
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(content, []byte("super-secret-token")) {
+		t.Fatal("credentials file is not encrypted")
+	}
+	if info, _ := os.Stat(file); runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Fatalf("expected mode 0600, got %s", info.Mode().Perm())
+	}
+
+	creds := &testCreds{}
+	if err := store.Get(creds); err != nil || creds.Token != "super-secret-token" {
+		t.Fatalf("unexpected credentials %+v (%v)", creds, err)
+	}
+
+	usePassphrase(t, "wrong")
+	if err := store.Get(&testCreds{}); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+}
+
+func TestStore_PlainFileIsEncrypted(t *testing.T) {
+	useFileMode(t)
+	usePassphrase(t, "correct horse")
+	dir := t.TempDir()
+	store := New(dir, "test")
+
+	plain := filepath.Join(dir, "minepkg-credentials-test.json")
+	if err := ioutil.WriteFile(plain, []byte(`{"Token":"old"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	creds := &testCreds{}
+	if err := store.Get(creds); err != nil || creds.Token != "old" {
+		t.Fatalf("unexpected credentials %+v (%v)", creds, err)
+	}
+	if info, _ := os.Stat(plain); runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Fatalf("expected permissions to be fixed, got %s", info.Mode().Perm())
+	}
+
+	if err := store.Set(&testCreds{Token: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(plain); !os.IsNotExist(err) {
+		t.Fatal("expected plain credentials file to be removed")
+	}
+}
+
+func TestStore_Migrate(t *testing.T) {
+	keyring.MockInit()
+	usePassphrase(t, "correct horse")
+	dir := t.TempDir()
+	store := New(dir, "test")
+
+	if err := store.Set(&testCreds{Token: "token"}); err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := store.MigrateToFile(); err != nil || !moved {
+		t.Fatalf("expected credentials to be moved (%v)", err)
+	}
+	if _, err := keyring.Get(keyringService, "test"); err != keyring.ErrNotFound {
+		t.Fatalf("expected credentials to be removed from the keyring, got %v", err)
+	}
+
+	// stays in the file
+	if err := store.Set(&testCreds{Token: "refreshed"}); err != nil {
+		t.Fatal(err)
+	}
+	creds := &testCreds{}
+	if err := store.Get(creds); err != nil || creds.Token != "refreshed" || !store.inFile() {
+		t.Fatalf("unexpected credentials %+v (%v)", creds, err)
+	}
+
+	if moved, err := store.MigrateToKeyring(); err != nil || !moved {
+		t.Fatalf("expected credentials to be moved (%v)", err)
+	}
+	if store.inFile() {
+		t.Fatal("expected credentials file to be removed")
+	}
+	creds = &testCreds{}
+	if err := store.Get(creds); err != nil || creds.Token != "refreshed" {
+		t.Fatalf("unexpected credentials %+v (%v)", creds, err)
+	}
+
+	if moved, err := New(dir, "empty").MigrateToFile(); err != nil || moved {
+		t.Fatalf("expected nothing to move (%v)", err)
+	}
+}
@@ -0,0 +1,166 @@
+package credentials
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/manifoldco/promptui"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseEnv is the environment variable that contains the passphrase of the encrypted credential files
+const PassphraseEnv = "MINEPKG_CREDENTIALS_PASSPHRASE"
+
+var (
+	// ErrWrongPassphrase is returned if a credentials file can not be decrypted
+	ErrWrongPassphrase = errors.New("wrong passphrase for the credentials file")
+	// ErrNoPassphrase is returned if no passphrase was set and the user can not be prompted
+	ErrNoPassphrase = fmt.Errorf("no passphrase for the credentials file. Set %s", PassphraseEnv)
+)
+
+// Passphrase returns the passphrase for the encrypted credential files.
+// confirm is true if a new file is created. Defaults to reading `PassphraseEnv` or prompting the user
+var Passphrase = func(confirm bool) (string, error) {
+	if env := os.Getenv(PassphraseEnv); env != "" {
+		return env, nil
+	}
+	return promptPassphrase(confirm)
+}
+
+// scrypt parameters for new files. Stored in every file, so they can be changed later
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// encryptedFile is the content of an encrypted credentials file
+type encryptedFile struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	N       int    `json:"n"`
+	R       int    `json:"r"`
+	P       int    `json:"p"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// the passphrase is only asked once per run
+var (
+	passphraseMu    sync.Mutex
+	knownPassphrase string
+)
+
+func getPassphrase(confirm bool) (string, error) {
+	passphraseMu.Lock()
+	defer passphraseMu.Unlock()
+	if knownPassphrase != "" {
+		return knownPassphrase, nil
+	}
+	passphrase, err := Passphrase(confirm)
+	if err != nil {
+		return "", err
+	}
+	knownPassphrase = passphrase
+	return passphrase, nil
+}
+
+func forgetPassphrase() {
+	passphraseMu.Lock()
+	knownPassphrase = ""
+	passphraseMu.Unlock()
+}
+
+func promptPassphrase(confirm bool) (string, error) {
+	label := "Passphrase of your minepkg credentials file"
+	if confirm {
+		fmt.Println("No system keyring found. Your credentials will be saved in a file encrypted with a passphrase.")
+		fmt.Printf("Set %s to skip this prompt.\n", PassphraseEnv)
+		label = "Choose a passphrase for your minepkg credentials file"
+	}
+
+	passphrase, err := (&promptui.Prompt{Label: label, Mask: '■', Validate: notEmpty}).Run()
+	if err != nil {
+		return "", ErrNoPassphrase
+	}
+	if !confirm {
+		return passphrase, nil
+	}
+
+	repeated, err := (&promptui.Prompt{Label: "Repeat the passphrase", Mask: '■'}).Run()
+	if err != nil {
+		return "", ErrNoPassphrase
+	}
+	if repeated != passphrase {
+		return "", errors.New("the passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+func notEmpty(s string) error {
+	if s == "" {
+		return errors.New("can not be empty")
+	}
+	return nil
+}
+
+func deriveKey(passphrase string, salt []byte, n, r, p int) (*[32]byte, error) {
+	raw, err := scrypt.Key([]byte(passphrase), salt, n, r, p, 32)
+	if err != nil {
+		return nil, err
+	}
+	key := &[32]byte{}
+	copy(key[:], raw)
+	return key, nil
+}
+
+// encrypt seals plaintext with a key derived from passphrase
+func encrypt(passphrase string, plaintext []byte) ([]byte, error) {
+	file := &encryptedFile{Version: 1, KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP}
+	file.Salt = make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, file.Salt); err != nil {
+		return nil, err
+	}
+	nonce := [24]byte{}
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, err
+	}
+	file.Nonce = nonce[:]
+
+	key, err := deriveKey(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, err
+	}
+	file.Data = secretbox.Seal(nil, plaintext, &nonce, key)
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// decrypt opens a file created by `encrypt`. Returns `ErrWrongPassphrase` if the passphrase does not match
+func decrypt(passphrase string, content []byte) ([]byte, error) {
+	file := &encryptedFile{}
+	if err := json.Unmarshal(content, file); err != nil {
+		return nil, err
+	}
+	if file.Version != 1 || file.KDF != "scrypt" || len(file.Nonce) != 24 {
+		return nil, fmt.Errorf("unsupported credentials file (version %d)", file.Version)
+	}
+
+	key, err := deriveKey(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, err
+	}
+	nonce := [24]byte{}
+	copy(nonce[:], file.Nonce)
+	plaintext, ok := secretbox.Open(nil, file.Data, &nonce, key)
+	if !ok {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,106 @@
+// Package curseforge is a client for the CurseForge API and reads and writes CurseForge modpack zips
+package curseforge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+)
+
+// DefaultApiURL is the url of the official CurseForge API
+const DefaultApiURL = "https://api.curseforge.com/"
+
+// APIKeyEnv is the environment variable the api key is read from by default
+const APIKeyEnv = "CURSEFORGE_API_KEY"
+
+var (
+	// ErrNoAPIKey is returned if no api key is set. The CurseForge API can not be used without one
+	ErrNoAPIKey = errors.New("no CurseForge API key set")
+	// ErrNotFound is returned when the requested mod or file does not exist
+	ErrNotFound = errors.New("not found on CurseForge")
+)
+
+// Client talks to the CurseForge API
+type Client struct {
+	// APIKey is sent as `x-api-key` header. Get one at https://console.curseforge.com/
+	APIKey string
+
+	http    *http.Client
+	baseURL *url.URL
+}
+
+// New returns a new client using the api key of the `CURSEFORGE_API_KEY` environment variable
+func New() *Client {
+	parsedDefaultURL, _ := url.Parse(DefaultApiURL)
+
+	return &Client{
+		APIKey:  os.Getenv(APIKeyEnv),
+		http:    http.DefaultClient,
+		baseURL: parsedDefaultURL,
+	}
+}
+
+// url joins the addedPath to the baseURL (panics if new path can not be parsed)
+func (c *Client) url(addedPath ...string) *url.URL {
+	u, err := url.Parse(path.Join(addedPath...))
+	if err != nil {
+		panic(err)
+	}
+
+	return c.baseURL.ResolveReference(u)
+}
+
+// do sends a request with the api key and decodes the `data` of the response into v.
+// body is sent as json if it is not nil
+func (c *Client) do(ctx context.Context, method string, u *url.URL, body interface{}, v interface{}) error {
+	if c.APIKey == "" {
+		return ErrNoAPIKey
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(body); err != nil {
+			return err
+		}
+		reqBody = buf
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-api-key", c.APIKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return fmt.Errorf("the CurseForge API rejected the api key (status code %d)", res.StatusCode)
+	default:
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	data := struct {
+		Data interface{} `json:"data"`
+	}{v}
+	return json.NewDecoder(res.Body).Decode(&data)
+}
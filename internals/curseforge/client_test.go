@@ -0,0 +1,57 @@
+package curseforge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func testClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	baseURL, _ := url.Parse(server.URL + "/")
+	return &Client{APIKey: "test-key", http: server.Client(), baseURL: baseURL}
+}
+
+func TestClient_GetFiles(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/mods/files" || r.Header.Get("x-api-key") != "test-key" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body := struct {
+			FileIDs []int `json:"fileIds"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.FileIDs) != 1 || body.FileIDs[0] != 3609610 {
+			t.Errorf("unexpected body %+v", body)
+		}
+		w.Write([]byte(`{"data":[{"id":3609610,"modId":306612,"fileName":"fabric-api.jar","downloadUrl":"https://edge.forgecdn.net/fabric-api.jar","hashes":[{"value":"abc","algo":1},{"value":"def","algo":2}]}]}`))
+	})
+
+	files, err := client.GetFiles(context.Background(), []int{3609610})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].ModID != 306612 || files[0].Sha1() != "abc" {
+		t.Fatalf("unexpected files %+v", files)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	if _, err := client.GetFile(context.Background(), 1, 2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	client.APIKey = ""
+	if _, err := client.GetFile(context.Background(), 1, 2); !errors.Is(err, ErrNoAPIKey) {
+		t.Fatalf("expected ErrNoAPIKey, got %v", err)
+	}
+}
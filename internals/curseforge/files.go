@@ -0,0 +1,78 @@
+package curseforge
+
+import (
+	"context"
+	"strconv"
+)
+
+// Values of `FileHash.Algo`
+const (
+	HashAlgoSha1 = 1
+	HashAlgoMd5  = 2
+)
+
+// File is a file of a mod
+type File struct {
+	ID          int        `json:"id"`
+	ModID       int        `json:"modId"`
+	DisplayName string     `json:"displayName"`
+	FileName    string     `json:"fileName"`
+	Hashes      []FileHash `json:"hashes"`
+	// DownloadURL is empty if the author does not allow downloads through third party launchers
+	DownloadURL  string   `json:"downloadUrl"`
+	FileLength   int64    `json:"fileLength"`
+	GameVersions []string `json:"gameVersions"`
+}
+
+// FileHash is a hash of a file
+type FileHash struct {
+	Value string `json:"value"`
+	Algo  int    `json:"algo"`
+}
+
+// Sha1 returns the sha1 hash of the file (if known)
+func (f *File) Sha1() string {
+	for _, hash := range f.Hashes {
+		if hash.Algo == HashAlgoSha1 {
+			return hash.Value
+		}
+	}
+	return ""
+}
+
+// Mod is a project on CurseForge
+type Mod struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// GetFile returns a single file of a mod
+func (c *Client) GetFile(ctx context.Context, modID int, fileID int) (*File, error) {
+	var file File
+	u := c.url("v1/mods", strconv.Itoa(modID), "files", strconv.Itoa(fileID))
+	if err := c.do(ctx, "GET", u, nil, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// GetFiles returns the files with the given ids. Unknown ids are not part of the result
+func (c *Client) GetFiles(ctx context.Context, fileIDs []int) ([]File, error) {
+	files := []File{}
+	body := map[string][]int{"fileIds": fileIDs}
+	if err := c.do(ctx, "POST", c.url("v1/mods/files"), body, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// GetMods returns the mods with the given ids. Unknown ids are not part of the result
+func (c *Client) GetMods(ctx context.Context, modIDs []int) ([]Mod, error) {
+	mods := []Mod{}
+	body := map[string][]int{"modIds": modIDs}
+	if err := c.do(ctx, "POST", c.url("v1/mods"), body, &mods); err != nil {
+		return nil, err
+	}
+	return mods, nil
+}
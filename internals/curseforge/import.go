@@ -0,0 +1,113 @@
+package curseforge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// Lookup fetches files and mods from CurseForge. `*Client` implements it
+type Lookup interface {
+	GetFiles(ctx context.Context, fileIDs []int) ([]File, error)
+	GetMods(ctx context.Context, modIDs []int) ([]Mod, error)
+}
+
+// Import is the result of converting a modpack manifest to a minepkg manifest
+type Import struct {
+	Manifest *manifest.Manifest
+	// Unresolved lists the files that could not be added as dependency
+	Unresolved []Unresolved
+}
+
+// Unresolved is a file of the modpack that could not be added as dependency
+type Unresolved struct {
+	ManifestFile
+	// Name is the name of the file or mod if it is known
+	Name   string
+	Reason string
+}
+
+// Import converts the modpack manifest to a minepkg manifest. Files become `curseforge:` dependencies,
+// files that can not be downloaded by minepkg are reported as unresolved
+func (m *Manifest) Import(ctx context.Context, lookup Lookup) (*Import, error) {
+	man := manifest.New()
+	man.Package.Type = manifest.TypeModpack
+	man.Package.Name = manifest.PackageName(m.Name)
+	man.Package.Author = m.Author
+	if _, err := semver.StrictNewVersion(m.Version); err == nil {
+		man.Package.Version = m.Version
+	}
+
+	if m.Minecraft.Version == "" {
+		return nil, fmt.Errorf("%w: the minecraft version is missing", ErrInvalidModpack)
+	}
+	man.Requirements.Minecraft = m.Minecraft.Version
+	man.Package.Platform = manifest.PlatformVanilla
+	for _, loader := range m.Minecraft.ModLoaders {
+		platform, version, err := ParseLoader(loader.ID)
+		if err != nil {
+			return nil, err
+		}
+		man.Package.Platform = platform
+		switch platform {
+		case manifest.PlatformFabric:
+			man.Requirements.FabricLoader = version
+		case manifest.PlatformForge:
+			man.Requirements.ForgeLoader = version
+		}
+	}
+
+	result := &Import{Manifest: man}
+	if len(m.Files) == 0 {
+		return result, nil
+	}
+
+	fileIDs := make([]int, 0, len(m.Files))
+	modIDs := make([]int, 0, len(m.Files))
+	for _, file := range m.Files {
+		fileIDs = append(fileIDs, file.FileID)
+		modIDs = append(modIDs, file.ProjectID)
+	}
+	files, err := lookup.GetFiles(ctx, fileIDs)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up the files on CurseForge: %w", err)
+	}
+	mods, err := lookup.GetMods(ctx, modIDs)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up the mods on CurseForge: %w", err)
+	}
+	filesByID := make(map[int]File, len(files))
+	for _, file := range files {
+		filesByID[file.ID] = file
+	}
+	modsByID := make(map[int]Mod, len(mods))
+	for _, mod := range mods {
+		modsByID[mod.ID] = mod
+	}
+
+	for _, ref := range m.Files {
+		mod, modFound := modsByID[ref.ProjectID]
+		file, fileFound := filesByID[ref.FileID]
+		switch {
+		case !ref.Required:
+			result.Unresolved = append(result.Unresolved, Unresolved{ref, mod.Name, "optional file"})
+			continue
+		case !modFound || !fileFound || file.ModID != ref.ProjectID:
+			result.Unresolved = append(result.Unresolved, Unresolved{ref, mod.Name, "not found on CurseForge"})
+			continue
+		case file.DownloadURL == "":
+			result.Unresolved = append(result.Unresolved, Unresolved{ref, mod.Name, "the author does not allow downloads through other launchers"})
+			continue
+		}
+
+		name := manifest.PackageName(mod.Slug)
+		if _, exists := man.Dependencies[name]; exists || name == "" {
+			name = fmt.Sprintf("%s-%d", name, ref.ProjectID)
+		}
+		man.AddDependency(name, ref.Source())
+	}
+
+	return result, nil
+}
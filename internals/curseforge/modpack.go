@@ -0,0 +1,175 @@
+package curseforge
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/pack"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// ManifestName is the name of the manifest in a modpack zip
+const ManifestName = "manifest.json"
+
+// DefaultOverrides is the usual name of the overrides directory in a modpack zip
+const DefaultOverrides = "overrides"
+
+var (
+	// ErrInvalidModpack is returned if the file is not a zip with a valid `manifest.json`
+	ErrInvalidModpack = errors.New("not a valid CurseForge modpack")
+	// ErrUnsupportedLoader is returned for loaders minepkg can not launch (like Quilt)
+	ErrUnsupportedLoader = errors.New("unsupported mod loader")
+	// ErrInvalidSource is returned for `curseforge:` sources that are not `projectID@fileID`
+	ErrInvalidSource = errors.New("curseforge sources have to be projectID@fileID (like 306612@3609610)")
+)
+
+// Manifest is the `manifest.json` of a modpack zip
+type Manifest struct {
+	Minecraft       ManifestMinecraft `json:"minecraft"`
+	ManifestType    string            `json:"manifestType"`
+	ManifestVersion int               `json:"manifestVersion"`
+	Name            string            `json:"name"`
+	Version         string            `json:"version"`
+	Author          string            `json:"author"`
+	Files           []ManifestFile    `json:"files"`
+	Overrides       string            `json:"overrides"`
+}
+
+// ManifestMinecraft contains the Minecraft version and loaders of a modpack
+type ManifestMinecraft struct {
+	Version    string      `json:"version"`
+	ModLoaders []ModLoader `json:"modLoaders"`
+}
+
+// ModLoader is a loader like "fabric-0.14.21" or "forge-47.1.0"
+type ModLoader struct {
+	ID      string `json:"id"`
+	Primary bool   `json:"primary"`
+}
+
+// ManifestFile references a file on CurseForge
+type ManifestFile struct {
+	ProjectID int  `json:"projectID"`
+	FileID    int  `json:"fileID"`
+	Required  bool `json:"required"`
+}
+
+// Source returns the `curseforge:` source of the file as used in the minepkg.toml
+func (f *ManifestFile) Source() string {
+	return fmt.Sprintf("curseforge:%d@%d", f.ProjectID, f.FileID)
+}
+
+// ParseSource parses a `projectID@fileID` source
+func ParseSource(source string) (projectID int, fileID int, err error) {
+	parts := strings.SplitN(source, "@", 2)
+	if len(parts) != 2 {
+		return 0, 0, ErrInvalidSource
+	}
+	if projectID, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, ErrInvalidSource
+	}
+	if fileID, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, ErrInvalidSource
+	}
+	return projectID, fileID, nil
+}
+
+// ParseLoader splits a loader id like "fabric-0.14.21" into the minepkg platform and the version
+func ParseLoader(id string) (platform string, version string, err error) {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedLoader, id)
+	}
+	switch parts[0] {
+	case manifest.PlatformFabric, manifest.PlatformForge:
+		return parts[0], parts[1], nil
+	default:
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedLoader, id)
+	}
+}
+
+// LoaderID returns the loader id of a minepkg platform (like "fabric-0.14.21")
+func LoaderID(platform string, version string) string {
+	return platform + "-" + version
+}
+
+// Modpack is an opened modpack zip
+type Modpack struct {
+	Manifest *Manifest
+
+	file   *os.File
+	reader *pack.Reader
+}
+
+// Open opens the modpack zip and reads its manifest
+func Open(p string) (*Modpack, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	man, err := readManifest(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &Modpack{
+		Manifest: man,
+		file:     f,
+		reader:   pack.NewReader(f, info.Size()),
+	}, nil
+}
+
+func readManifest(r io.ReaderAt, size int64) (*Manifest, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidModpack
+	}
+
+	for _, f := range archive.File {
+		if f.Name != ManifestName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		man := &Manifest{}
+		if err := json.NewDecoder(rc).Decode(man); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidModpack, err)
+		}
+		if man.ManifestType != "minecraftModpack" {
+			return nil, fmt.Errorf("%w: unsupported manifest type %q", ErrInvalidModpack, man.ManifestType)
+		}
+		return man, nil
+	}
+	return nil, fmt.Errorf("%w: %s is missing", ErrInvalidModpack, ManifestName)
+}
+
+// ExtractOverrides extracts the overrides of the modpack to dest
+func (m *Modpack) ExtractOverrides(dest string) error {
+	overrides := m.Manifest.Overrides
+	if overrides == "" {
+		overrides = DefaultOverrides
+	}
+	return m.reader.ExtractDir(overrides, dest)
+}
+
+// Close closes the underlying file
+func (m *Modpack) Close() error {
+	return m.file.Close()
+}
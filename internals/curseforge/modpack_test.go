@@ -0,0 +1,126 @@
+package curseforge
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLoader(t *testing.T) {
+	tests := []struct {
+		id       string
+		platform string
+		version  string
+		wantErr  bool
+	}{
+		{"fabric-0.14.21", "fabric", "0.14.21", false},
+		{"forge-47.1.0", "forge", "47.1.0", false},
+		{"quilt-0.19.0", "", "", true},
+		{"fabric", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			platform, version, err := ParseLoader(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if platform != tt.platform || version != tt.version {
+				t.Fatalf("got %s %s", platform, version)
+			}
+		})
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	projectID, fileID, err := ParseSource("306612@3609610")
+	if err != nil || projectID != 306612 || fileID != 3609610 {
+		t.Fatalf("unexpected result %d %d (%v)", projectID, fileID, err)
+	}
+	if _, _, err := ParseSource("fabric-api"); !errors.Is(err, ErrInvalidSource) {
+		t.Fatalf("expected ErrInvalidSource, got %v", err)
+	}
+}
+
+type fakeLookup struct{}
+
+func (f *fakeLookup) GetFiles(ctx context.Context, fileIDs []int) ([]File, error) {
+	return []File{
+		{ID: 10, ModID: 1, DownloadURL: "https://edge.forgecdn.net/files/10/fabric-api.jar"},
+		{ID: 20, ModID: 2},
+	}, nil
+}
+
+func (f *fakeLookup) GetMods(ctx context.Context, modIDs []int) ([]Mod, error) {
+	return []Mod{{ID: 1, Name: "Fabric API", Slug: "fabric-api"}, {ID: 2, Name: "Private", Slug: "private"}}, nil
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	embedded := filepath.Join(dir, "embedded.jar")
+	ioutil.WriteFile(embedded, []byte("jar"), 0644)
+	overrides := filepath.Join(dir, "overwrites")
+	os.MkdirAll(filepath.Join(overrides, "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(overrides, "config", "test.json"), []byte("{}"), 0644)
+
+	buf := &bytes.Buffer{}
+	_, err := Write(buf, &Pack{
+		Name:      "Test Pack",
+		Version:   "1.2.0",
+		Author:    "someone",
+		Minecraft: "1.19.4",
+		Loader:    "fabric-0.14.21",
+		Files: []ManifestFile{
+			{ProjectID: 1, FileID: 10, Required: true},
+			{ProjectID: 2, FileID: 20, Required: true},
+			{ProjectID: 3, FileID: 30, Required: true},
+			{ProjectID: 1, FileID: 11, Required: false},
+		},
+		Embedded:  []EmbeddedMod{{Path: embedded, Filename: "embedded.jar"}},
+		Overrides: overrides,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	packFile := filepath.Join(dir, "pack.zip")
+	if err := ioutil.WriteFile(packFile, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modpack, err := Open(packFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer modpack.Close()
+
+	imported, err := modpack.Manifest.Import(context.Background(), &fakeLookup{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	man := imported.Manifest
+	if man.Package.Name != "test-pack" || man.Package.Version != "1.2.0" || man.Package.Platform != "fabric" {
+		t.Fatalf("unexpected package %+v", man.Package)
+	}
+	if man.Requirements.Minecraft != "1.19.4" || man.Requirements.FabricLoader != "0.14.21" {
+		t.Fatalf("unexpected requirements %+v", man.Requirements)
+	}
+	if len(man.Dependencies) != 1 || man.Dependencies["fabric-api"] != "curseforge:1@10" {
+		t.Fatalf("unexpected dependencies %v", man.Dependencies)
+	}
+	if len(imported.Unresolved) != 3 {
+		t.Fatalf("expected 3 unresolved files, got %+v", imported.Unresolved)
+	}
+
+	target := t.TempDir()
+	if err := modpack.ExtractOverrides(target); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"config/test.json", "mods/embedded.jar"} {
+		if _, err := os.Stat(filepath.Join(target, expected)); err != nil {
+			t.Fatalf("expected %s to be extracted", expected)
+		}
+	}
+}
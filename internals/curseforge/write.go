@@ -0,0 +1,82 @@
+package curseforge
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"path"
+
+	"github.com/minepkg/minepkg/internals/pack"
+)
+
+// Pack describes a modpack to write
+type Pack struct {
+	Name    string
+	Version string
+	Author  string
+	// Minecraft is the Minecraft version
+	Minecraft string
+	// Loader is the loader id like "fabric-0.14.21". Can be empty for vanilla packs
+	Loader string
+	// Files are the mods that are downloaded from CurseForge
+	Files []ManifestFile
+	// Embedded are mods that are not on CurseForge. They are written to overrides/mods
+	Embedded []EmbeddedMod
+	// Overrides is a directory that is copied into the Minecraft directory. Can be empty
+	Overrides string
+}
+
+// EmbeddedMod is a mod jar that is embedded in the pack
+type EmbeddedMod struct {
+	// Path is the location of the jar on disk
+	Path string
+	// Filename is the name of the file in the mods directory
+	Filename string
+}
+
+// Write writes the pack as CurseForge modpack zip to w. Returns the written manifest
+func Write(w io.Writer, p *Pack) (*Manifest, error) {
+	man := &Manifest{
+		Minecraft:       ManifestMinecraft{Version: p.Minecraft, ModLoaders: []ModLoader{}},
+		ManifestType:    "minecraftModpack",
+		ManifestVersion: 1,
+		Name:            p.Name,
+		Version:         p.Version,
+		Author:          p.Author,
+		Files:           p.Files,
+		Overrides:       DefaultOverrides,
+	}
+	if p.Loader != "" {
+		man.Minecraft.ModLoaders = append(man.Minecraft.ModLoaders, ModLoader{ID: p.Loader, Primary: true})
+	}
+	if man.Files == nil {
+		man.Files = []ManifestFile{}
+	}
+
+	archive := zip.NewWriter(w)
+
+	for _, mod := range p.Embedded {
+		if err := pack.AddZipFile(archive, mod.Path, path.Join(DefaultOverrides, "mods", mod.Filename)); err != nil {
+			return nil, err
+		}
+	}
+
+	err := pack.WalkOverrides(p.Overrides, func(src string, name string) error {
+		return pack.AddZipFile(archive, src, path.Join(DefaultOverrides, name))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifestWriter, err := archive.Create(ManifestName)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(manifestWriter)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(man); err != nil {
+		return nil, err
+	}
+
+	return man, archive.Close()
+}
@@ -3,9 +3,12 @@ package globals
 import (
 	"github.com/minepkg/minepkg/internals/api"
 	"github.com/minepkg/minepkg/internals/cmdlog"
+	"github.com/minepkg/minepkg/internals/curseforge"
 )
 
 var (
 	ApiClient = api.New()
 	Logger    = cmdlog.New()
+	// CurseForgeClient is used for `curseforge:` dependencies
+	CurseForgeClient = curseforge.New()
 )
@@ -1,6 +1,8 @@
 package instances
 
 type LaunchCredentials struct {
+	// Account is the name of the minepkg account these credentials belong to
+	Account     string
 	PlayerName  string
 	UUID        string
 	AccessToken string
@@ -0,0 +1,115 @@
+package instances
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/backup"
+)
+
+// BackupSettings configure the world backups of this instance. They live in the `[backup]` section of the local settings
+type BackupSettings struct {
+	// Format is the archive format: "zip" (default) or "tar.zst"
+	Format string `toml:"format,omitempty"`
+	// Every is the interval of scheduled backups while the server is running (eg. "6h")
+	Every string `toml:"every,omitempty"`
+	// KeepLast keeps the newest n backups when pruning
+	KeepLast int `toml:"keepLast,omitempty"`
+	// KeepHourly keeps one backup for each of the last n hours when pruning
+	KeepHourly int `toml:"keepHourly,omitempty"`
+	// KeepDaily keeps one backup for each of the last n days when pruning
+	KeepDaily int `toml:"keepDaily,omitempty"`
+}
+
+// Policy returns the retention policy of these settings
+func (b *BackupSettings) Policy() backup.Policy {
+	return backup.Policy{KeepLast: b.KeepLast, KeepHourly: b.KeepHourly, KeepDaily: b.KeepDaily}
+}
+
+// Interval returns the parsed `Every` setting. Returns 0 if no schedule is set
+func (b *BackupSettings) Interval() (time.Duration, error) {
+	if b.Every == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(b.Every)
+}
+
+// BackupsDir contains the world backups of this instance
+func (i *Instance) BackupsDir() string {
+	return filepath.Join(i.Directory, "backups")
+}
+
+// BackupStore returns the store containing the backups of this instance
+func (i *Instance) BackupStore() *backup.Store {
+	return backup.NewStore(i.BackupsDir())
+}
+
+// BackupSettings returns the `[backup]` local settings. Never returns nil settings without an error
+func (i *Instance) BackupSettings() (*BackupSettings, error) {
+	settings, err := i.LocalSettings()
+	if err != nil {
+		return nil, err
+	}
+	if settings.Backup == nil {
+		return &BackupSettings{}, nil
+	}
+	return settings.Backup, nil
+}
+
+// WorldPaths returns the paths of all worlds relative to `McDir`.
+// These are the singleplayer saves and the world of the server
+func (i *Instance) WorldPaths() ([]string, error) {
+	paths := make([]string, 0)
+
+	saves, err := os.ReadDir(filepath.Join(i.McDir(), "saves"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, save := range saves {
+		if save.IsDir() {
+			paths = append(paths, filepath.Join("saves", save.Name()))
+		}
+	}
+
+	props, err := i.ServerProperties()
+	if err != nil {
+		return nil, err
+	}
+	level := props.Value("level-name")
+	if level == "" {
+		level = "world"
+	}
+	if info, err := os.Stat(filepath.Join(i.McDir(), level)); err == nil && info.IsDir() {
+		paths = append(paths, level)
+	}
+
+	return paths, nil
+}
+
+// CreateBackup backs up all worlds of this instance. If server is set, auto saving is paused
+// during the backup so the world files are consistent
+func (i *Instance) CreateBackup(server backup.Commander, format backup.Format) (*backup.Backup, error) {
+	paths, err := i.WorldPaths()
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, backup.ErrNothingToBackup
+	}
+
+	if server != nil {
+		resume, err := backup.PauseSaving(server)
+		if err != nil {
+			return nil, err
+		}
+		defer resume()
+	}
+
+	return i.BackupStore().Create(i.McDir(), paths, format)
+}
+
+// RestoreBackup replaces the worlds of this instance with the ones in the backup
+func (i *Instance) RestoreBackup(b *backup.Backup) error {
+	return backup.Restore(b, i.McDir())
+}
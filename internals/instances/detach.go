@@ -0,0 +1,308 @@
+package instances
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ErrNotRunning is returned if an instance is not running in the background
+var ErrNotRunning = errors.New("instance is not running")
+
+// RunInfo describes an instance that was started in the background
+type RunInfo struct {
+	// PID is the process id of the java process
+	PID int `json:"pid"`
+	// Name is the name of the instance (usually the modpack name)
+	Name string `json:"name"`
+	// Directory is the instance directory
+	Directory string `json:"directory"`
+	// Server is true if this is a server
+	Server bool `json:"server"`
+	// Started is the time the process was started
+	Started time.Time `json:"started"`
+	// LogFile contains the stdout & stderr output of the process
+	LogFile string `json:"logFile"`
+
+	entry string
+}
+
+// PidFilePath is the path to the pidfile of this instance. It only exists while the instance runs detached
+func (i *Instance) PidFilePath() string {
+	return filepath.Join(i.Directory, "minepkg.pid")
+}
+
+// RunLockPath is the path to the lock of a launch in the foreground. It only exists while minepkg runs the instance
+func (i *Instance) RunLockPath() string {
+	return filepath.Join(i.Directory, "minepkg.run.json")
+}
+
+// LogFilePath is the path to the file containing the output of the last detached launch
+func (i *Instance) LogFilePath() string {
+	return filepath.Join(i.Directory, "minepkg.log")
+}
+
+// RunningDir contains an entry for every instance that runs in the background
+func (i *Instance) RunningDir() string {
+	return filepath.Join(i.GlobalDir, "running")
+}
+
+// Name returns the name of this instance
+func (i *Instance) Name() string {
+	if i.Manifest.Package.BasedOn != "" {
+		return i.Manifest.Package.BasedOn
+	}
+	return i.Manifest.Package.Name
+}
+
+// runningEntry is the path of the entry of this instance in the `RunningDir`
+func (i *Instance) runningEntry() string {
+	dir, _ := filepath.Abs(i.Directory)
+	sum := sha1.Sum([]byte(dir))
+	return filepath.Join(i.RunningDir(), hex.EncodeToString(sum[:6])+".json")
+}
+
+// LaunchDetached starts the instance in the background. The output is written to `LogFilePath`
+// and the process keeps running after minepkg exits
+func (i *Instance) LaunchDetached(opts *LaunchOptions) (*RunInfo, error) {
+	if info, err := i.RunInfo(); err == nil {
+		return nil, fmt.Errorf("%s is already running with pid %d", info.Name, info.PID)
+	}
+	if info, err := i.ForegroundRunInfo(); err == nil {
+		return nil, fmt.Errorf("%s is already running with pid %d", info.Name, info.PID)
+	}
+
+	logFile, err := os.Create(i.LogFilePath())
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+
+	opts.Stdout = logFile
+	opts.Stderr = logFile
+	cmd, err := i.BuildLaunchCmd(opts)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdin = nil
+	detach(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	info := &RunInfo{
+		PID:       cmd.Process.Pid,
+		Name:      i.Name(),
+		Directory: i.Directory,
+		Server:    opts.Server,
+		Started:   time.Now(),
+		LogFile:   i.LogFilePath(),
+		entry:     i.runningEntry(),
+	}
+
+	// we do not wait for the process, it should outlive us
+	if err := cmd.Process.Release(); err != nil {
+		return nil, err
+	}
+
+	if err := ioutil.WriteFile(i.PidFilePath(), []byte(strconv.Itoa(info.PID)+"\n"), 0644); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(i.RunningDir(), os.ModePerm); err != nil {
+		return nil, err
+	}
+	raw, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(info.entry, raw, 0644); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// RunInfo returns the details of this instance if it is running in the background.
+// Returns `ErrNotRunning` otherwise
+func (i *Instance) RunInfo() (*RunInfo, error) {
+	info, err := readRunInfo(i.runningEntry())
+	if os.IsNotExist(err) {
+		return nil, ErrNotRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !info.Alive() {
+		info.Cleanup()
+		return nil, ErrNotRunning
+	}
+	return info, nil
+}
+
+// ForegroundRunInfo returns the details of this instance if a minepkg process runs it in the foreground.
+// Returns `ErrNotRunning` otherwise
+func (i *Instance) ForegroundRunInfo() (*RunInfo, error) {
+	info, err := readRunInfo(i.RunLockPath())
+	if os.IsNotExist(err) {
+		return nil, ErrNotRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the lock is left behind if minepkg did not exit normally
+	if !info.Alive() {
+		os.Remove(i.RunLockPath())
+		return nil, ErrNotRunning
+	}
+	return info, nil
+}
+
+// LockRun marks this instance as running in the foreground by the current process.
+// Call the returned function after the instance stopped
+func (i *Instance) LockRun(server bool) (func(), error) {
+	if info, err := i.RunInfo(); err == nil {
+		return nil, fmt.Errorf("%s is already running in the background with pid %d", info.Name, info.PID)
+	}
+	if info, err := i.ForegroundRunInfo(); err == nil {
+		return nil, fmt.Errorf("%s is already running with pid %d", info.Name, info.PID)
+	}
+
+	info := &RunInfo{
+		PID:       os.Getpid(),
+		Name:      i.Name(),
+		Directory: i.Directory,
+		Server:    server,
+		Started:   time.Now(),
+	}
+	// the start time has to be the one of the process, see `RunInfo.Alive`
+	if self, err := info.Process(); err == nil {
+		if created, err := self.CreateTime(); err == nil {
+			info.Started = time.UnixMilli(created)
+		}
+	}
+
+	raw, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(i.RunLockPath(), raw, 0644); err != nil {
+		return nil, err
+	}
+	return func() { os.Remove(i.RunLockPath()) }, nil
+}
+
+// Running returns all instances that are running in the background.
+// Entries of processes that are not running anymore are removed
+func (i *Instance) Running() ([]*RunInfo, error) {
+	entries, err := os.ReadDir(i.RunningDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*RunInfo{}, nil
+		}
+		return nil, err
+	}
+
+	running := make([]*RunInfo, 0, len(entries))
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		entry := filepath.Join(i.RunningDir(), e.Name())
+		info, err := readRunInfo(entry)
+		if err != nil {
+			// broken entry
+			os.Remove(entry)
+			continue
+		}
+		if !info.Alive() {
+			info.Cleanup()
+			continue
+		}
+		running = append(running, info)
+	}
+
+	return running, nil
+}
+
+func readRunInfo(entry string) (*RunInfo, error) {
+	raw, err := ioutil.ReadFile(entry)
+	if err != nil {
+		return nil, err
+	}
+	info := &RunInfo{entry: entry}
+	if err := json.Unmarshal(raw, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Process returns the running process
+func (r *RunInfo) Process() (*process.Process, error) {
+	return process.NewProcess(int32(r.PID))
+}
+
+// Alive checks if the process is still running.
+// The start time is compared to make sure the pid was not reused by another process
+func (r *RunInfo) Alive() bool {
+	p, err := r.Process()
+	if err != nil {
+		return false
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		return false
+	}
+	diff := time.UnixMilli(created).Sub(r.Started)
+	return diff < 10*time.Second && diff > -10*time.Second
+}
+
+// Uptime returns the time since the process was started
+func (r *RunInfo) Uptime() time.Duration {
+	return time.Since(r.Started)
+}
+
+// Stop gracefully stops the process. It is killed if it did not stop after `timeout`
+func (r *RunInfo) Stop(timeout time.Duration) error {
+	p, err := os.FindProcess(r.PID)
+	if err != nil {
+		return err
+	}
+
+	if err := terminate(p); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !r.Alive() {
+			r.Cleanup()
+			return nil
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+
+	if err := p.Kill(); err != nil {
+		return err
+	}
+	r.Cleanup()
+	return fmt.Errorf("%s did not stop within %s and was killed", r.Name, timeout)
+}
+
+// Cleanup removes the pidfile and the running entry
+func (r *RunInfo) Cleanup() {
+	os.Remove(filepath.Join(r.Directory, "minepkg.pid"))
+	if r.entry != "" {
+		os.Remove(r.entry)
+	}
+}
@@ -0,0 +1,75 @@
+package instances
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+func TestInstance_Running(t *testing.T) {
+	i := &Instance{GlobalDir: t.TempDir()}
+	os.MkdirAll(i.RunningDir(), os.ModePerm)
+
+	self, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := self.CreateTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(name string, info *RunInfo) {
+		raw, _ := json.Marshal(info)
+		os.WriteFile(filepath.Join(i.RunningDir(), name), raw, 0644)
+	}
+	write("alive.json", &RunInfo{PID: os.Getpid(), Name: "alive", Started: time.UnixMilli(created)})
+	// same pid, but started a long time ago. so the pid was reused
+	write("reused.json", &RunInfo{PID: os.Getpid(), Name: "reused", Started: time.UnixMilli(created).Add(-time.Hour)})
+	os.WriteFile(filepath.Join(i.RunningDir(), "broken.json"), []byte("{"), 0644)
+
+	running, err := i.Running()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(running) != 1 || running[0].Name != "alive" {
+		t.Fatalf("expected only the alive instance, got %+v", running)
+	}
+
+	entries, _ := os.ReadDir(i.RunningDir())
+	if len(entries) != 1 {
+		t.Fatalf("stale entries should be removed, got %d entries", len(entries))
+	}
+}
+
+func TestInstance_LockRun(t *testing.T) {
+	i := &Instance{GlobalDir: t.TempDir(), Directory: t.TempDir(), Manifest: manifest.New()}
+
+	unlock, err := i.LockRun(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := i.ForegroundRunInfo(); err != nil {
+		t.Fatalf("expected the instance to be running, got %v", err)
+	}
+	if _, err := i.LockRun(false); err == nil {
+		t.Fatal("expected the second lock to fail")
+	}
+
+	unlock()
+	if _, err := i.ForegroundRunInfo(); err != ErrNotRunning {
+		t.Fatalf("expected ErrNotRunning, got %v", err)
+	}
+
+	// locks of processes that are gone are ignored
+	raw, _ := json.Marshal(&RunInfo{PID: os.Getpid(), Started: time.Now().Add(-time.Hour)})
+	os.WriteFile(i.RunLockPath(), raw, 0644)
+	if _, err := i.ForegroundRunInfo(); err != ErrNotRunning {
+		t.Fatalf("expected stale lock to be ignored, got %v", err)
+	}
+}
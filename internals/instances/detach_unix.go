@@ -0,0 +1,19 @@
+//go:build !windows
+
+package instances
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the process in its own session, so it is not stopped with the terminal
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// terminate asks the process to stop. Minecraft saves and stops on SIGTERM
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package instances
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const (
+	createNewProcessGroup = 0x00000200
+	detachedProcess       = 0x00000008
+)
+
+// detach starts the process without a console, so it is not stopped with the terminal
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: createNewProcessGroup | detachedProcess}
+}
+
+// terminate stops the process. Windows has no signals that a detached java process
+// would react to, so it has to be killed
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
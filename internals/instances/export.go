@@ -0,0 +1,37 @@
+package instances
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// PackFile is a downloaded dependency of the instance
+type PackFile struct {
+	Lock *manifest.DependencyLock
+	// Path is the location of the file in the package cache
+	Path string
+}
+
+// PackFiles returns the mod files of all locked dependencies (without dev dependencies) sorted by name.
+// Modpack dependencies can not be exported as single files, they are returned as skipped
+func (i *Instance) PackFiles() (files []PackFile, skipped []*manifest.DependencyLock) {
+	for _, dep := range i.Lockfile.Dependencies {
+		// skip packages with no binary
+		if dep.URL == "" || dep.IsDev {
+			continue
+		}
+		if dep.Type == manifest.DependencyLockTypeModpack {
+			skipped = append(skipped, dep)
+			continue
+		}
+		files = append(files, PackFile{
+			Lock: dep,
+			Path: filepath.Join(i.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt()),
+		})
+	}
+
+	sort.Slice(files, func(a, b int) bool { return files[a].Lock.Name < files[b].Lock.Name })
+	return files, skipped
+}
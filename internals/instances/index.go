@@ -0,0 +1,256 @@
+package instances
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrInstanceNotFound is returned if no instance with the given name exists
+	ErrInstanceNotFound = errors.New("instance not found")
+	// ErrInstanceExists is returned if an instance with the given name already exists
+	ErrInstanceExists = errors.New("an instance with this name already exists")
+	// ErrInvalidInstanceName is returned for names that can not be used as directory name
+	ErrInvalidInstanceName = errors.New("invalid instance name (use letters, numbers, ., - and _)")
+)
+
+var instanceNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// IndexEntry contains the metadata of an instance in the instances directory
+type IndexEntry struct {
+	// Name is the name of the instance. It also is the name of its directory
+	Name string `json:"name"`
+	// Created is the time the instance was created (or first seen)
+	Created time.Time `json:"created"`
+	// LastPlayed is the time the instance was launched the last time
+	LastPlayed time.Time `json:"lastPlayed"`
+}
+
+// Index lists the instances in the instances directory. It is saved as `index.json` in there
+type Index struct {
+	Instances []*IndexEntry `json:"instances"`
+
+	dir string
+}
+
+// LoadIndex reads the index of the instances directory dir. Instances that were created
+// without an index entry are added and entries of removed instances are dropped
+func LoadIndex(dir string) (*Index, error) {
+	index := &Index{Instances: []*IndexEntry{}, dir: dir}
+
+	raw, err := ioutil.ReadFile(index.path())
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, index); err != nil {
+			return nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	found := make(map[string]fs.DirEntry, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), "minepkg.toml")); err == nil {
+			found[entry.Name()] = entry
+		}
+	}
+
+	known := index.Instances[:0]
+	for _, instance := range index.Instances {
+		if _, ok := found[instance.Name]; ok {
+			known = append(known, instance)
+			delete(found, instance.Name)
+		}
+	}
+	index.Instances = known
+	for name, entry := range found {
+		created := time.Now()
+		if info, err := entry.Info(); err == nil {
+			created = info.ModTime()
+		}
+		index.Instances = append(index.Instances, &IndexEntry{Name: name, Created: created})
+	}
+
+	sort.Slice(index.Instances, func(a, b int) bool {
+		return index.Instances[a].Name < index.Instances[b].Name
+	})
+	return index, nil
+}
+
+func (x *Index) path() string {
+	return filepath.Join(x.dir, "index.json")
+}
+
+// Save writes the index to disk
+func (x *Index) Save() error {
+	raw, err := json.MarshalIndent(x, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(x.dir, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(x.path(), raw, 0644)
+}
+
+// Get returns the entry of the instance with the given name
+func (x *Index) Get(name string) (*IndexEntry, error) {
+	for _, entry := range x.Instances {
+		if entry.Name == name {
+			return entry, nil
+		}
+	}
+	return nil, ErrInstanceNotFound
+}
+
+// Dir returns the directory of the instance
+func (x *Index) Dir(entry *IndexEntry) string {
+	return filepath.Join(x.dir, entry.Name)
+}
+
+// Instance opens the instance with the given name
+func (x *Index) Instance(name string) (*Instance, error) {
+	entry, err := x.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromDir(x.Dir(entry))
+}
+
+// checkNewName returns an error if name can not be used for a new instance
+func (x *Index) checkNewName(name string) error {
+	if !instanceNameRegex.MatchString(name) {
+		return ErrInvalidInstanceName
+	}
+	if _, err := x.Get(name); err == nil {
+		return ErrInstanceExists
+	}
+	if _, err := os.Stat(filepath.Join(x.dir, name)); err == nil {
+		return ErrInstanceExists
+	}
+	return nil
+}
+
+// Rename renames the instance and moves its directory
+func (x *Index) Rename(name string, newName string) error {
+	entry, err := x.Get(name)
+	if err != nil {
+		return err
+	}
+	if err := x.checkNewName(newName); err != nil {
+		return err
+	}
+	if err := os.Rename(x.Dir(entry), filepath.Join(x.dir, newName)); err != nil {
+		return err
+	}
+	entry.Name = newName
+	return nil
+}
+
+// Clone copies the instance with all its worlds and settings to a new instance
+func (x *Index) Clone(name string, newName string) (*IndexEntry, error) {
+	entry, err := x.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := x.checkNewName(newName); err != nil {
+		return nil, err
+	}
+
+	target := filepath.Join(x.dir, newName)
+	if err := copyInstanceDir(x.Dir(entry), target); err != nil {
+		os.RemoveAll(target)
+		return nil, err
+	}
+
+	clone := &IndexEntry{Name: newName, Created: time.Now()}
+	x.Instances = append(x.Instances, clone)
+	return clone, nil
+}
+
+// Remove deletes the instance directory with all its worlds
+func (x *Index) Remove(name string) error {
+	entry, err := x.Get(name)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(x.Dir(entry)); err != nil {
+		return err
+	}
+
+	remaining := x.Instances[:0]
+	for _, other := range x.Instances {
+		if other != entry {
+			remaining = append(remaining, other)
+		}
+	}
+	x.Instances = remaining
+	return nil
+}
+
+// MarkPlayed sets the last played time of this instance if it lives in the instances directory
+func (i *Instance) MarkPlayed() {
+	if filepath.Dir(i.Directory) != i.InstancesDir() {
+		return
+	}
+	index, err := LoadIndex(i.InstancesDir())
+	if err != nil {
+		log.Println("Could not read the instance index:", err)
+		return
+	}
+	entry, err := index.Get(filepath.Base(i.Directory))
+	if err != nil {
+		return
+	}
+	entry.LastPlayed = time.Now()
+	if err := index.Save(); err != nil {
+		log.Println("Could not save the instance index:", err)
+	}
+}
+
+// copyInstanceDir copies all files of an instance. Symlinks (like linked mods) stay symlinks
+func copyInstanceDir(src string, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		// files of the running process belong to the original instance
+		if rel == "minepkg.pid" || rel == "minepkg.log" || rel == "minepkg.run.json" {
+			return nil
+		}
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.IsDir():
+			return os.MkdirAll(target, os.ModePerm)
+		default:
+			return copyFileContents(path, target)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package instances
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createInstanceDir(t *testing.T, dir string, name string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Join(path, "minecraft", "saves", "world"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "manifestVersion = 0\n[package]\ntype = \"modpack\"\nname = \"" + name + "\"\n[requirements]\nminecraft = \"1.18.2\"\n"
+	if err := os.WriteFile(filepath.Join(path, "minepkg.toml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(path, "minecraft", "saves", "world", "level.dat"), []byte("level"), 0644)
+}
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	createInstanceDir(t, dir, "test-mansion_fabric")
+	createInstanceDir(t, dir, "server.example.com.pack.fabric")
+	os.MkdirAll(filepath.Join(dir, "not-an-instance"), os.ModePerm)
+
+	index, err := LoadIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Instances) != 2 || index.Instances[0].Name != "server.example.com.pack.fabric" {
+		t.Fatalf("unexpected instances %+v", index.Instances)
+	}
+
+	if err := index.Rename("server.example.com.pack.fabric", "pack"); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Rename("pack", "test-mansion_fabric"); !errors.Is(err, ErrInstanceExists) {
+		t.Fatalf("expected ErrInstanceExists, got %v", err)
+	}
+	if err := index.Rename("pack", "../outside"); !errors.Is(err, ErrInvalidInstanceName) {
+		t.Fatalf("expected ErrInvalidInstanceName, got %v", err)
+	}
+
+	if _, err := index.Clone("pack", "pack-copy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "pack-copy", "minecraft", "saves", "world", "level.dat")); err != nil {
+		t.Fatal("expected worlds to be cloned")
+	}
+	instance, err := index.Instance("pack-copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instance.Manifest.Package.Name != "server.example.com.pack.fabric" {
+		t.Fatalf("unexpected cloned manifest %+v", instance.Manifest.Package)
+	}
+
+	if err := index.Remove("test-mansion_fabric"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test-mansion_fabric")); !os.IsNotExist(err) {
+		t.Fatal("expected instance directory to be removed")
+	}
+	if err := index.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err = LoadIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, entry := range index.Instances {
+		names = append(names, entry.Name)
+	}
+	if len(names) != 2 || names[0] != "pack" || names[1] != "pack-copy" {
+		t.Fatalf("unexpected instances after reload %v", names)
+	}
+}
+
+func TestInstance_MarkPlayed(t *testing.T) {
+	global := t.TempDir()
+	instance := &Instance{GlobalDir: global}
+	createInstanceDir(t, instance.InstancesDir(), "pack")
+	instance.Directory = filepath.Join(instance.InstancesDir(), "pack")
+
+	instance.MarkPlayed()
+
+	index, err := LoadIndex(instance.InstancesDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := index.Get("pack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.LastPlayed.IsZero() {
+		t.Fatal("expected last played to be set")
+	}
+}
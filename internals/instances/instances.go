@@ -114,6 +114,11 @@ func (i *Instance) OverwritesDir() string {
 	return filepath.Join(i.Directory, "overwrites")
 }
 
+// CrashReportsDir contains the crash reports saved by minepkg
+func (i *Instance) CrashReportsDir() string {
+	return filepath.Join(i.Directory, "crashes")
+}
+
 // ManifestPath is the path to the `minepkg.toml`. The file does not necessarily exist
 func (i *Instance) ManifestPath() string {
 	return filepath.Join(i.Directory, "minepkg.toml")
@@ -174,7 +179,7 @@ func New() *Instance {
 
 // NewFromDir tries to detect a instance in the given directory
 func NewFromDir(dir string) (*Instance, error) {
-	manifestToml, err := ioutil.ReadFile("./minepkg.toml")
+	manifestToml, err := ioutil.ReadFile(filepath.Join(dir, "minepkg.toml"))
 	if err != nil {
 		// TODO only for not found errors
 		return nil, ErrNoInstance
@@ -0,0 +1,120 @@
+package instances
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/pbnjay/memory"
+)
+
+// gcPresets are the jvm flags of all garbage collector presets
+var gcPresets = map[string][]string{
+	manifest.GCG1: {
+		"-XX:+UnlockExperimentalVMOptions",
+		"-XX:+UseG1GC",
+		"-XX:G1NewSizePercent=20",
+		"-XX:G1ReservePercent=20",
+		"-XX:MaxGCPauseMillis=50",
+		"-XX:G1HeapRegionSize=32M",
+	},
+	manifest.GCZGC: {
+		"-XX:+UseZGC",
+	},
+	// see https://mcflags.emc.gs
+	manifest.GCAikar: {
+		"-XX:+UseG1GC",
+		"-XX:+ParallelRefProcEnabled",
+		"-XX:MaxGCPauseMillis=200",
+		"-XX:+UnlockExperimentalVMOptions",
+		"-XX:+DisableExplicitGC",
+		"-XX:+AlwaysPreTouch",
+		"-XX:G1NewSizePercent=30",
+		"-XX:G1MaxNewSizePercent=40",
+		"-XX:G1HeapRegionSize=8M",
+		"-XX:G1ReservePercent=20",
+		"-XX:G1HeapWastePercent=5",
+		"-XX:G1MixedGCCountTarget=4",
+		"-XX:InitiatingHeapOccupancyPercent=15",
+		"-XX:G1MixedGCLiveThresholdPercent=90",
+		"-XX:G1RSetUpdatingPauseTimePercent=5",
+		"-XX:SurvivorRatio=32",
+		"-XX:+PerfDisableSharedMem",
+		"-XX:MaxTenuringThreshold=1",
+		"-Dusing.aikars.flags=https://mcflags.emc.gs",
+		"-Daikars.new.flags=true",
+	},
+	manifest.GCNone: {},
+}
+
+// LaunchConfig returns the `[launch]` options of the manifest merged with
+// the ones from the local settings (which take precedence)
+func (i *Instance) LaunchConfig() (*manifest.Launch, error) {
+	settings, err := i.LocalSettings()
+	if err != nil {
+		return nil, err
+	}
+	return i.Manifest.Launch.Merge(settings.Launch), nil
+}
+
+// jvmArgs returns the memory, garbage collector and additional jvm arguments
+func (i *Instance) jvmArgs(cfg *manifest.Launch, opts *LaunchOptions, javaVersion int) ([]string, error) {
+	minRamMiB, err := manifest.ParseMemory(cfg.MinMemory)
+	if err != nil {
+		return nil, fmt.Errorf("launch.minMemory: %w", err)
+	}
+	maxRamMiB, err := manifest.ParseMemory(cfg.MaxMemory)
+	if err != nil {
+		return nil, fmt.Errorf("launch.maxMemory: %w", err)
+	}
+
+	// --ram overwrites everything
+	if opts.RamMiB != 0 {
+		minRamMiB = opts.RamMiB
+		maxRamMiB = opts.RamMiB
+	}
+	if maxRamMiB == 0 {
+		maxRamMiB = i.defaultMaxRamMiB()
+	}
+
+	gc := cfg.GC
+	if gc == "" {
+		gc = manifest.GCG1
+	}
+	gcArgs, ok := gcPresets[gc]
+	if !ok {
+		return nil, fmt.Errorf("launch.gc: unknown preset %s", gc)
+	}
+	if gc == manifest.GCZGC && javaVersion != 0 && javaVersion < 17 {
+		return nil, fmt.Errorf("launch.gc: the zgc preset requires Java 17 or newer but Java %d is used", javaVersion)
+	}
+	// aikar recommends using the same min & max memory
+	if gc == manifest.GCAikar && minRamMiB == 0 {
+		minRamMiB = maxRamMiB
+	}
+
+	args := make([]string, 0, len(gcArgs)+len(cfg.JVMArgs)+len(opts.JVMArgs)+2)
+	if minRamMiB != 0 {
+		args = append(args, fmt.Sprintf("-Xms%dM", minRamMiB))
+	}
+	args = append(args, fmt.Sprintf("-Xmx%dM", maxRamMiB))
+	args = append(args, gcArgs...)
+	args = append(args, cfg.JVMArgs...)
+	// flags passed via cli come last, so they win
+	args = append(args, opts.JVMArgs...)
+
+	return args, nil
+}
+
+// defaultMaxRamMiB determines the max amount of ram by modcount & available system ram
+func (i *Instance) defaultMaxRamMiB() int {
+	sysMemMiB := float64(memory.TotalMemory()) / 1024 / 1024
+
+	// 1GiB for base Minecraft + every dependency takes 25 MiB
+	maxRamMiB := 1024 + len(i.Lockfile.Dependencies)*25
+
+	// we take 1/4 of the system memory if that is more
+	maxRamMiB = int(math.Max(float64(maxRamMiB), sysMemMiB/4))
+	// but not more than 85% of the memory
+	return int(math.Min(float64(maxRamMiB), sysMemMiB*0.85))
+}
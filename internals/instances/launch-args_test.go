@@ -0,0 +1,73 @@
+package instances
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+func TestInstance_jvmArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *manifest.Launch
+		opts        *LaunchOptions
+		javaVersion int
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name: "memory and extra args",
+			cfg:  &manifest.Launch{MinMemory: "1G", MaxMemory: "4G", GC: manifest.GCNone, JVMArgs: []string{"-Da=1"}},
+			opts: &LaunchOptions{JVMArgs: []string{"-Db=2"}},
+			want: []string{"-Xms1024M", "-Xmx4096M", "-Da=1", "-Db=2"},
+		},
+		{
+			name: "ram flag overwrites config",
+			cfg:  &manifest.Launch{MaxMemory: "4G", GC: manifest.GCNone},
+			opts: &LaunchOptions{RamMiB: 2000},
+			want: []string{"-Xms2000M", "-Xmx2000M"},
+		},
+		{
+			name:        "zgc",
+			cfg:         &manifest.Launch{MaxMemory: "4G", GC: manifest.GCZGC},
+			opts:        &LaunchOptions{},
+			javaVersion: 17,
+			want:        []string{"-Xmx4096M", "-XX:+UseZGC"},
+		},
+		{
+			name:        "zgc on old java",
+			cfg:         &manifest.Launch{MaxMemory: "4G", GC: manifest.GCZGC},
+			opts:        &LaunchOptions{},
+			javaVersion: 8,
+			wantErr:     true,
+		},
+		{
+			name:    "invalid memory",
+			cfg:     &manifest.Launch{MaxMemory: "lots"},
+			opts:    &LaunchOptions{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&Instance{}).jvmArgs(tt.cfg, tt.opts, tt.javaVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("aikar uses max memory as min memory", func(t *testing.T) {
+		got, err := (&Instance{}).jvmArgs(&manifest.Launch{MaxMemory: "6G", GC: manifest.GCAikar}, &LaunchOptions{}, 17)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[0] != "-Xms6144M" || got[1] != "-Xmx6144M" {
+			t.Fatalf("unexpected memory args %v", got[:2])
+		}
+	})
+}
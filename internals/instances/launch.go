@@ -8,7 +8,6 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
-	"math"
 	"net/http"
 	"net/url"
 	"os"
@@ -22,7 +21,6 @@ import (
 
 	"github.com/minepkg/minepkg/internals/minecraft"
 	"github.com/minepkg/minepkg/pkg/manifest"
-	"github.com/pbnjay/memory"
 )
 
 var (
@@ -55,6 +53,54 @@ func (i *Instance) GetLaunchManifest() (*minecraft.LaunchManifest, error) {
 	return man, nil
 }
 
+// GetServerLaunchManifest returns the manifest used to launch a dedicated server.
+// It contains no client libraries, fabric instances use the fabric server profile
+func (i *Instance) GetServerLaunchManifest() (*minecraft.LaunchManifest, error) {
+	if i.Lockfile == nil {
+		if err := i.initLockfile(); err != nil {
+			return nil, err
+		}
+	}
+	vanilla, err := i.getVanillaManifest(i.Lockfile.MinecraftVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	man := &minecraft.LaunchManifest{ID: vanilla.ID}
+	switch i.Platform() {
+	case PlatformFabric:
+		if man, err = i.fetchFabricManifest(i.Lockfile.Fabric, true); err != nil {
+			return nil, err
+		}
+	case PlatformForge:
+		return nil, ErrLaunchNotImplemented
+	}
+
+	man.Downloads = vanilla.Downloads
+	man.JavaVersion = vanilla.JavaVersion
+	man.Type = vanilla.Type
+	return man, nil
+}
+
+// ServerJarPath returns the path of the dedicated server jar for the given manifest
+func (i *Instance) ServerJarPath(man *minecraft.LaunchManifest) string {
+	return filepath.Join(i.VersionsDir(), man.MinecraftVersion(), man.ServerJarName())
+}
+
+// ServerClasspath returns the main class of the server jar and the jars that are needed to start it.
+// Bundled server jars (1.18+) get extracted if that did not happen yet
+func (i *Instance) ServerClasspath(man *minecraft.LaunchManifest) (string, []string, error) {
+	server, err := minecraft.ReadServerJar(i.ServerJarPath(man))
+	if err != nil {
+		return "", nil, err
+	}
+	classpath, err := server.Classpath(i.LibrariesDir(), i.VersionsDir())
+	if err != nil {
+		return "", nil, err
+	}
+	return server.MainClass, classpath, nil
+}
+
 // LaunchOptions are options for launching
 type LaunchOptions struct {
 	LaunchManifest *minecraft.LaunchManifest
@@ -72,10 +118,17 @@ type LaunchOptions struct {
 	StartSave string
 	Debug     bool
 	// RamMiB can be set to the amount of ram in MiB to start Minecraft with
-	// 0 determins the amount by modcount + available system ram
+	// 0 uses the `[launch]` options or determines the amount by modcount + available system ram
 	RamMiB int
+	// JVMArgs are additional arguments for java. They are added after the ones from `[launch]`
+	JVMArgs []string
+	// JavaVersion is the major version of the java runtime (eg. 17). Used to check if the gc preset is supported.
+	// Falls back to the java version of the launch manifest if 0
+	JavaVersion int
 	// Environment variables to set
 	Env []string
+	// NoSignalForwarding does not stop the process on ctrl-c or SIGTERM. Set it if the caller stops the process itself
+	NoSignalForwarding bool
 }
 
 // Launch will launch the minecraft instance
@@ -144,6 +197,10 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 	for _, lib := range libs {
 		// copy natives. not sure if this implementation is complete
 		if len(lib.Natives) != 0 {
+			// servers do not need natives
+			if opts.Server {
+				continue
+			}
 			// extract native to temp dir
 			nativeID := lib.Natives[osName]
 			native := lib.Downloads.Classifiers[nativeID]
@@ -162,9 +219,22 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 		}
 	}
 
-	// finally append the minecraft.jar
+	mainClass := launchManifest.MainClass
 	mcJar := filepath.Join(i.VersionsDir(), launchManifest.MinecraftVersion(), launchManifest.JarName())
-	cpArgs = append(cpArgs, mcJar)
+	if opts.Server {
+		serverMain, serverCp, err := i.ServerClasspath(launchManifest)
+		if err != nil {
+			return nil, err
+		}
+		cpArgs = append(cpArgs, serverCp...)
+		// fabric brings its own main class (that loads the server)
+		if mainClass == "" {
+			mainClass = serverMain
+		}
+	} else {
+		// finally append the minecraft.jar
+		cpArgs = append(cpArgs, mcJar)
+	}
 
 	gameArgs, err := i.gameArgs(launchManifest, opts)
 	if err != nil {
@@ -177,43 +247,32 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 		javaCpSeperator = ";"
 	}
 
-	var maxRamMiB int
-
-	if opts.RamMiB == 0 {
-		sysMemMiB := float64(memory.TotalMemory()) / 1024 / 1024
-
-		// 1GiB for base Minecraft + every dependency takes 25 MiB
-		maxRamMiB = 1024 + len(i.Lockfile.Dependencies)*25
+	launchConfig, err := i.LaunchConfig()
+	if err != nil {
+		return nil, err
+	}
 
-		// we take 1/4 of the system memory if that is more
-		maxRamMiB = int(math.Max(float64(maxRamMiB), sysMemMiB/4))
-		// but not more than 85% of the memory
-		maxRamMiB = int(math.Min(float64(maxRamMiB), sysMemMiB*0.85))
-	} else {
-		maxRamMiB = opts.RamMiB
+	javaVersion := opts.JavaVersion
+	if javaVersion == 0 {
+		javaVersion = launchManifest.JavaVersion.MajorVersion
+	}
+	jvmArgs, err := i.jvmArgs(launchConfig, opts, javaVersion)
+	if err != nil {
+		return nil, err
 	}
 
-	cmdArgs := []string{
-		"-Djava.library.path=" + tmpDir,
-		"-Dminecraft.launcher.brand=minepkg",
-		// "-Dminecraft.launcher.version=" + "0.0.2", // TODO: implement!
-		"-Dminecraft.client.jar=" + mcJar,
+	cmdArgs := []string{"-Dminecraft.launcher.brand=minepkg"}
+	// "-Dminecraft.launcher.version=" + "0.0.2", // TODO: implement!
+	if !opts.Server {
+		cmdArgs = append(cmdArgs, "-Djava.library.path="+tmpDir, "-Dminecraft.client.jar="+mcJar)
+	}
+	cmdArgs = append(cmdArgs,
 		"-cp",
 		strings.Join(cpArgs, javaCpSeperator),
-		fmt.Sprintf("-Xmx%dM", maxRamMiB),
-		"-XX:+UnlockExperimentalVMOptions",
-		"-XX:+UseG1GC",
-		"-XX:G1NewSizePercent=20",
-		"-XX:G1ReservePercent=20",
-		"-XX:MaxGCPauseMillis=50",
-		"-XX:G1HeapRegionSize=32M",
 		"-XX:ErrorFile=./jvm-error.log",
-		launchManifest.MainClass,
-	}
-
-	if opts.RamMiB != 0 {
-		cmdArgs = append([]string{fmt.Sprintf("-Xms%dM", opts.RamMiB)}, cmdArgs...)
-	}
+	)
+	cmdArgs = append(cmdArgs, jvmArgs...)
+	cmdArgs = append(cmdArgs, mainClass)
 
 	// HACK: prepend this so macos does not crash
 	if runtime.GOOS == "darwin" {
@@ -223,9 +282,9 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 	if !opts.Server {
 		cmdArgs = append(cmdArgs, gameArgs...)
 	} else {
-		// maybe don't use client args for server …
 		cmdArgs = append(cmdArgs, "nogui")
 	}
+	cmdArgs = append(cmdArgs, launchConfig.GameArgs...)
 
 	if opts.Debug {
 		fmt.Println("cmd: ")
@@ -256,13 +315,15 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 	}
 
 	// we catch ctrl-c to handle this by ourself
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		// stops the minecraft server
-		cmd.Process.Signal(syscall.SIGTERM)
-	}()
+	if !opts.NoSignalForwarding {
+		c := make(chan os.Signal)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-c
+			// stops the minecraft server
+			cmd.Process.Signal(syscall.SIGTERM)
+		}()
+	}
 
 	if opts.Stdout != nil {
 		cmd.Stdout = opts.Stdout
@@ -275,10 +336,11 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 		cmd.Stderr = os.Stderr
 	}
 
-	cmd.Stderr = os.Stderr
-
 	// Set the process directory to our minecraft dir
 	cmd.Dir = i.McDir()
+	for key, value := range launchConfig.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
 	// some things may rely on PWD
 	cmd.Env = append(cmd.Env, opts.Env...)
 	cmd.Env = append(cmd.Env, "PWD="+i.McDir())
@@ -345,7 +407,7 @@ func (i *Instance) launchManifest() (*minecraft.LaunchManifest, error) {
 
 	switch i.Platform() {
 	case PlatformFabric:
-		return i.fetchFabricManifest(lockfile.Fabric)
+		return i.fetchFabricManifest(lockfile.Fabric, false)
 	case PlatformForge:
 		// TODO: forge
 		panic("Forge is not supported")
@@ -365,14 +427,22 @@ func (i *Instance) getVanillaManifest(v string) (*minecraft.LaunchManifest, erro
 	return &instructions, nil
 }
 
-func (i *Instance) fetchFabricManifest(lock *manifest.FabricLock) (*minecraft.LaunchManifest, error) {
+// fetchFabricManifest returns the fabric launcher profile. server returns the profile
+// for dedicated servers instead of the client one
+func (i *Instance) fetchFabricManifest(lock *manifest.FabricLock, server bool) (*minecraft.LaunchManifest, error) {
 	manifest := minecraft.LaunchManifest{}
 	loader := lock.FabricLoader
 	minecraft := lock.Minecraft
 
 	version := minecraft + "-fabric-" + loader
 	dir := filepath.Join(i.VersionsDir(), minecraft+"-fabric-"+loader)
-	file := filepath.Join(dir, version+".json")
+	profile := "profile"
+	fileName := version + ".json"
+	if server {
+		profile = "server"
+		fileName = version + "-server.json"
+	}
+	file := filepath.Join(dir, fileName)
 
 	// cached
 	if rawMan, err := ioutil.ReadFile(file); err == nil {
@@ -385,9 +455,10 @@ func (i *Instance) fetchFabricManifest(lock *manifest.FabricLock) (*minecraft.La
 	}
 
 	profileURL := fmt.Sprintf(
-		"https://meta.fabricmc.net/v2/versions/loader/%s/%s/profile/json",
+		"https://meta.fabricmc.net/v2/versions/loader/%s/%s/%s/json",
 		url.QueryEscape(minecraft),
 		url.QueryEscape(loader),
+		profile,
 	)
 	res, err := http.Get(profileURL)
 	if err != nil {
@@ -402,7 +473,7 @@ func (i *Instance) fetchFabricManifest(lock *manifest.FabricLock) (*minecraft.La
 	if err != nil {
 		return nil, err
 	}
-	ioutil.WriteFile(filepath.Join(dir, version+".json"), buf, 0666)
+	ioutil.WriteFile(file, buf, 0666)
 
 	if err = json.Unmarshal(buf, &manifest); err != nil {
 		return nil, err
@@ -448,21 +519,5 @@ func (i *Instance) fetchVanillaManifest(version string) (*minecraft.LaunchManife
 		return nil, err
 	}
 
-	// TODO: this is a side effect. it should not be here
-	jarRes, err := http.Get(manifest.Downloads.Client.URL)
-	if err != nil {
-		return nil, err
-	}
-	jarDest, err := os.Create(filepath.Join(dir, version+".jar"))
-	if err != nil {
-		return nil, err
-	}
-	defer jarDest.Close()
-
-	// copy the jar
-	if _, err = io.Copy(jarDest, jarRes.Body); err != nil {
-		return nil, err
-	}
-
 	return &manifest, nil
 }
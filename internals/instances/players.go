@@ -0,0 +1,156 @@
+package instances
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/minecraft"
+)
+
+// WhitelistPath is the path to the `whitelist.json` of this instance
+func (i *Instance) WhitelistPath() string {
+	return filepath.Join(i.McDir(), "whitelist.json")
+}
+
+// OpsPath is the path to the `ops.json` of this instance
+func (i *Instance) OpsPath() string {
+	return filepath.Join(i.McDir(), "ops.json")
+}
+
+// UserCachePath is the path to the `usercache.json` of this instance
+func (i *Instance) UserCachePath() string {
+	return filepath.Join(i.McDir(), "usercache.json")
+}
+
+// KnownPlayers returns the names of all players in the whitelist, ops and user cache of this instance.
+// Unreadable files are skipped
+func (i *Instance) KnownPlayers() []string {
+	names := []string{}
+	for _, path := range []string{i.WhitelistPath(), i.UserCachePath()} {
+		players, _ := minecraft.ReadWhitelist(path)
+		for _, p := range players {
+			names = append(names, p.Name)
+		}
+	}
+	ops, _ := i.Ops()
+	for _, o := range ops {
+		names = append(names, o.Name)
+	}
+	return names
+}
+
+// OnlineMode returns false if the server of this instance runs with `online-mode=false`
+func (i *Instance) OnlineMode() (bool, error) {
+	props, err := i.ServerProperties()
+	if err != nil {
+		return false, err
+	}
+	return props.Value("online-mode") != "false", nil
+}
+
+// ResolvePlayer returns the player with the given name. Servers in online mode resolve the name
+// with lookup, offline servers derive the UUID from the name
+func (i *Instance) ResolvePlayer(ctx context.Context, lookup *minecraft.ProfileLookup, name string) (*minecraft.Player, error) {
+	online, err := i.OnlineMode()
+	if err != nil {
+		return nil, err
+	}
+	if !online {
+		return &minecraft.Player{UUID: minecraft.OfflineUUID(name), Name: name}, nil
+	}
+	return lookup.Lookup(ctx, name)
+}
+
+// Whitelist returns the whitelisted players of this instance
+func (i *Instance) Whitelist() ([]minecraft.Player, error) {
+	return minecraft.ReadWhitelist(i.WhitelistPath())
+}
+
+// AddToWhitelist adds player to the whitelist. Existing entries for the player are replaced
+func (i *Instance) AddToWhitelist(player *minecraft.Player) error {
+	players, err := i.Whitelist()
+	if err != nil {
+		return err
+	}
+	filtered := make([]minecraft.Player, 0, len(players)+1)
+	for _, p := range players {
+		if !matchesPlayer(p.UUID, p.Name, player.UUID) && !matchesPlayer(p.UUID, p.Name, player.Name) {
+			filtered = append(filtered, p)
+		}
+	}
+	if err := os.MkdirAll(i.McDir(), os.ModePerm); err != nil {
+		return err
+	}
+	return minecraft.WriteWhitelist(i.WhitelistPath(), append(filtered, *player))
+}
+
+// RemoveFromWhitelist removes the player with the given name or UUID from the whitelist.
+// Returns false if the player was not whitelisted
+func (i *Instance) RemoveFromWhitelist(query string) (bool, error) {
+	players, err := i.Whitelist()
+	if err != nil {
+		return false, err
+	}
+	filtered := make([]minecraft.Player, 0, len(players))
+	for _, p := range players {
+		if !matchesPlayer(p.UUID, p.Name, query) {
+			filtered = append(filtered, p)
+		}
+	}
+	if len(filtered) == len(players) {
+		return false, nil
+	}
+	return true, minecraft.WriteWhitelist(i.WhitelistPath(), filtered)
+}
+
+// Ops returns the operators of this instance
+func (i *Instance) Ops() ([]minecraft.Op, error) {
+	return minecraft.ReadOps(i.OpsPath())
+}
+
+// AddOp makes op an operator. Existing entries for the player are replaced
+func (i *Instance) AddOp(op *minecraft.Op) error {
+	ops, err := i.Ops()
+	if err != nil {
+		return err
+	}
+	filtered := make([]minecraft.Op, 0, len(ops)+1)
+	for _, o := range ops {
+		if !matchesPlayer(o.UUID, o.Name, op.UUID) && !matchesPlayer(o.UUID, o.Name, op.Name) {
+			filtered = append(filtered, o)
+		}
+	}
+	if err := os.MkdirAll(i.McDir(), os.ModePerm); err != nil {
+		return err
+	}
+	return minecraft.WriteOps(i.OpsPath(), append(filtered, *op))
+}
+
+// RemoveOp removes the operator with the given name or UUID and returns it. Returns nil if the player was no operator
+func (i *Instance) RemoveOp(query string) (*minecraft.Op, error) {
+	ops, err := i.Ops()
+	if err != nil {
+		return nil, err
+	}
+	var removed *minecraft.Op
+	filtered := make([]minecraft.Op, 0, len(ops))
+	for _, o := range ops {
+		if matchesPlayer(o.UUID, o.Name, query) {
+			o := o
+			removed = &o
+			continue
+		}
+		filtered = append(filtered, o)
+	}
+	if removed == nil {
+		return nil, nil
+	}
+	return removed, minecraft.WriteOps(i.OpsPath(), filtered)
+}
+
+// matchesPlayer returns true if query is the (case insensitive) name or the UUID of a player
+func matchesPlayer(uuid string, name string, query string) bool {
+	return strings.EqualFold(name, query) || minecraft.FormatUUID(uuid) == minecraft.FormatUUID(query)
+}
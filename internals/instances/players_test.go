@@ -0,0 +1,74 @@
+package instances
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/minecraft"
+)
+
+func TestInstance_Whitelist(t *testing.T) {
+	i := &Instance{Directory: t.TempDir()}
+	os.MkdirAll(i.McDir(), os.ModePerm)
+	os.WriteFile(i.ServerPropertiesPath(), []byte("online-mode=false\n"), 0644)
+
+	// offline servers do not need the profile API
+	player, err := i.ResolvePlayer(context.Background(), nil, "Steve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.UUID != minecraft.OfflineUUID("Steve") {
+		t.Fatalf("unexpected uuid %s", player.UUID)
+	}
+
+	if err := i.AddToWhitelist(player); err != nil {
+		t.Fatal(err)
+	}
+	// adding again replaces the entry
+	if err := i.AddToWhitelist(player); err != nil {
+		t.Fatal(err)
+	}
+	players, _ := i.Whitelist()
+	if len(players) != 1 {
+		t.Fatalf("expected one player, got %v", players)
+	}
+
+	removed, err := i.RemoveFromWhitelist("steve")
+	if err != nil || !removed {
+		t.Fatalf("expected steve to be removed (%v)", err)
+	}
+	if removed, _ := i.RemoveFromWhitelist("steve"); removed {
+		t.Fatal("steve was removed twice")
+	}
+}
+
+func TestInstance_Ops(t *testing.T) {
+	i := &Instance{Directory: t.TempDir()}
+
+	op := &minecraft.Op{UUID: minecraft.OfflineUUID("Alex"), Name: "Alex", Level: 4}
+	if err := i.AddOp(op); err != nil {
+		t.Fatal(err)
+	}
+	ops, _ := i.Ops()
+	if len(ops) != 1 || ops[0].Level != 4 {
+		t.Fatalf("unexpected ops %v", ops)
+	}
+
+	removed, err := i.RemoveOp(op.UUID)
+	if err != nil || removed == nil || removed.Name != "Alex" {
+		t.Fatalf("expected Alex to be removed by uuid (%v)", err)
+	}
+}
+
+func TestInstance_KnownPlayers(t *testing.T) {
+	i := &Instance{Directory: t.TempDir()}
+	i.AddToWhitelist(&minecraft.Player{Name: "Steve"})
+	i.AddOp(&minecraft.Op{Name: "Alex", Level: 4})
+	os.WriteFile(i.UserCachePath(), []byte(`[{"name":"Notch","uuid":"069a79f4-44e9-4726-a5be-fca90e38aaf5","expiresOn":"2022-07-01 10:00:00 +0200"}]`), 0644)
+
+	players := i.KnownPlayers()
+	if len(players) != 3 || players[0] != "Steve" || players[1] != "Notch" || players[2] != "Alex" {
+		t.Fatalf("unexpected players %v", players)
+	}
+}
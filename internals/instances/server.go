@@ -0,0 +1,51 @@
+package instances
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/minecraft"
+	"github.com/minepkg/minepkg/internals/rcon"
+)
+
+// ErrRconDisabled is returned if rcon is not enabled for a server
+var ErrRconDisabled = errors.New("rcon is not enabled")
+
+// ServerPropertiesPath is the path to the `server.properties` of this instance. The file does not necessarily exist
+func (i *Instance) ServerPropertiesPath() string {
+	return filepath.Join(i.McDir(), "server.properties")
+}
+
+// ServerProperties reads the `server.properties` of this instance.
+// Returns empty properties if the file does not exist
+func (i *Instance) ServerProperties() (*minecraft.ServerProperties, error) {
+	raw, err := ioutil.ReadFile(i.ServerPropertiesPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return minecraft.ParseServerProps(raw), nil
+}
+
+// SaveServerProperties writes props to the `server.properties` of this instance
+func (i *Instance) SaveServerProperties(props *minecraft.ServerProperties) error {
+	if err := os.MkdirAll(i.McDir(), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(i.ServerPropertiesPath(), []byte(props.String()), 0600)
+}
+
+// DialRcon connects to the rcon server of this instance. Returns `ErrRconDisabled` if
+// rcon is not enabled in the `server.properties`
+func (i *Instance) DialRcon(timeout time.Duration) (*rcon.Client, error) {
+	props, err := i.ServerProperties()
+	if err != nil {
+		return nil, err
+	}
+	if props.Value("enable-rcon") != "true" || props.Value("rcon.password") == "" {
+		return nil, ErrRconDisabled
+	}
+	return rcon.Dial(props.RconAddress(), props.Value("rcon.password"), timeout)
+}
@@ -0,0 +1,68 @@
+package instances
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/pelletier/go-toml"
+)
+
+// LocalSettings are instance specific settings that are never published.
+// They live next to the manifest in the `.minepkg-local.toml` file
+type LocalSettings struct {
+	// Java pins the java runtime used to launch this instance.
+	// Can be a version like "17-jre", "system" or a path to a java binary
+	Java string `toml:"java,omitempty"`
+	// Launch overrides the `[launch]` options of the manifest
+	Launch *manifest.Launch `toml:"launch,omitempty"`
+	// Backup configures world backups
+	Backup *BackupSettings `toml:"backup,omitempty"`
+	// Account is the name of the Minecraft account used to launch this instance instead of the default one
+	Account string `toml:"account,omitempty"`
+	// Dev contains development options
+	Dev *DevSettings `toml:"dev,omitempty"`
+}
+
+// DevSettings are options for developing and testing mods
+type DevSettings struct {
+	// OfflinePlayer launches the client as this offline player instead of logging in.
+	// Works in singleplayer and on servers with `online-mode=false`
+	OfflinePlayer string `toml:"offlinePlayer,omitempty"`
+}
+
+// LocalSettingsPath is the path to the `.minepkg-local.toml`. The file does not necessarily exist
+func (i *Instance) LocalSettingsPath() string {
+	return filepath.Join(i.Directory, ".minepkg-local.toml")
+}
+
+// LocalSettings reads the local settings of this instance.
+// Returns empty settings if the file does not exist
+func (i *Instance) LocalSettings() (*LocalSettings, error) {
+	settings := &LocalSettings{}
+
+	raw, err := ioutil.ReadFile(i.LocalSettingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, err
+	}
+
+	if err := toml.Unmarshal(raw, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SaveLocalSettings writes the given settings to the `.minepkg-local.toml` file
+func (i *Instance) SaveLocalSettings(settings *LocalSettings) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString("# Local settings for this instance. This file is not published.\n\n")
+	if err := toml.NewEncoder(buf).Order(toml.OrderPreserve).Encode(settings); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(i.LocalSettingsPath(), buf.Bytes(), 0644)
+}
@@ -8,12 +8,16 @@ import (
 	"net/url"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 )
 
 const AdoptAPI = "https://api.adoptium.net/v3"
 
 type AdoptAssetRequest struct {
+	// baseURL is the API url of the vendor. defaults to the official one (eg. `AdoptAPI`)
+	// can be changed to use a mirror
+	baseURL        string
 	featureVersion uint16
 	releaseType    string
 	architecture   string
@@ -64,8 +68,8 @@ type AdoptAsset struct {
 	} `json:"version_data"`
 }
 
-func (j *Factory) getAssets(ctx context.Context, opts *AdoptAssetRequest) ([]AdoptAsset, error) {
-	// set all the defaults
+// setDefaults fills all unset fields with the default values for this system
+func (opts *AdoptAssetRequest) setDefaults() {
 	if opts.architecture == "" {
 		opts.architecture = archMap(runtime.GOARCH)
 	}
@@ -98,12 +102,48 @@ func (j *Factory) getAssets(ctx context.Context, opts *AdoptAssetRequest) ([]Ado
 		opts.releaseType = "ga"
 	}
 	if opts.vendor == "" {
-		opts.vendor = "adoptopenjdk"
+		opts.vendor = VendorAdoptium
 	}
-
 	if opts.imageType == "" {
 		opts.imageType = "jdk" // jre is deprecated. they stopped shipping them after 16
 	}
+}
+
+// adoptium provides releases using the adoptium API
+type adoptium struct{}
+
+func (a *adoptium) Name() string { return VendorAdoptium }
+
+func (a *adoptium) Latest(ctx context.Context, client *http.Client, opts *AdoptAssetRequest) (*Release, error) {
+	assets, err := a.getAssets(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(assets) == 0 || len(assets[0].Binaries) == 0 {
+		return nil, ErrNoRelease
+	}
+
+	asset := assets[0]
+	pkg := asset.Binaries[0].Package
+	version := asset.VersionData.Semver
+	if version == "" {
+		version = asset.VersionData.OpenjdkVersion
+	}
+
+	return &Release{
+		Vendor:   VendorAdoptium,
+		Version:  version,
+		Link:     pkg.Link,
+		Checksum: pkg.Checksum,
+		Size:     pkg.Size,
+	}, nil
+}
+
+func (a *adoptium) getAssets(ctx context.Context, client *http.Client, opts *AdoptAssetRequest) ([]AdoptAsset, error) {
+	baseURL := opts.baseURL
+	if baseURL == "" {
+		baseURL = AdoptAPI
+	}
 
 	// url params
 	params := url.Values{}
@@ -119,7 +159,7 @@ func (j *Factory) getAssets(ctx context.Context, opts *AdoptAssetRequest) ([]Ado
 	// build the url
 	p := fmt.Sprintf(
 		"%s/assets/feature_releases/%d/%s?%s",
-		AdoptAPI,
+		strings.TrimSuffix(baseURL, "/"),
 		opts.featureVersion,
 		opts.releaseType,
 		params.Encode(),
@@ -132,10 +172,15 @@ func (j *Factory) getAssets(ctx context.Context, opts *AdoptAssetRequest) ([]Ado
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := j.http.Do(req)
+	res, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("java API responded with unexpected status %s", res.Status)
+	}
 
 	parsed := make([]AdoptAsset, 0, 1)
 	if err = json.NewDecoder(res.Body).Decode(&parsed); err != nil {
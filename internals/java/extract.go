@@ -0,0 +1,172 @@
+package java
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// extractArchive extracts a .tar.gz or .zip java archive to dest.
+// Java archives contain a single root directory (like "jdk8u292-b10-jre"), which is stripped
+func extractArchive(archive string, dest string) error {
+	if strings.HasSuffix(archive, ".zip") {
+		return extractZip(archive, dest)
+	}
+	return extractTarGz(archive, dest)
+}
+
+func extractTarGz(archive string, dest string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := extractTarget(header.Name, dest)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, header.FileInfo().Mode()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := checkSymlink(header.Name, header.Linkname); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			linkTarget, err := extractTarget(header.Linkname, dest)
+			if err != nil || linkTarget == "" {
+				return fmt.Errorf("%s: illegal link target %s", header.Name, header.Linkname)
+			}
+			if err := os.Link(linkTarget, target); err != nil {
+				return err
+			}
+		default:
+			// skip everything else (devices, fifos …)
+		}
+	}
+}
+
+func extractZip(archive string, dest string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		target, err := extractTarget(f.Name, dest)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			continue
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(target, rc, f.Mode())
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractTarget strips the root directory from name and returns the path inside dest.
+// An empty path is returned for the root directory itself.
+// Returns an error for paths that would end up outside of dest
+func extractTarget(name string, dest string) (string, error) {
+	cleaned := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%s: illegal file path", name)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(cleaned, "./"), "/", 2)
+	if len(parts) < 2 || parts[1] == "" {
+		return "", nil
+	}
+
+	target := filepath.Join(dest, filepath.FromSlash(parts[1]))
+	if !strings.HasPrefix(target, filepath.Clean(dest)+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s: illegal file path", name)
+	}
+	return target, nil
+}
+
+// checkSymlink makes sure a symlink does not point outside of the (stripped) root directory
+func checkSymlink(name string, link string) error {
+	if path.IsAbs(link) || filepath.IsAbs(link) {
+		return fmt.Errorf("%s: illegal symlink to %s", name, link)
+	}
+	cleaned := strings.TrimPrefix(path.Clean(name), "./")
+	root := strings.SplitN(cleaned, "/", 2)[0]
+	resolved := path.Join(path.Dir(cleaned), link)
+	if !strings.HasPrefix(resolved, root+"/") {
+		return fmt.Errorf("%s: illegal symlink to %s", name, link)
+	}
+	return nil
+}
+
+func writeFile(target string, src io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0200)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
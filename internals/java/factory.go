@@ -11,21 +11,28 @@ import (
 )
 
 var (
-	ErrInvalidVersionString     = errors.New("invalid java version string. must consist of at most 3 parts separated by dashes")
+	ErrInvalidVersionString     = errors.New("invalid java version string. must consist of at most 4 parts separated by dashes")
 	ErrInvalidFeatureVersion    = errors.New("invalid feature version. must be a number between 1 and 65535")
 	ErrInvalidImageType         = errors.New("invalid image type. must be either jdk, jre, testimage or debugimage")
 	ErrInvalidJvmImplementation = errors.New("invalid jvm implementation. must be hotspot or openj9")
+	ErrInvalidVendor            = errors.New("invalid java vendor. must be adoptium, zulu, microsoft or graalvm")
+	ErrNotInstalled             = errors.New("this java version is not installed")
 )
 
 type Factory struct {
 	baseDir string
-	http    *http.Client
+	// apiURLs maps vendor names to mirrors of their API
+	apiURLs       map[string]string
+	defaultVendor string
+	http          *http.Client
 }
 
 func NewFactory(baseDir string) *Factory {
 	return &Factory{
-		baseDir,
-		http.DefaultClient,
+		baseDir:       baseDir,
+		apiURLs:       make(map[string]string),
+		defaultVendor: VendorAdoptium,
+		http:          http.DefaultClient,
 	}
 }
 
@@ -34,12 +41,42 @@ func (j *Factory) SetHTTPClient(c *http.Client) {
 	j.http = c
 }
 
-func (j *Factory) Version(ctx context.Context, wantedVersion string) (*Java, error) {
+// SetAPIURL replaces the API url of the given vendor. This can be used to point to a mirror
+func (j *Factory) SetAPIURL(vendor string, u string) error {
+	if _, ok := vendors[vendor]; !ok {
+		return ErrInvalidVendor
+	}
+	j.apiURLs[vendor] = u
+	return nil
+}
+
+// SetDefaultVendor sets the vendor that is used if a version string does not contain one
+func (j *Factory) SetDefaultVendor(name string) error {
+	if _, ok := vendors[name]; !ok {
+		return ErrInvalidVendor
+	}
+	j.defaultVendor = name
+	return nil
+}
+
+// wanted parses the version string and fills in the default vendor
+func (j *Factory) wanted(wantedVersion string) (*wantedVersion, error) {
 	wanted, err := newWantedVersion(wantedVersion)
 	if err != nil {
 		return nil, err
 	}
-	fullName := wanted.Identifier()
+	if wanted.vendor == "" {
+		wanted.vendor = j.defaultVendor
+	}
+	return wanted, nil
+}
+
+func (j *Factory) Version(ctx context.Context, wantedVersion string) (*Java, error) {
+	wanted, err := j.wanted(wantedVersion)
+	if err != nil {
+		return nil, err
+	}
+	fullName := wanted.dirName()
 
 	os.MkdirAll(j.baseDir, os.ModePerm)
 	entries, err := os.ReadDir(j.baseDir)
@@ -54,40 +91,112 @@ func (j *Factory) Version(ctx context.Context, wantedVersion string) (*Java, err
 
 	for _, e := range entries {
 		if e.Name() == fullName {
-			asset, err := readAssetFile(filepath.Join(p, "asset.json"))
+			release, err := readRelease(p)
 			if err != nil {
 				break
 			}
 
 			return &Java{
 				dir:              p,
-				asset:            asset,
+				release:          release,
+				http:             j.http,
 				needsDownloading: false,
 			}, nil
 		}
 	}
 
 	// no cached version, downloading
-	assets, err := j.getAssets(ctx, &wanted.AdoptAssetRequest)
+	req := &wanted.AdoptAssetRequest
+	req.baseURL = j.apiURLs[wanted.vendor]
+	req.setDefaults()
+	release, err := vendors[req.vendor].Latest(ctx, j.http, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", req.vendor, err)
+	}
+
+	return &Java{dir: p, release: release, http: j.http, needsDownloading: true}, nil
+}
+
+// Installed returns all java versions that are installed in the base directory
+func (j *Factory) Installed() ([]*Java, error) {
+	entries, err := os.ReadDir(j.baseDir)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Java{}, nil
+		}
 		return nil, err
 	}
-	if len(assets) == 0 {
-		return nil, fmt.Errorf("no java version found")
+
+	installed := make([]*Java, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		p, err := filepath.Abs(filepath.Join(j.baseDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		// directories without release file are leftovers of failed downloads
+		release, err := readRelease(p)
+		if err != nil {
+			continue
+		}
+		installed = append(installed, &Java{dir: p, release: release, http: j.http})
 	}
 
-	return &Java{dir: p, asset: &assets[0], needsDownloading: true}, nil
+	return installed, nil
 }
 
-func readAssetFile(file string) (*AdoptAsset, error) {
-	f, err := os.Open(file)
+// Remove deletes the installed java version matching `wantedVersion`
+func (j *Factory) Remove(wantedVersion string) error {
+	wanted, err := j.wanted(wantedVersion)
 	if err != nil {
+		return err
+	}
+
+	p := filepath.Join(j.baseDir, wanted.dirName())
+	if _, err := os.Stat(p); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotInstalled
+		}
+		return err
+	}
+
+	return os.RemoveAll(p)
+}
+
+// readRelease reads the release file of an installed java version.
+// Installations of older minepkg versions only have the adoptium `asset.json`
+func readRelease(dir string) (*Release, error) {
+	release := &Release{}
+	if err := readJSONFile(filepath.Join(dir, "release.json"), release); err == nil {
+		return release, nil
+	} else if !os.IsNotExist(err) {
 		return nil, err
 	}
 
 	asset := &AdoptAsset{}
-	if err := json.NewDecoder(f).Decode(asset); err != nil {
+	if err := readJSONFile(filepath.Join(dir, "asset.json"), asset); err != nil {
 		return nil, err
 	}
-	return asset, nil
+	release = &Release{Vendor: VendorAdoptium, Version: asset.VersionData.Semver}
+	if release.Version == "" {
+		release.Version = asset.VersionData.OpenjdkVersion
+	}
+	if len(asset.Binaries) != 0 {
+		release.Link = asset.Binaries[0].Package.Link
+		release.Checksum = asset.Binaries[0].Package.Checksum
+		release.Size = asset.Binaries[0].Package.Size
+	}
+	return release, nil
+}
+
+func readJSONFile(file string, v interface{}) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewDecoder(f).Decode(v)
 }
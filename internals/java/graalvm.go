@@ -0,0 +1,82 @@
+package java
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// GraalVMAPI is the GitHub API endpoint of the GraalVM Community Edition releases
+const GraalVMAPI = "https://api.github.com/repos/graalvm/graalvm-ce-builds"
+
+// graalvm provides GraalVM Community Edition releases from GitHub
+type graalvm struct{}
+
+type githubRelease struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
+		Name               string `json:"name"`
+		Size               int    `json:"size"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+func (g *graalvm) Name() string { return VendorGraalVM }
+
+func (g *graalvm) Latest(ctx context.Context, client *http.Client, opts *AdoptAssetRequest) (*Release, error) {
+	if err := jdkOnly(VendorGraalVM, opts); err != nil {
+		return nil, err
+	}
+	if opts.os == "alpine-linux" {
+		return nil, fmt.Errorf("graalvm does not provide musl builds: %w", ErrNoRelease)
+	}
+
+	baseURL := strings.TrimSuffix(opts.baseURL, "/")
+	if baseURL == "" {
+		baseURL = GraalVMAPI
+	}
+
+	releases := make([]githubRelease, 0)
+	if err := getJSON(ctx, client, baseURL+"/releases?per_page=100", &releases); err != nil {
+		return nil, err
+	}
+
+	// tags look like "jdk-17.0.9" or "jdk-21"
+	feature := strconv.Itoa(int(opts.featureVersion))
+	// asset names look like "graalvm-community-jdk-17.0.9_linux-x64_bin.tar.gz"
+	suffix := fmt.Sprintf("_%s-%s_bin.%s", vendorOS(opts.os), opts.architecture, archiveType(opts.os))
+
+	// releases are sorted newest first
+	for _, r := range releases {
+		if r.Draft || r.Prerelease {
+			continue
+		}
+		version := strings.TrimPrefix(r.TagName, "jdk-")
+		if version != feature && !strings.HasPrefix(version, feature+".") {
+			continue
+		}
+
+		for _, asset := range r.Assets {
+			if !strings.HasPrefix(asset.Name, "graalvm-community-") || !strings.HasSuffix(asset.Name, suffix) {
+				continue
+			}
+			checksum, err := fetchChecksum(ctx, client, asset.BrowserDownloadURL+".sha256")
+			if err != nil {
+				return nil, err
+			}
+			return &Release{
+				Vendor:   VendorGraalVM,
+				Version:  version,
+				Link:     asset.BrowserDownloadURL,
+				Checksum: checksum,
+				Size:     asset.Size,
+			}, nil
+		}
+	}
+
+	return nil, ErrNoRelease
+}
@@ -2,21 +2,23 @@ package java
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
-
-	archiver "github.com/mholt/archiver/v3"
 )
 
 type Java struct {
 	dir              string
-	asset            *AdoptAsset
+	release          *Release
+	http             *http.Client
 	needsDownloading bool
 }
 
@@ -27,6 +29,10 @@ func (j *Java) Bin() string {
 		bin = "bin/java.exe"
 	case "darwin": // macOS
 		bin = "Contents/Home/bin/java"
+		// some vendors (like zulu) ship a bin directory in the root on macOS
+		if _, err := os.Stat(filepath.Join(j.dir, bin)); err != nil {
+			bin = "bin/java"
+		}
 	default:
 		bin = "bin/java"
 	}
@@ -34,95 +40,198 @@ func (j *Java) Bin() string {
 	return filepath.Join(j.dir, bin)
 }
 
+// Identifier returns the version identifier of this java installation (eg. "17-jdk-hotspot-zulu")
+func (j *Java) Identifier() string {
+	name := filepath.Base(j.dir)
+	// adoptium builds are installed without vendor suffix
+	if strings.Count(name, "-") == 2 {
+		return name + "-" + VendorAdoptium
+	}
+	return name
+}
+
+// FeatureVersion returns the major version of this java installation (eg. 17)
+func (j *Java) FeatureVersion() int {
+	v, _ := strconv.Atoi(strings.SplitN(j.Identifier(), "-", 2)[0])
+	return v
+}
+
+// Dir returns the directory this java version is installed in
+func (j *Java) Dir() string {
+	return j.dir
+}
+
+// Version returns the full version of this java release (eg. "17.0.4+8")
+func (j *Java) Version() string {
+	if j.release == nil {
+		return ""
+	}
+	return j.release.Version
+}
+
+// Vendor returns the name of the vendor of this java release (eg. "adoptium")
+func (j *Java) Vendor() string {
+	if j.release == nil {
+		return ""
+	}
+	return j.release.Vendor
+}
+
 func (j *Java) NeedsDownloading() bool {
 	return j.needsDownloading
 }
 
-// Update downloads or updates this java version
+// Update downloads or updates this java version.
+// The archive is downloaded, verified and extracted to a temporary directory first.
+// The existing installation is only replaced if all of that succeeded
 func (j *Java) Update(ctx context.Context) error {
-	// remove everything
-	if err := os.RemoveAll(j.dir); err != nil {
-		return err
-	}
-	os.RemoveAll(j.dir + ".tmp")
-
-	// download archive
+	// download archive (this resumes a previously interrupted download)
 	archive, err := j.download(ctx)
 	if err != nil {
 		return err
 	}
-	defer os.Remove(archive.Name()) // remove temporary download
 
-	// ugly hack to get root directory. it's something like "jdk8u292-b10-jre"
-	rootDirName := ""
-	err = archiver.Walk(archive.Name(), func(f archiver.File) error {
-		if f.IsDir() {
-			rootDirName = f.Name()
-			return archiver.ErrStopWalk
-		}
-		return nil
-	})
-	if err != nil {
+	// extract the whole archive to a temporary directory
+	tmpDir := j.dir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return err
+	}
+	if err := extractArchive(archive, tmpDir); err != nil {
+		os.RemoveAll(tmpDir)
 		return err
 	}
 
-	// extract the whole archive. avoids https://github.com/mholt/archiver/issues/289
-	if err := archiver.Unarchive(archive.Name(), j.dir+".tmp"); err != nil {
+	// write the release file
+	releaseFile, err := os.Create(filepath.Join(tmpDir, "release.json"))
+	if err != nil {
 		return err
 	}
-	// another ugly hack because archiver can not extract without creating a directory
-	// and doing in manually with archiver.Walk is a pain. PRs welcome …
-	if err := os.Rename(filepath.Join(j.dir+".tmp", rootDirName), j.dir); err != nil {
+	if err := json.NewEncoder(releaseFile).Encode(j.release); err != nil {
+		releaseFile.Close()
 		return err
 	}
-
-	// we moved the rootDir "jdk8u292-b10-jre" to j.dir ("8-jre-openj9")
-	// but the leftover .tmp dir is still here. should be empty, but it's not
-	// for macos archives
-	if err := os.RemoveAll(j.dir + ".tmp"); err != nil {
+	if err := releaseFile.Close(); err != nil {
 		return err
 	}
 
-	// finally write the asset file
-	asset, err := os.Create(filepath.Join(j.dir, "asset.json"))
-	if err != nil {
+	// finally swap the new installation in
+	if err := os.RemoveAll(j.dir); err != nil {
 		return err
 	}
-
-	if err := json.NewEncoder(asset).Encode(j.asset); err != nil {
+	if err := os.Rename(tmpDir, j.dir); err != nil {
 		return err
 	}
 
+	// the archive is not needed anymore
+	os.Remove(archive)
+
 	j.needsDownloading = false
 	return nil
 }
 
-func (j *Java) download(ctx context.Context) (*os.File, error) {
-	url := j.downloadURL()
-	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
+// download downloads the archive of this java version and verifies it.
+// It returns the path to the downloaded archive
+func (j *Java) download(ctx context.Context) (string, error) {
+	url := j.release.Link
 
 	ext := ".tar.gz"
 	if !strings.HasSuffix(url, ".tar.gz") {
 		ext = filepath.Ext(url)
 	}
-	archive, err := ioutil.TempFile("", "minepkg-java.*"+ext)
+	archive := j.dir + ".download" + ext
+
+	if err := os.MkdirAll(filepath.Dir(archive), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	// continue where we left of last time (if there is a partial download)
+	var offset int64
+	if stat, err := os.Stat(archive); err == nil {
+		offset = stat.Size()
+	}
+
+	// there might be a complete (but unextracted) archive already
+	if offset == 0 || j.release.Size == 0 || offset < int64(j.release.Size) {
+		if err := j.fetch(ctx, url, archive, offset); err != nil {
+			return "", err
+		}
+	}
+
+	if err := verifyArchive(archive, int64(j.release.Size), j.release.Checksum); err != nil {
+		// corrupt archive. remove it so the next try starts from scratch
+		os.Remove(archive)
+		return "", err
+	}
+
+	return archive, nil
+}
+
+// fetch downloads url to target. If offset is not 0 it tries to resume the download
+func (j *Java) fetch(ctx context.Context, url string, target string, offset int64) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
 	}
-	defer archive.Close()
 
-	_, err = io.Copy(archive, res.Body)
+	client := j.http
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	defer res.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch res.StatusCode {
+	case http.StatusOK:
+		// server does not support resuming (or there was nothing to resume)
+		flags |= os.O_TRUNC
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the partial download is already complete. it gets verified afterwards
+		return nil
+	default:
+		return fmt.Errorf("java download failed with status %s from %s", res.Status, url)
 	}
 
-	return archive, nil
+	f, err := os.OpenFile(target, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, res.Body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
 
-func (j *Java) downloadURL() string {
-	return j.asset.Binaries[0].Package.Link
+// verifyArchive checks the size and sha256 checksum of the archive (if they are known)
+func verifyArchive(archive string, size int64, checksum string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	written, err := io.Copy(hasher, f)
+	if err != nil {
+		return err
+	}
+
+	if size != 0 && written != size {
+		return fmt.Errorf("java download is corrupted: expected %d bytes but got %d", size, written)
+	}
+
+	actual := hex.EncodeToString(hasher.Sum(nil))
+	if checksum != "" && !strings.EqualFold(actual, checksum) {
+		return fmt.Errorf("java download is corrupted: expected sha256 %s but got %s", checksum, actual)
+	}
+	return nil
 }
@@ -0,0 +1,166 @@
+package java
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testEntry struct {
+	name     string
+	body     string
+	linkname string
+}
+
+func testArchive(t *testing.T, entries []testEntry) []byte {
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0755, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		switch {
+		case strings.HasSuffix(e.name, "/"):
+			header.Typeflag = tar.TypeDir
+		case e.linkname != "":
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = e.linkname
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			tw.Write([]byte(e.body))
+		}
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// newStandIn starts a fake adoptium API serving the given archive
+func newStandIn(t *testing.T, archive []byte, checksum string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/assets/feature_releases/17/ga"):
+			fmt.Fprintf(w, `[{
+				"binaries": [{"package": {"link": "%s/download/jdk.tar.gz", "checksum": "%s", "size": %d}}],
+				"version_data": {"semver": "17.0.4+8"}
+			}]`, server.URL, checksum, len(archive))
+		case r.URL.Path == "/download/jdk.tar.gz":
+			http.ServeContent(w, r, "jdk.tar.gz", time.Time{}, bytes.NewReader(archive))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestJava_Update(t *testing.T) {
+	archive := testArchive(t, []testEntry{
+		{name: "jdk-17.0.4+8/"},
+		{name: "jdk-17.0.4+8/bin/java", body: "#!/bin/sh\necho fake java"},
+		{name: "jdk-17.0.4+8/legal/LICENSE", body: "GPL"},
+		{name: "jdk-17.0.4+8/legal/COPYRIGHT", linkname: "LICENSE"},
+	})
+
+	tests := []struct {
+		name     string
+		checksum string
+		partial  []byte
+		wantErr  bool
+	}{
+		{name: "fresh install", checksum: sha256Hex(archive)},
+		{name: "resumes partial download", checksum: sha256Hex(archive), partial: archive[:len(archive)/2]},
+		{name: "checksum mismatch", checksum: sha256Hex([]byte("nope")), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStandIn(t, archive, tt.checksum)
+			factory := NewFactory(t.TempDir())
+			factory.SetAPIURL(VendorAdoptium, server.URL)
+
+			j, err := factory.Version(context.Background(), "17")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !j.NeedsDownloading() {
+				t.Fatal("expected java to need downloading")
+			}
+
+			if tt.partial != nil {
+				os.WriteFile(j.dir+".download.tar.gz", tt.partial, 0644)
+			}
+
+			err = j.Update(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if _, err := os.Stat(j.dir); !os.IsNotExist(err) {
+					t.Fatal("nothing should be installed after a failed download")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := os.Stat(filepath.Join(j.dir, "bin/java")); err != nil {
+				t.Fatalf("java binary missing: %s", err)
+			}
+			if _, err := os.Stat(j.dir + ".download.tar.gz"); !os.IsNotExist(err) {
+				t.Fatal("archive should be removed after install")
+			}
+
+			installed, err := factory.Installed()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(installed) != 1 || installed[0].Version() != "17.0.4+8" {
+				t.Fatalf("unexpected installed versions: %+v", installed)
+			}
+		})
+	}
+}
+
+func TestExtractArchive_maliciousPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry testEntry
+	}{
+		{"parent dir", testEntry{name: "jdk/../../evil", body: "x"}},
+		{"absolute", testEntry{name: "/etc/evil", body: "x"}},
+		{"symlink out of root", testEntry{name: "jdk/evil", linkname: "../../etc/passwd"}},
+		{"absolute symlink", testEntry{name: "jdk/evil", linkname: "/etc/passwd"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			archive := filepath.Join(dir, "evil.tar.gz")
+			os.WriteFile(archive, testArchive(t, []testEntry{tt.entry}), 0644)
+
+			err := extractArchive(archive, filepath.Join(dir, "out"))
+			if err == nil {
+				t.Fatalf("%s was extracted", tt.entry.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package java
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// MicrosoftAPI is the base url of the Microsoft Build of OpenJDK downloads
+const MicrosoftAPI = "https://aka.ms/download-jdk"
+
+// microsoftVersion matches the full version in archive names like "microsoft-jdk-17.0.4-linux-x64.tar.gz"
+var microsoftVersion = regexp.MustCompile(`^microsoft-jdk-([0-9][0-9.+_]*)-`)
+
+// microsoft provides the Microsoft Build of OpenJDK.
+// Microsoft has no API, but stable links that redirect to the latest release
+type microsoft struct{}
+
+func (m *microsoft) Name() string { return VendorMicrosoft }
+
+func (m *microsoft) Latest(ctx context.Context, client *http.Client, opts *AdoptAssetRequest) (*Release, error) {
+	if err := jdkOnly(VendorMicrosoft, opts); err != nil {
+		return nil, err
+	}
+
+	baseURL := strings.TrimSuffix(opts.baseURL, "/")
+	if baseURL == "" {
+		baseURL = MicrosoftAPI
+	}
+
+	osName := opts.os
+	switch osName {
+	case "mac":
+		osName = "macOS"
+	case "alpine-linux":
+		osName = "alpine"
+	}
+
+	link := fmt.Sprintf(
+		"%s/microsoft-jdk-%d-%s-%s.%s",
+		baseURL,
+		opts.featureVersion,
+		osName,
+		opts.architecture,
+		archiveType(opts.os),
+	)
+
+	// follow the redirect to find out which version this actually is
+	req, err := http.NewRequestWithContext(ctx, "HEAD", link, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrNoRelease
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("java download responded with unexpected status %s", res.Status)
+	}
+
+	release := &Release{Vendor: VendorMicrosoft, Link: res.Request.URL.String()}
+	if match := microsoftVersion.FindStringSubmatch(path.Base(res.Request.URL.Path)); match != nil {
+		release.Version = match[1]
+	}
+	if res.ContentLength > 0 {
+		release.Size = int(res.ContentLength)
+	}
+
+	checksum, err := fetchChecksum(ctx, client, link+".sha256sum.txt")
+	if err != nil {
+		return nil, err
+	}
+	release.Checksum = checksum
+
+	return release, nil
+}
+
+// fetchChecksum downloads a checksum file (`sha256sum` format or just the hash)
+// and returns the contained hash
+func fetchChecksum(ctx context.Context, client *http.Client, u string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not fetch java checksum: unexpected status %s", res.Status)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(res.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(raw))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("could not fetch java checksum: empty checksum file")
+	}
+	return fields[0], nil
+}
@@ -0,0 +1,129 @@
+package java
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// SystemJava is a java runtime that was not installed by minepkg
+type SystemJava struct {
+	// Bin is the path to the java binary
+	Bin string
+	// Version is the full java version (eg. "17.0.4")
+	Version string
+	// Vendor is the vendor reported by the runtime (eg. "Eclipse Adoptium")
+	Vendor string
+	// Source describes where this java was found (JAVA_HOME, PATH or a directory)
+	Source string
+}
+
+// FeatureVersion returns the major version of this runtime (eg. "8" or "17")
+func (s *SystemJava) FeatureVersion() string {
+	parts := strings.Split(s.Version, ".")
+	// old versions are reported as "1.8.0_292"
+	if parts[0] == "1" && len(parts) > 1 {
+		return parts[1]
+	}
+	return parts[0]
+}
+
+// systemJavaDirs are directories that usually contain multiple java installations
+var systemJavaDirs = map[string][]string{
+	"linux":   {"/usr/lib/jvm", "/usr/java", "/opt/java"},
+	"darwin":  {"/Library/Java/JavaVirtualMachines"},
+	"windows": {`C:\Program Files\Java`, `C:\Program Files\Eclipse Adoptium`},
+}
+
+// DetectSystem searches for java runtimes installed on this system.
+// It looks in JAVA_HOME, the PATH and a few well known directories
+func DetectSystem(ctx context.Context) []*SystemJava {
+	found := make([]*SystemJava, 0)
+	seen := make(map[string]bool)
+
+	add := func(bin string, source string) {
+		resolved, err := filepath.EvalSymlinks(bin)
+		if err != nil {
+			return
+		}
+		if seen[resolved] {
+			return
+		}
+		seen[resolved] = true
+
+		java, err := ReadSystemJava(ctx, bin)
+		if err != nil {
+			return
+		}
+		java.Source = source
+		found = append(found, java)
+	}
+
+	if home := os.Getenv("JAVA_HOME"); home != "" {
+		add(filepath.Join(home, binName()), "JAVA_HOME")
+	}
+
+	if bin, err := exec.LookPath("java"); err == nil {
+		add(bin, "PATH")
+	}
+
+	for _, dir := range systemJavaDirs[runtime.GOOS] {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			home := filepath.Join(dir, e.Name())
+			if runtime.GOOS == "darwin" {
+				home = filepath.Join(home, "Contents/Home")
+			}
+			add(filepath.Join(home, binName()), dir)
+		}
+	}
+
+	return found
+}
+
+// ReadSystemJava executes the given java binary to find out its version
+func ReadSystemJava(ctx context.Context, bin string) (*SystemJava, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// settings are printed to stderr
+	out, err := exec.CommandContext(ctx, bin, "-XshowSettings:properties", "-version").CombinedOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	java := &SystemJava{Bin: bin}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		// lines look like "    java.version = 17.0.4"
+		parts := strings.SplitN(scanner.Text(), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "java.version":
+			java.Version = value
+		case "java.vendor":
+			java.Vendor = value
+		}
+	}
+
+	return java, nil
+}
+
+func binName() string {
+	if runtime.GOOS == "windows" {
+		return `bin\java.exe`
+	}
+	return "bin/java"
+}
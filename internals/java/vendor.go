@@ -0,0 +1,90 @@
+package java
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+const (
+	// VendorAdoptium are the Eclipse Temurin builds (default)
+	VendorAdoptium = "adoptium"
+	// VendorZulu are the Azul Zulu builds
+	VendorZulu = "zulu"
+	// VendorMicrosoft are the Microsoft Build of OpenJDK builds
+	VendorMicrosoft = "microsoft"
+	// VendorGraalVM are the GraalVM Community Edition builds
+	VendorGraalVM = "graalvm"
+)
+
+// ErrNoRelease is returned if a vendor has no matching java release for this system
+var ErrNoRelease = errors.New("no java version found")
+
+// Release is a single downloadable java archive of a vendor
+type Release struct {
+	// Vendor is the name of the vendor that provides this release
+	Vendor string `json:"vendor"`
+	// Version is the full java version (eg. "17.0.4+8")
+	Version string `json:"version"`
+	// Link is the download url of the .tar.gz or .zip archive
+	Link string `json:"link"`
+	// Checksum is the sha256 checksum of the archive (hex encoded)
+	Checksum string `json:"checksum,omitempty"`
+	// Size is the size of the archive in bytes. 0 if unknown
+	Size int `json:"size,omitempty"`
+}
+
+// Vendor provides java releases
+type Vendor interface {
+	// Name returns the name of this vendor as used in version strings (eg. "zulu")
+	Name() string
+	// Latest returns the latest release matching the request
+	Latest(ctx context.Context, client *http.Client, req *AdoptAssetRequest) (*Release, error)
+}
+
+var vendors = map[string]Vendor{
+	VendorAdoptium:  &adoptium{},
+	VendorZulu:      &zulu{},
+	VendorMicrosoft: &microsoft{},
+	VendorGraalVM:   &graalvm{},
+}
+
+// Vendors returns the names of all supported vendors
+func Vendors() []string {
+	names := make([]string, 0, len(vendors))
+	for name := range vendors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jdkOnly returns an error if the request is not for a hotspot jdk.
+// Used by vendors that do not provide anything else
+func jdkOnly(vendor string, req *AdoptAssetRequest) error {
+	if req.imageType != "jdk" || req.jvmImpl != "hotspot" {
+		return fmt.Errorf("%s only provides hotspot jdk builds: %w", vendor, ErrNoRelease)
+	}
+	return nil
+}
+
+// vendorOS maps the adoptium os names to the ones used by most other vendors
+func vendorOS(adoptOS string) string {
+	switch adoptOS {
+	case "mac":
+		return "macos"
+	case "alpine-linux":
+		return "linux-musl"
+	}
+	return adoptOS
+}
+
+// archiveType returns the preferred archive type for the given (adoptium) os name
+func archiveType(adoptOS string) string {
+	if adoptOS == "windows" {
+		return "zip"
+	}
+	return "tar.gz"
+}
@@ -0,0 +1,152 @@
+package java
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewWantedVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{in: "17", want: "17-jdk-hotspot"},
+		{in: "8-jre", want: "8-jre-hotspot"},
+		{in: "17-jdk-hotspot-adoptium", want: "17-jdk-hotspot-adoptium"},
+		{in: "17-jdk-hotspot-zulu", want: "17-jdk-hotspot-zulu"},
+		{in: "17-jdk-hotspot-oracle", wantErr: ErrInvalidVendor},
+		{in: "17-jdk-hotspot-zulu-extra", wantErr: ErrInvalidVersionString},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NormalizeVersion(tt.in)
+			if err != tt.wantErr {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZulu_Latest(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/zulu/packages/":
+			q := r.URL.Query()
+			if q.Get("os") != "linux-musl" || q.Get("arch") != "aarch64" || q.Get("java_version") != "17" {
+				fmt.Fprint(w, `[]`)
+				return
+			}
+			fmt.Fprint(w, `[{"package_uuid": "abc", "java_version": [17, 0, 4]}]`)
+		case "/zulu/packages/abc":
+			fmt.Fprintf(w, `{
+				"package_uuid": "abc",
+				"java_version": [17, 0, 4],
+				"download_url": "%s/zulu17.tar.gz",
+				"sha256_hash": "deadbeef",
+				"size": 42
+			}`, server.URL)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	req := &AdoptAssetRequest{
+		baseURL:        server.URL,
+		featureVersion: 17,
+		os:             "alpine-linux",
+		architecture:   "aarch64",
+		vendor:         VendorZulu,
+	}
+	req.setDefaults()
+
+	release, err := (&zulu{}).Latest(context.Background(), http.DefaultClient, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Release{Vendor: VendorZulu, Version: "17.0.4", Link: server.URL + "/zulu17.tar.gz", Checksum: "deadbeef", Size: 42}
+	if *release != want {
+		t.Fatalf("got %+v, want %+v", release, want)
+	}
+}
+
+func TestMicrosoft_Latest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/microsoft-jdk-17-linux-x64.tar.gz":
+			http.Redirect(w, r, "/download/microsoft-jdk-17.0.4-linux-x64.tar.gz", http.StatusFound)
+		case "/microsoft-jdk-17-linux-x64.tar.gz.sha256sum.txt":
+			fmt.Fprint(w, "cafe  microsoft-jdk-17.0.4-linux-x64.tar.gz\n")
+		case "/download/microsoft-jdk-17.0.4-linux-x64.tar.gz":
+			w.Write([]byte("archive"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	req := &AdoptAssetRequest{baseURL: server.URL, featureVersion: 17, os: "linux", architecture: "x64"}
+	req.setDefaults()
+
+	release, err := (&microsoft{}).Latest(context.Background(), http.DefaultClient, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Version != "17.0.4" || release.Checksum != "cafe" || release.Link != server.URL+"/download/microsoft-jdk-17.0.4-linux-x64.tar.gz" {
+		t.Fatalf("unexpected release %+v", release)
+	}
+
+	req.imageType = "jre"
+	if _, err := (&microsoft{}).Latest(context.Background(), http.DefaultClient, req); err == nil {
+		t.Fatal("expected an error for jre builds")
+	}
+}
+
+func TestReadRelease_legacyAsset(t *testing.T) {
+	dir := t.TempDir()
+	asset := `{"binaries": [{"package": {"link": "https://example.com/jdk.tar.gz", "checksum": "abc", "size": 3}}], "version_data": {"semver": "17.0.4+8"}}`
+	os.WriteFile(filepath.Join(dir, "asset.json"), []byte(asset), 0644)
+
+	release, err := readRelease(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Vendor != VendorAdoptium || release.Version != "17.0.4+8" || release.Link != "https://example.com/jdk.tar.gz" {
+		t.Fatalf("unexpected release %+v", release)
+	}
+}
+
+func TestFactory_SetAPIURL(t *testing.T) {
+	factory := NewFactory(t.TempDir())
+	if err := factory.SetAPIURL("oracle", "https://example.com"); err != ErrInvalidVendor {
+		t.Fatalf("expected ErrInvalidVendor, got %v", err)
+	}
+	if err := factory.SetAPIURL(VendorZulu, "https://zulu.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if factory.apiURLs[VendorAdoptium] != "" {
+		t.Fatal("the zulu mirror must not be used for adoptium")
+	}
+}
+
+func TestJava_Identifier(t *testing.T) {
+	for dir, want := range map[string]string{
+		"17-jdk-hotspot":      "17-jdk-hotspot-adoptium",
+		"17-jdk-hotspot-zulu": "17-jdk-hotspot-zulu",
+	} {
+		j := &Java{dir: filepath.Join("java", dir)}
+		if got := j.Identifier(); got != want {
+			t.Errorf("%s: got %s, want %s", dir, got, want)
+		}
+	}
+}
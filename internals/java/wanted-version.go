@@ -12,10 +12,11 @@ type wantedVersion struct {
 }
 
 func newWantedVersion(s string) (*wantedVersion, error) {
+	// vendor is left empty here so the default vendor of the factory can be used
 	req := AdoptAssetRequest{featureVersion: 17, imageType: "jdk", jvmImpl: "hotspot"}
 	parts := strings.Split(s, "-")
 
-	if len(parts) > 3 {
+	if len(parts) > 4 {
 		return nil, ErrInvalidVersionString
 	}
 
@@ -48,10 +49,46 @@ func newWantedVersion(s string) (*wantedVersion, error) {
 		}
 	}
 
+	if len(parts) > 3 {
+		if _, ok := vendors[parts[3]]; !ok {
+			return nil, ErrInvalidVendor
+		}
+		req.vendor = parts[3]
+	}
+
 	return &wantedVersion{req}, nil
 }
 
+// NormalizeVersion validates the given version string (eg. "17-jre")
+// and returns it in its full identifier form (eg. "17-jre-hotspot").
+// The vendor is only part of the identifier if it was set explicitly (eg. "17-jdk-hotspot-zulu")
+func NormalizeVersion(s string) (string, error) {
+	wanted, err := newWantedVersion(s)
+	if err != nil {
+		return "", err
+	}
+	return wanted.Identifier(), nil
+}
+
+// Identifier returns the full version string. It contains the vendor if one is set
 func (w *wantedVersion) Identifier() string {
+	if w.vendor != "" {
+		return w.baseIdentifier() + "-" + w.vendor
+	}
+	return w.baseIdentifier()
+}
+
+// dirName returns the name of the directory this version is installed in
+func (w *wantedVersion) dirName() string {
+	// adoptium builds have no suffix to keep existing installations working
+	if w.vendor != "" && w.vendor != VendorAdoptium {
+		return w.baseIdentifier() + "-" + w.vendor
+	}
+	return w.baseIdentifier()
+}
+
+// baseIdentifier returns the version string without vendor
+func (w *wantedVersion) baseIdentifier() string {
 	feature := uint16(8)
 	if w.featureVersion != 0 {
 		feature = w.featureVersion
@@ -64,6 +101,5 @@ func (w *wantedVersion) Identifier() string {
 	if w.jvmImpl != "" {
 		jvmImpl = w.jvmImpl
 	}
-
 	return fmt.Sprintf("%d-%s-%s", feature, imageType, jvmImpl)
 }
@@ -0,0 +1,98 @@
+package java
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ZuluAPI is the Azul metadata API
+const ZuluAPI = "https://api.azul.com/metadata/v1"
+
+// zulu provides releases using the Azul metadata API
+type zulu struct{}
+
+type zuluPackage struct {
+	PackageUUID string `json:"package_uuid"`
+	Name        string `json:"name"`
+	JavaVersion []int  `json:"java_version"`
+	DownloadURL string `json:"download_url"`
+	SHA256Hash  string `json:"sha256_hash"`
+	Size        int    `json:"size"`
+}
+
+func (z *zulu) Name() string { return VendorZulu }
+
+func (z *zulu) Latest(ctx context.Context, client *http.Client, opts *AdoptAssetRequest) (*Release, error) {
+	if opts.jvmImpl != "hotspot" || (opts.imageType != "jdk" && opts.imageType != "jre") {
+		return nil, fmt.Errorf("zulu only provides hotspot jdk and jre builds: %w", ErrNoRelease)
+	}
+
+	baseURL := strings.TrimSuffix(opts.baseURL, "/")
+	if baseURL == "" {
+		baseURL = ZuluAPI
+	}
+
+	params := url.Values{}
+	params.Add("java_version", strconv.Itoa(int(opts.featureVersion)))
+	params.Add("os", vendorOS(opts.os))
+	params.Add("arch", opts.architecture)
+	params.Add("archive_type", archiveType(opts.os))
+	params.Add("java_package_type", opts.imageType)
+	params.Add("javafx_bundled", "false")
+	params.Add("release_status", "ga")
+	params.Add("availability_types", "CA")
+	params.Add("latest", "true")
+
+	packages := make([]zuluPackage, 0, 1)
+	if err := getJSON(ctx, client, baseURL+"/zulu/packages/?"+params.Encode(), &packages); err != nil {
+		return nil, err
+	}
+	if len(packages) == 0 {
+		return nil, ErrNoRelease
+	}
+
+	// the list does not contain checksums, so we need to fetch the details
+	pkg := &zuluPackage{}
+	if err := getJSON(ctx, client, baseURL+"/zulu/packages/"+url.PathEscape(packages[0].PackageUUID), pkg); err != nil {
+		return nil, err
+	}
+
+	version := make([]string, len(pkg.JavaVersion))
+	for i, v := range pkg.JavaVersion {
+		version[i] = strconv.Itoa(v)
+	}
+
+	return &Release{
+		Vendor:   VendorZulu,
+		Version:  strings.Join(version, "."),
+		Link:     pkg.DownloadURL,
+		Checksum: pkg.SHA256Hash,
+		Size:     pkg.Size,
+	}, nil
+}
+
+// getJSON fetches the given url and decodes the json response into v
+func getJSON(ctx context.Context, client *http.Client, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("java API responded with unexpected status %s", res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,79 @@
+package launcher
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/backup"
+)
+
+// backupInterval returns the interval of scheduled backups. 0 means no backups are scheduled
+func (c *Launcher) backupInterval() (time.Duration, error) {
+	if !c.ServerMode {
+		return 0, nil
+	}
+	if c.BackupInterval != 0 {
+		return c.BackupInterval, nil
+	}
+	settings, err := c.Instance.BackupSettings()
+	if err != nil {
+		return 0, err
+	}
+	interval, err := settings.Interval()
+	if err != nil {
+		return 0, fmt.Errorf("invalid backup.every setting: %w", err)
+	}
+	return interval, nil
+}
+
+// scheduleBackups creates a backup every interval until done is closed
+func (c *Launcher) scheduleBackups(interval time.Duration, stdin *backup.StdinCommander, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.scheduledBackup(stdin); err != nil {
+			fmt.Printf("[minepkg] Backup failed: %s\n", err)
+		}
+	}
+}
+
+// scheduledBackup backs up the server world & prunes old backups. rcon is preferred to
+// pause saving because it waits for the world to be saved. stdin is used as fallback
+func (c *Launcher) scheduledBackup(stdin *backup.StdinCommander) error {
+	settings, err := c.Instance.BackupSettings()
+	if err != nil {
+		return err
+	}
+	format, err := backup.ParseFormat(settings.Format)
+	if err != nil {
+		return err
+	}
+
+	var server backup.Commander = stdin
+	if client, err := c.Instance.DialRcon(5 * time.Second); err == nil {
+		defer client.Close()
+		server = client
+	}
+
+	created, err := c.Instance.CreateBackup(server, format)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("[minepkg] Created backup %s\n", created.Name)
+
+	pruned, err := c.Instance.BackupStore().Prune(settings.Policy())
+	if err != nil {
+		return err
+	}
+	if len(pruned) != 0 {
+		fmt.Printf("[minepkg] Removed %d old backups\n", len(pruned))
+	}
+	return nil
+}
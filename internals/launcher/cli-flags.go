@@ -14,6 +14,7 @@ type OverwriteFlags struct {
 	MinepkgCompanion string
 	Java             string
 	Ram              int
+	JVMArgs          []string
 }
 
 func CmdOverwriteFlags(cmd *cobra.Command) *OverwriteFlags {
@@ -22,6 +23,7 @@ func CmdOverwriteFlags(cmd *cobra.Command) *OverwriteFlags {
 	cmd.Flags().StringVar(&flags.FabricVersion, "fabricLoader", "", "Overwrite the required fabricLoader version")
 	cmd.Flags().StringVar(&flags.MinepkgCompanion, "minepkgCompanion", "", "Overwrite the required minepkg companion version (can also be \"none\")")
 	cmd.Flags().IntVar(&flags.Ram, "ram", 0, "Overwrite the amount of RAM in MiB to use")
+	cmd.Flags().StringArrayVar(&flags.JVMArgs, "jvm-arg", nil, "Pass an additional argument to java (can be used multiple times)")
 	cmd.Flags().StringVar(&flags.Java, "java", "", "Overwrite the Java runtime. Examples: 16-jre, 8-jre-openj9, system")
 
 	return &flags
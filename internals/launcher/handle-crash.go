@@ -7,12 +7,28 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
+	"github.com/erikgeiser/promptkit/confirmation"
+	"github.com/jwalton/gchalk"
 	"github.com/minepkg/minepkg/internals/api"
+	"github.com/minepkg/minepkg/internals/auth"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/crash"
 	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/viper"
 )
 
-// HandleCrash handles a crash by submitting it to minepkg.io and outputting some debug info
+// Values of the `crashReports` config
+const (
+	CrashReportsAsk    = "ask"
+	CrashReportsAlways = "always"
+	CrashReportsNever  = "never"
+)
+
+// HandleCrash handles a crash by outputting some debug info, saving a redacted crash report
+// and submitting it to minepkg.io (if the user agreed to that).
+// It exits minepkg unless the server is supervised, supervised servers get restarted
 func (c *Launcher) HandleCrash() error {
 	// exit code was not 130 or 0, we output error info and submit a crash report
 	man := c.Instance.Manifest
@@ -20,14 +36,12 @@ func (c *Launcher) HandleCrash() error {
 
 	packageName := man.Package.Name
 	packageVersion := man.Package.Version
+	customized := false
 	if man.Package.BasedOn != "" {
-		if len(man.Dependencies) != 1 {
-			fmt.Println("Customized modpacks do not support crash reports for now. Skipping")
-			return nil
-		}
 		// looks like an unmodified modpack instance. use that as name
 		packageName = man.Package.BasedOn
 		packageVersion = man.Dependencies[packageName]
+		customized = len(man.Dependencies) != 1
 	}
 
 	fmt.Println("--------------------")
@@ -54,7 +68,10 @@ func (c *Launcher) HandleCrash() error {
 	}
 	fmt.Printf("  exit code: %d\n", c.Cmd.ProcessState.ExitCode())
 
-	fmt.Println("\nSubmitting crash report to minepkg.io …")
+	log := c.latestLog()
+	redactor := c.redactor(log)
+
+	findings := c.printCrashAnalysis(redactor)
 
 	mods := make(map[string]string)
 
@@ -82,9 +99,8 @@ func (c *Launcher) HandleCrash() error {
 		ExitCode:         c.Cmd.ProcessState.ExitCode(),
 	}
 
-	logPath := filepath.Join(c.Instance.McDir(), "logs/latest.log")
-	if log, err := ioutil.ReadFile(logPath); err == nil {
-		report.Logs = string(log)
+	if log != "" {
+		report.Logs = redactor.Redact(log)
 	}
 
 	if c.Instance.Platform() == instances.PlatformFabric {
@@ -94,10 +110,33 @@ func (c *Launcher) HandleCrash() error {
 		}
 	}
 
-	err := c.Instance.MinepkgAPI.PostCrashReport(context.TODO(), &report)
-	if err != nil {
-		fmt.Println("Could not submit crash report:")
+	store := crash.NewStore(c.Instance.CrashReportsDir())
+	saved := &crash.SavedReport{Report: &report, Findings: findings}
+	if err := store.Save(saved); err != nil {
+		fmt.Println("Could not save crash report:")
 		fmt.Println(err)
+	} else {
+		fmt.Printf("\nSaved crash report %s. Show it with %s\n", saved.ID, gchalk.Bold("minepkg crash show "+saved.ID))
+	}
+
+	var err error
+	switch {
+	case customized:
+		fmt.Println("Customized modpacks do not support crash reports for now. Skipping")
+	case c.shouldSubmitCrashReport():
+		fmt.Println("\nSubmitting crash report to minepkg.io …")
+		err = c.Instance.MinepkgAPI.PostCrashReport(context.TODO(), &report)
+		if err != nil {
+			fmt.Println("Could not submit crash report:")
+			fmt.Println(err)
+		} else {
+			saved.Submitted = true
+			store.Save(saved)
+		}
+	}
+
+	if c.Supervise != nil {
+		return err
 	}
 
 	// exit with special status code, so tools know that minecraft crashed
@@ -105,3 +144,90 @@ func (c *Launcher) HandleCrash() error {
 	os.Exit(69)
 	return err
 }
+
+// shouldSubmitCrashReport checks the `crashReports` config and asks the user if needed
+func (c *Launcher) shouldSubmitCrashReport() bool {
+	switch strings.ToLower(viper.GetString("crashReports")) {
+	case CrashReportsAlways:
+		return true
+	case CrashReportsNever:
+		return false
+	}
+
+	// supervised servers get the user input, so we can not ask
+	if c.NonInteractive || c.Supervise != nil {
+		fmt.Printf(
+			"Not submitting the crash report. Send it with %s or set %s\n",
+			gchalk.Bold("minepkg crash submit"),
+			gchalk.Bold("minepkg config set crashReports always"),
+		)
+		return false
+	}
+
+	input := confirmation.New("Submit the crash report (with redacted logs) to minepkg.io?", confirmation.Yes)
+	submit, err := input.RunPrompt()
+	return err == nil && submit
+}
+
+// latestLog returns the content of the latest log of Minecraft or an empty string if there is none
+func (c *Launcher) latestLog() string {
+	log, err := ioutil.ReadFile(filepath.Join(c.Instance.McDir(), "logs/latest.log"))
+	if err != nil {
+		return ""
+	}
+	return string(log)
+}
+
+// redactor returns a redactor that removes the credentials of this launch, private paths
+// and the names of all players that are in log or known to the server
+func (c *Launcher) redactor(log string) *crash.Redactor {
+	r := &crash.Redactor{}
+	if home, err := os.UserHomeDir(); err == nil {
+		r.Home = home
+	}
+	if creds := c.Instance.AuthCredentials; creds != nil {
+		// the token of offline players is a plain word that would be removed everywhere
+		if creds.AccessToken != auth.OfflineAccessToken {
+			r.Secrets = append(r.Secrets, creds.AccessToken)
+		}
+		r.Players = append(r.Players, creds.PlayerName)
+	}
+	r.Players = append(r.Players, crash.LogPlayers(log)...)
+	r.Players = append(r.Players, c.Instance.KnownPlayers()...)
+	return r
+}
+
+// printCrashAnalysis analyzes the crash report & log and prints the problems it found.
+// The evidence of the findings is redacted with redactor
+func (c *Launcher) printCrashAnalysis(redactor *crash.Redactor) []*crash.Finding {
+	mods, err := crash.ScanMods(c.Instance.ModsDir())
+	if err != nil {
+		mods = nil
+	}
+
+	findings, err := crash.New(mods, c.Instance.Lockfile).AnalyzeDir(c.Instance.McDir(), c.started)
+	if err != nil || len(findings) == 0 {
+		return nil
+	}
+	for _, f := range findings {
+		f.Evidence = redactor.Redact(f.Evidence)
+	}
+
+	fmt.Println("\nThis is what probably went wrong:")
+	for _, f := range findings {
+		fmt.Println(FindingError(f).RichError())
+	}
+	return findings
+}
+
+// FindingError formats a crash analysis finding as `commands.CliError`
+func FindingError(f *crash.Finding) *commands.CliError {
+	cliErr := &commands.CliError{
+		Text:        f.Problem,
+		Suggestions: f.Suggestions,
+	}
+	if len(f.Packages) != 0 {
+		cliErr.Help = fmt.Sprintf("caused by: %s", strings.Join(f.Packages, ", "))
+	}
+	return cliErr
+}
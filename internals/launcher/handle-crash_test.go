@@ -0,0 +1,49 @@
+package launcher
+
+import (
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/auth"
+	"github.com/minepkg/minepkg/internals/crash"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/spf13/viper"
+)
+
+func TestLauncher_redactor(t *testing.T) {
+	instance := &instances.Instance{Directory: t.TempDir()}
+	instance.SetLaunchCredentials(&instances.LaunchCredentials{PlayerName: "Steve", AccessToken: auth.OfflineAccessToken})
+	c := &Launcher{Instance: instance}
+
+	got := c.redactor("[12:00:00] [Server thread/INFO]: Alex joined the game").
+		Redact("Steve and Alex play offline")
+	if got != "<player> and <player> play offline" {
+		t.Fatalf("unexpected redaction %q", got)
+	}
+}
+
+func TestLauncher_handleExit(t *testing.T) {
+	viper.Set("crashReports", CrashReportsNever)
+	defer viper.Set("crashReports", "")
+
+	instance := &instances.Instance{
+		Directory: t.TempDir(),
+		Manifest:  manifest.New(),
+		Lockfile:  &manifest.Lockfile{Vanilla: &manifest.VanillaLock{Minecraft: "1.18.2"}},
+	}
+	cmd, _ := helperCommand("crash")()
+	// supervised launchers do not exit minepkg after a crash
+	c := &Launcher{Instance: instance, Cmd: cmd, Supervise: &SuperviseOptions{}}
+
+	if err := c.handleExit(cmd.Run()); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := crash.NewStore(instance.CrashReportsDir()).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Report.ExitCode != 1 {
+		t.Fatalf("expected one saved report with exit code 1, got %v", reports)
+	}
+}
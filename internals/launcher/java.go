@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/minepkg/minepkg/internals/java"
+	"github.com/spf13/viper"
 )
 
 func (l *Launcher) Java(ctx context.Context) (*java.Java, error) {
@@ -53,6 +55,31 @@ func (l *Launcher) Java(ctx context.Context) (*java.Java, error) {
 	return java, nil
 }
 
+// applyPinnedJava uses the java runtime pinned in the local instance settings
+// if no java version was set explicitly
+func (l *Launcher) applyPinnedJava() error {
+	if l.JavaVersion != "" || l.JavaBinary != "" || l.UseSystemJava {
+		return nil
+	}
+
+	settings, err := l.Instance.LocalSettings()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case settings.Java == "":
+		// nothing pinned
+	case settings.Java == "system":
+		l.UseSystemJava = true
+	case strings.ContainsAny(settings.Java, `/\`):
+		l.JavaBinary = settings.Java
+	default:
+		l.JavaVersion = settings.Java
+	}
+	return nil
+}
+
 func (l Launcher) javaFactory() (*java.Factory, error) {
 	if l.javaFactoryInstance != nil {
 		return l.javaFactoryInstance, nil
@@ -61,6 +88,26 @@ func (l Launcher) javaFactory() (*java.Factory, error) {
 	if err != nil {
 		return nil, err
 	}
-	l.javaFactoryInstance = java.NewFactory(filepath.Join(userCache, "minepkg", "java"))
+	l.javaFactoryInstance, err = NewJavaFactory(filepath.Join(userCache, "minepkg", "java"))
+	if err != nil {
+		return nil, err
+	}
 	return l.javaFactoryInstance, nil
 }
+
+// NewJavaFactory returns a java factory for dir that uses the `java.mirrors.<vendor>`
+// and `java.vendor` config
+func NewJavaFactory(dir string) (*java.Factory, error) {
+	factory := java.NewFactory(dir)
+	for _, vendor := range java.Vendors() {
+		if mirror := viper.GetString("java.mirrors." + vendor); mirror != "" {
+			factory.SetAPIURL(vendor, mirror)
+		}
+	}
+	if vendor := viper.GetString("java.vendor"); vendor != "" {
+		if err := factory.SetDefaultVendor(vendor); err != nil {
+			return nil, err
+		}
+	}
+	return factory, nil
+}
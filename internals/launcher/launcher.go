@@ -2,6 +2,7 @@ package launcher
 
 import (
 	"os/exec"
+	"time"
 
 	"github.com/minepkg/minepkg/internals/instances"
 	"github.com/minepkg/minepkg/internals/java"
@@ -39,8 +40,21 @@ type Launcher struct {
 	// JavaVersion is the version to use
 	JavaVersion string
 
+	// JavaBinary is the path to a java binary that should be used instead of
+	// a managed java installation. This skips downloading java
+	JavaBinary string
+
+	// BackupInterval creates world backups in this interval while the server runs.
+	// 0 uses the `every` backup setting of the instance
+	BackupInterval time.Duration
+
+	// Supervise restarts the server after crashes and stops it gracefully on ctrl-c.
+	// nil starts the server unsupervised
+	Supervise *SuperviseOptions
+
 	javaFactoryInstance *java.Factory
 	java                *java.Java
 	introPrinted        bool
+	started             time.Time
 	originalServerProps []byte
 }
@@ -2,16 +2,19 @@ package launcher
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strings"
+	"strconv"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jwalton/gchalk"
 	"github.com/minepkg/minepkg/internals/downloadmgr"
 	"github.com/minepkg/minepkg/internals/minecraft"
+	"github.com/minepkg/minepkg/internals/rcon"
 	"github.com/spf13/viper"
 )
 
@@ -59,7 +62,9 @@ func (l *Launcher) Prepare() error {
 
 	if l.ServerMode {
 		fmt.Println(pipeText.Render("\nPreparing server"))
-		l.prepareServer()
+		if err := l.prepareServer(); err != nil {
+			return fmt.Errorf("failed to prepare server: %w", err)
+		}
 		if l.OfflineMode {
 			pipeText.Render("  in offline mode")
 			l.prepareOfflineServer()
@@ -112,7 +117,12 @@ func (l Launcher) PrepareRequirements() (bool, error) {
 // prepareJava downloads java if needed and returns an error channel
 func (l *Launcher) PrepareJavaBg(ctx context.Context) chan error {
 	javaUpdate := make(chan error, 1)
-	if l.UseSystemJava {
+	if err := l.applyPinnedJava(); err != nil {
+		javaUpdate <- fmt.Errorf("failed to read local settings: %w", err)
+		return javaUpdate
+	}
+
+	if l.UseSystemJava || l.JavaBinary != "" {
 		// nothing gets downloaded. this is a success
 		javaUpdate <- nil
 	} else {
@@ -170,7 +180,11 @@ func (l *Launcher) PrepareMinecraft(ctx context.Context) error {
 	mgr := downloadmgr.New()
 
 	fmt.Println(pipeText.Render(gchalk.Gray("Preparing Minecraft")))
-	launchManifest, err := instance.GetLaunchManifest()
+	getManifest := instance.GetLaunchManifest
+	if l.ServerMode {
+		getManifest = instance.GetServerLaunchManifest
+	}
+	launchManifest, err := getManifest()
 	if err != nil {
 		return fmt.Errorf("failed to get launch manifest: %w", err)
 	}
@@ -178,12 +192,20 @@ func (l *Launcher) PrepareMinecraft(ctx context.Context) error {
 
 	// check for JAR
 	// TODO move more logic to internals
-	mainJar := filepath.Join(l.Instance.VersionsDir(), l.LaunchManifest.MinecraftVersion(), l.LaunchManifest.JarName())
-	if _, err := os.Stat(mainJar); os.IsNotExist(err) {
-		mgr.Add(downloadmgr.NewHTTPItem(l.LaunchManifest.Downloads.Client.URL, mainJar))
-	}
+	if l.ServerMode {
+		serverJar := instance.ServerJarPath(launchManifest)
+		if _, err := os.Stat(serverJar); os.IsNotExist(err) {
+			if launchManifest.Downloads.Server.URL == "" {
+				return fmt.Errorf("minecraft %s has no dedicated server download", launchManifest.MinecraftVersion())
+			}
+			mgr.Add(downloadmgr.NewHTTPItem(launchManifest.Downloads.Server.URL, serverJar))
+		}
+	} else {
+		mainJar := filepath.Join(instance.VersionsDir(), launchManifest.MinecraftVersion(), launchManifest.JarName())
+		if _, err := os.Stat(mainJar); os.IsNotExist(err) {
+			mgr.Add(downloadmgr.NewHTTPItem(launchManifest.Downloads.Client.URL, mainJar))
+		}
 
-	if !l.ServerMode {
 		missingAssets, err := instance.FindMissingAssets(launchManifest)
 		if err != nil {
 			return err
@@ -217,13 +239,19 @@ func (l *Launcher) PrepareMinecraft(ctx context.Context) error {
 		return err
 	}
 
+	// unpack bundled server jars now, so launching is fast
+	if l.ServerMode {
+		if _, _, err := instance.ServerClasspath(launchManifest); err != nil {
+			return fmt.Errorf("failed to read server jar: %w", err)
+		}
+	}
+
 	fmt.Println(pipeText.Render(""))
 
 	return nil
 }
 
-func (c *Launcher) prepareServer() {
-	c.LaunchManifest.MainClass = strings.Replace(c.LaunchManifest.MainClass, "Client", "Server", -1)
+func (c *Launcher) prepareServer() error {
 	instance := c.Instance
 
 	// TODO: better handling
@@ -231,11 +259,57 @@ func (c *Launcher) prepareServer() {
 		eula := "# accepted through minepkg\n# https://account.mojang.com/documents/minecraft_eula\neula=true\n"
 		ioutil.WriteFile(filepath.Join(instance.McDir(), "./eula.txt"), []byte(eula), 0644)
 	}
+
+	if err := c.applyServerProperties(); err != nil {
+		return err
+	}
+	return c.prepareRcon()
+}
+
+// applyServerProperties writes the `[server.properties]` of the manifest to the `server.properties` file
+func (c *Launcher) applyServerProperties() error {
+	server := c.Instance.Manifest.Server
+	if server == nil || len(server.Properties) == 0 {
+		return nil
+	}
+
+	props, err := c.Instance.ServerProperties()
+	if err != nil {
+		return err
+	}
+	values := server.FlatProperties()
+	for _, key := range server.PropertyKeys() {
+		props.Set(key, values[key])
+	}
+	return c.Instance.SaveServerProperties(props)
+}
+
+// prepareRcon enables rcon with a random password, so `minepkg server exec` can send commands to the server
+func (c *Launcher) prepareRcon() error {
+	props, err := c.Instance.ServerProperties()
+	if err != nil {
+		return err
+	}
+	if props.Value("enable-rcon") == "true" && props.Value("rcon.password") != "" {
+		return nil
+	}
+
+	props.Set("enable-rcon", "true")
+	if props.Value("rcon.password") == "" {
+		secret := make([]byte, 16)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		props.Set("rcon.password", hex.EncodeToString(secret))
+	}
+	if props.Value("rcon.port") == "" {
+		props.Set("rcon.port", strconv.Itoa(rcon.DefaultPort))
+	}
+	return c.Instance.SaveServerProperties(props)
 }
 
 func (c *Launcher) prepareOfflineServer() {
-	settingsFile := filepath.Join(c.Instance.McDir(), "server.properties")
-	rawSettings, err := ioutil.ReadFile(settingsFile)
+	rawSettings, err := ioutil.ReadFile(c.Instance.ServerPropertiesPath())
 
 	// workaround to get server that was started in offline mode for the first time
 	// to start in online mode next time it is launched
@@ -247,10 +321,10 @@ func (c *Launcher) prepareOfflineServer() {
 	c.originalServerProps = rawSettings
 
 	settings := minecraft.ParseServerProps(rawSettings)
-	settings["online-mode"] = "false"
+	settings.Set("online-mode", "false")
 
 	// write modified config file
-	if err := ioutil.WriteFile(settingsFile, []byte(settings.String()), 0644); err != nil {
+	if err := c.Instance.SaveServerProperties(settings); err != nil {
 		panic(err)
 	}
 }
@@ -272,11 +346,17 @@ func (c *Launcher) printIntro() {
 	fmt.Println(title)
 	fmt.Println("│")
 	fmt.Println("│ Directory: " + c.Instance.Directory)
+	if creds := c.Instance.AuthCredentials; creds != nil && creds.Account != "" {
+		fmt.Printf("│ Account: %s (%s)\n", creds.Account, creds.PlayerName)
+	}
 }
 
 func (l *Launcher) printOutro() {
 	javaDir := "(system java)"
-	if !l.UseSystemJava {
+	switch {
+	case l.JavaBinary != "":
+		javaDir = l.JavaBinary
+	case !l.UseSystemJava:
 		javaDir = l.java.Bin()
 	}
 	fmt.Println("│ minepkg " + l.MinepkgVersion)
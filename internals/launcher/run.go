@@ -1,14 +1,19 @@
 package launcher
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/backup"
 	"github.com/minepkg/minepkg/internals/commands"
 	"github.com/minepkg/minepkg/internals/instances"
 )
@@ -26,17 +31,16 @@ func (c *Launcher) Run(opts *instances.LaunchOptions) error {
 		),
 	)
 
-	switch {
-	case opts.LaunchManifest == nil:
-		opts.LaunchManifest = c.LaunchManifest
-	case !opts.Server:
-		opts.Server = c.ServerMode
+	c.applyLaunchDefaults(opts)
+	unlock, err := c.Instance.LockRun(c.ServerMode)
+	if err != nil {
+		return err
 	}
+	defer unlock()
+	c.Instance.MarkPlayed()
 
-	if c.UseSystemJava {
-		opts.Java = "java"
-	} else {
-		opts.Java = c.java.Bin()
+	if c.Supervise != nil && c.ServerMode {
+		return c.supervise(opts)
 	}
 
 	cmd, err := c.Instance.BuildLaunchCmd(opts)
@@ -44,17 +48,40 @@ func (c *Launcher) Run(opts *instances.LaunchOptions) error {
 		return err
 	}
 
+	backupInterval, err := c.backupInterval()
+	if err != nil {
+		return err
+	}
+
 	// Pass input to minecraft.
 	cmd.Stdin = os.Stdin
+	var stdin *backup.StdinCommander
+	if backupInterval != 0 {
+		// scheduled backups need to send commands to the server as well
+		cmd.Stdin = nil
+		pipe, err := cmd.StdinPipe()
+		if err != nil {
+			return err
+		}
+		stdin = &backup.StdinCommander{W: pipe, Wait: 10 * time.Second}
+		go io.Copy(stdin, os.Stdin)
+	}
 
 	c.Cmd = cmd
 
 	err = func() error {
 		runtime.GC()
+		c.started = time.Now()
 		if err := cmd.Start(); err != nil {
 			return err
 		}
 
+		if backupInterval != 0 {
+			done := make(chan struct{})
+			defer close(done)
+			go c.scheduleBackups(backupInterval, stdin, done)
+		}
+
 		// we wait for the output to finish (the lines following this one usually are reached after ctrl-c was pressed)
 		if err := cmd.Wait(); err != nil {
 			return err
@@ -63,15 +90,22 @@ func (c *Launcher) Run(opts *instances.LaunchOptions) error {
 		return nil
 	}()
 
+	return c.handleExit(err)
+}
+
+// handleExit handles the result of a finished Minecraft process.
+// A process that exited with an error code is handled as a crash
+func (c *Launcher) handleExit(err error) error {
 	// minecraft server will always return code 130 when
 	// stop was successful, so we ignore the error here
-	if cmd.ProcessState.ExitCode() == 130 || cmd.ProcessState.ExitCode() == 0 {
+	if c.Cmd.ProcessState.ExitCode() == 130 || c.Cmd.ProcessState.ExitCode() == 0 {
 		fmt.Println("\nMinecraft was stopped normally")
 		return nil
 	}
 
-	// TODO: what kind of errors are here?
-	if err != nil {
+	// the process could not be started or waited for
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
 		return err
 	}
 
@@ -82,3 +116,33 @@ func (c *Launcher) Run(opts *instances.LaunchOptions) error {
 
 	return c.HandleCrash()
 }
+
+// RunDetached starts the instance in the background and returns immediately.
+// The output is written to the log file of the instance
+func (c *Launcher) RunDetached(opts *instances.LaunchOptions) (*instances.RunInfo, error) {
+	c.applyLaunchDefaults(opts)
+	c.Instance.MarkPlayed()
+	return c.Instance.LaunchDetached(opts)
+}
+
+// applyLaunchDefaults sets the launch manifest, server mode and java runtime if not set in opts
+func (c *Launcher) applyLaunchDefaults(opts *instances.LaunchOptions) {
+	switch {
+	case opts.LaunchManifest == nil:
+		opts.LaunchManifest = c.LaunchManifest
+	case !opts.Server:
+		opts.Server = c.ServerMode
+	}
+
+	switch {
+	case c.JavaBinary != "":
+		opts.Java = c.JavaBinary
+	case c.UseSystemJava:
+		opts.Java = "java"
+	default:
+		opts.Java = c.java.Bin()
+		if opts.JavaVersion == 0 {
+			opts.JavaVersion = c.java.FeatureVersion()
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package launcher
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/smoketest"
+)
+
+// SmokeTest starts the server, runs test against it and makes sure it is stopped afterwards
+func (c *Launcher) SmokeTest(opts *instances.LaunchOptions, test *smoketest.Test) (*smoketest.Report, error) {
+	c.applyLaunchDefaults(opts)
+	opts.Server = true
+	unlock, err := c.Instance.LockRun(true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	output := opts.Stdout
+	if output == nil {
+		output = os.Stdout
+	}
+	// the output is printed & parsed by the test. stdout and stderr only write complete lines,
+	// so their output does not get mixed up in the middle of a line
+	pr, pw := io.Pipe()
+	mu := &sync.Mutex{}
+	stdout := &lineWriter{mu: mu, out: io.MultiWriter(output, pw)}
+	stderr := &lineWriter{mu: mu, out: io.MultiWriter(output, pw)}
+	opts.Stdout = stdout
+	opts.Stderr = stderr
+
+	cmd, err := c.Instance.BuildLaunchCmd(opts)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdin = nil
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Cmd = cmd
+	c.started = time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		stdout.Flush()
+		stderr.Flush()
+		pw.Close()
+		close(exited)
+	}()
+
+	report := test.Run(&smoketest.Server{
+		Stdin:    stdin,
+		Output:   pr,
+		Exited:   exited,
+		ExitCode: func() int { return cmd.ProcessState.ExitCode() },
+	})
+
+	// the server should never outlive the test
+	select {
+	case <-exited:
+	default:
+		cmd.Process.Kill()
+		<-exited
+	}
+
+	if !report.Passed() {
+		c.printCrashAnalysis(c.redactor(c.latestLog()))
+	}
+	return report, nil
+}
+
+// lineWriter passes only complete lines to out. Writers that share mu never write at the same time
+type lineWriter struct {
+	mu  *sync.Mutex
+	out io.Writer
+	buf []byte
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.buf = append(l.buf, p...)
+	end := bytes.LastIndexByte(l.buf, '\n')
+	if end == -1 {
+		return len(p), nil
+	}
+	l.mu.Lock()
+	_, err := l.out.Write(l.buf[:end+1])
+	l.mu.Unlock()
+	l.buf = append(l.buf[:0], l.buf[end+1:]...)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes the remaining incomplete line
+func (l *lineWriter) Flush() error {
+	if len(l.buf) == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.out.Write(l.buf)
+	l.buf = l.buf[:0]
+	return err
+}
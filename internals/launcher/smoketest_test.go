@@ -0,0 +1,24 @@
+package launcher
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	mu := &sync.Mutex{}
+	stdout := &lineWriter{mu: mu, out: out}
+	stderr := &lineWriter{mu: mu, out: out}
+
+	stdout.Write([]byte("[12:00:00] [Server thread/INFO]: Do"))
+	stderr.Write([]byte("Exception in thread \"main\"\n"))
+	stdout.Write([]byte("ne (3.2s)!\n[12:00:01] partial"))
+	stdout.Flush()
+
+	want := "Exception in thread \"main\"\n[12:00:00] [Server thread/INFO]: Done (3.2s)!\n[12:00:01] partial"
+	if out.String() != want {
+		t.Fatalf("got %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,324 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/instances"
+)
+
+// ErrCrashLoop is returned if a supervised server crashed too often in a short time
+var ErrCrashLoop = errors.New("server is crash looping")
+
+// ExitReason describes why a supervised server process ended
+type ExitReason string
+
+const (
+	// ExitStopped means the server was stopped from its console or with rcon
+	ExitStopped ExitReason = "stopped"
+	// ExitSignal means the server was stopped because minepkg received ctrl-c or SIGTERM
+	ExitSignal ExitReason = "signal"
+	// ExitKilled means the server did not stop within the stop timeout and was killed
+	ExitKilled ExitReason = "killed"
+	// ExitCrashed means the server exited with an error. It gets restarted
+	ExitCrashed ExitReason = "crashed"
+	// ExitCrashLoop means the server crashed too often and is not restarted anymore
+	ExitCrashLoop ExitReason = "crash-loop"
+)
+
+// SuperviseOptions configure how a supervised server is stopped and restarted
+type SuperviseOptions struct {
+	// StopTimeout is the time the server gets to stop after "stop" was sent. It is killed afterwards
+	StopTimeout time.Duration
+	// MaxCrashes is the number of crashes within `CrashWindow` after which the server is not restarted anymore
+	MaxCrashes int
+	// CrashWindow is the time span in which crashes are counted
+	CrashWindow time.Duration
+	// MinBackoff is the delay before the first restart. It doubles with every crash up to `MaxBackoff`
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between restarts
+	MaxBackoff time.Duration
+}
+
+// withDefaults returns a copy of the options with defaults for all zero values
+func (s SuperviseOptions) withDefaults() SuperviseOptions {
+	if s.StopTimeout == 0 {
+		s.StopTimeout = time.Minute
+	}
+	if s.MaxCrashes == 0 {
+		s.MaxCrashes = 5
+	}
+	if s.CrashWindow == 0 {
+		s.CrashWindow = 10 * time.Minute
+	}
+	if s.MinBackoff == 0 {
+		s.MinBackoff = 5 * time.Second
+	}
+	if s.MaxBackoff == 0 {
+		s.MaxBackoff = 5 * time.Minute
+	}
+	if s.MaxBackoff < s.MinBackoff {
+		s.MaxBackoff = s.MinBackoff
+	}
+	return s
+}
+
+// SupervisorEvent is logged by the supervisor whenever the server starts or exits
+type SupervisorEvent struct {
+	Event    string
+	Attempt  int
+	PID      int
+	ExitCode int
+	Reason   ExitReason
+	Uptime   time.Duration
+	// RestartIn is the delay before the next restart (only set for crashes)
+	RestartIn time.Duration
+}
+
+// String formats the event as logfmt line
+func (e *SupervisorEvent) String() string {
+	fields := []string{"event=" + e.Event, fmt.Sprintf("attempt=%d", e.Attempt)}
+	if e.PID != 0 {
+		fields = append(fields, fmt.Sprintf("pid=%d", e.PID))
+	}
+	if e.Event == "exited" {
+		fields = append(fields, fmt.Sprintf("exit_code=%d", e.ExitCode), fmt.Sprintf("uptime=%s", e.Uptime.Round(time.Second)))
+	}
+	if e.Reason != "" {
+		fields = append(fields, "reason="+string(e.Reason))
+	}
+	if e.RestartIn != 0 {
+		fields = append(fields, fmt.Sprintf("restart_in=%s", e.RestartIn))
+	}
+	return strings.Join(fields, " ")
+}
+
+// supervisor runs a server process & restarts it after crashes
+type supervisor struct {
+	options SuperviseOptions
+	// start builds a new, not yet started, server process
+	start func() (*exec.Cmd, error)
+	// crashed is called after every crash of the server
+	crashed func(cmd *exec.Cmd)
+	// running is called after the server started. The returned function is called after it exited
+	running func(stdin *backup.StdinCommander) func()
+	signals <-chan os.Signal
+	input   *stdinSwitch
+	log     io.Writer
+}
+
+func (s *supervisor) logEvent(e *SupervisorEvent) {
+	fmt.Fprintf(s.log, "[minepkg] supervisor %s\n", e)
+}
+
+// run starts the server and restarts it until it is stopped or crash loops
+func (s *supervisor) run() error {
+	options := s.options.withDefaults()
+	backoff := options.MinBackoff
+	crashes := make([]time.Time, 0, options.MaxCrashes)
+
+	for attempt := 1; ; attempt++ {
+		cmd, err := s.start()
+		if err != nil {
+			return err
+		}
+		// stdin is used to send "stop", user input is forwarded through input
+		cmd.Stdin = nil
+		pipe, err := cmd.StdinPipe()
+		if err != nil {
+			return err
+		}
+		isolateProcess(cmd)
+
+		started := time.Now()
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		stdin := &backup.StdinCommander{W: pipe}
+		if s.input != nil {
+			s.input.Set(stdin)
+		}
+		s.logEvent(&SupervisorEvent{Event: "started", Attempt: attempt, PID: cmd.Process.Pid})
+
+		exited := make(chan struct{})
+		go func() {
+			cmd.Wait()
+			close(exited)
+		}()
+		stopRunning := func() {}
+		if s.running != nil {
+			stopRunning = s.running(stdin)
+		}
+
+		reason := ExitReason("")
+		select {
+		case <-exited:
+		case <-s.signals:
+			reason = s.stop(cmd, stdin, exited, options.StopTimeout)
+		}
+		stopRunning()
+		pipe.Close()
+
+		event := &SupervisorEvent{
+			Event:    "exited",
+			Attempt:  attempt,
+			PID:      cmd.Process.Pid,
+			ExitCode: cmd.ProcessState.ExitCode(),
+			Uptime:   time.Since(started),
+			Reason:   reason,
+		}
+		if reason != "" {
+			s.logEvent(event)
+			return nil
+		}
+
+		// minecraft exits with 130 after the stop command
+		if event.ExitCode == 0 || event.ExitCode == 130 {
+			event.Reason = ExitStopped
+			s.logEvent(event)
+			return nil
+		}
+
+		// only crashes within the window count towards the crash loop limit
+		now := time.Now()
+		recent := crashes[:0]
+		for _, crash := range crashes {
+			if now.Sub(crash) < options.CrashWindow {
+				recent = append(recent, crash)
+			}
+		}
+		crashes = append(recent, now)
+		// the server ran fine for a while, so start with a short backoff again
+		if event.Uptime > options.CrashWindow {
+			backoff = options.MinBackoff
+		}
+
+		if len(crashes) >= options.MaxCrashes {
+			event.Reason = ExitCrashLoop
+			s.logEvent(event)
+			if s.crashed != nil {
+				s.crashed(cmd)
+			}
+			return fmt.Errorf("%w: crashed %d times within %s", ErrCrashLoop, len(crashes), options.CrashWindow)
+		}
+
+		event.Reason = ExitCrashed
+		event.RestartIn = backoff
+		s.logEvent(event)
+		if s.crashed != nil {
+			s.crashed(cmd)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-s.signals:
+			s.logEvent(&SupervisorEvent{Event: "cancelled", Attempt: attempt, Reason: ExitSignal})
+			return nil
+		}
+		backoff *= 2
+		if backoff > options.MaxBackoff {
+			backoff = options.MaxBackoff
+		}
+	}
+}
+
+// stop sends "stop" to the server and waits until it exited. The server is killed after the
+// timeout or if another signal is received
+func (s *supervisor) stop(cmd *exec.Cmd, stdin *backup.StdinCommander, exited <-chan struct{}, timeout time.Duration) ExitReason {
+	fmt.Fprintln(s.log, "[minepkg] Stopping the server. Press ctrl-c again to kill it")
+	if _, err := stdin.Exec("stop"); err != nil {
+		cmd.Process.Kill()
+		<-exited
+		return ExitKilled
+	}
+
+	select {
+	case <-exited:
+		return ExitSignal
+	case <-time.After(timeout):
+		fmt.Fprintf(s.log, "[minepkg] The server did not stop within %s. Killing it\n", timeout)
+	case <-s.signals:
+	}
+	cmd.Process.Kill()
+	<-exited
+	return ExitKilled
+}
+
+// stdinSwitch forwards user input to the stdin of the current server process
+type stdinSwitch struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Set changes the writer input is forwarded to
+func (s *stdinSwitch) Set(w io.Writer) {
+	s.mu.Lock()
+	s.w = w
+	s.mu.Unlock()
+}
+
+func (s *stdinSwitch) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	w := s.w
+	s.mu.Unlock()
+	if w == nil {
+		return len(p), nil
+	}
+	// input for a stopped server is dropped, it should not end forwarding
+	w.Write(p)
+	return len(p), nil
+}
+
+// supervise runs the server & restarts it after crashes. See `Launcher.Supervise`
+func (c *Launcher) supervise(opts *instances.LaunchOptions) error {
+	backupInterval, err := c.backupInterval()
+	if err != nil {
+		return err
+	}
+
+	// we stop the server ourself with the stop command
+	opts.NoSignalForwarding = true
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	input := &stdinSwitch{}
+	go io.Copy(input, os.Stdin)
+
+	s := &supervisor{
+		options: *c.Supervise,
+		start: func() (*exec.Cmd, error) {
+			cmd, err := c.Instance.BuildLaunchCmd(opts)
+			if err != nil {
+				return nil, err
+			}
+			c.Cmd = cmd
+			c.started = time.Now()
+			return cmd, nil
+		},
+		crashed: func(cmd *exec.Cmd) {
+			c.HandleCrash()
+		},
+		running: func(stdin *backup.StdinCommander) func() {
+			if backupInterval == 0 {
+				return func() {}
+			}
+			done := make(chan struct{})
+			go c.scheduleBackups(backupInterval, &backup.StdinCommander{W: stdin, Wait: 10 * time.Second}, done)
+			return func() { close(done) }
+		},
+		signals: signals,
+		input:   input,
+		log:     os.Stdout,
+	}
+	return s.run()
+}
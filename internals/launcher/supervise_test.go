@@ -0,0 +1,104 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess is not a real test. It is started by the supervisor tests as fake server
+func TestHelperProcess(t *testing.T) {
+	switch os.Getenv("MINEPKG_HELPER_PROCESS") {
+	case "crash":
+		os.Exit(1)
+	case "server":
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if scanner.Text() == "stop" {
+				os.Exit(0)
+			}
+		}
+		os.Exit(2)
+	case "hang":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+}
+
+func helperCommand(mode string) func() (*exec.Cmd, error) {
+	return func() (*exec.Cmd, error) {
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+		cmd.Env = append(os.Environ(), "MINEPKG_HELPER_PROCESS="+mode)
+		return cmd, nil
+	}
+}
+
+func TestSupervisor_CrashLoop(t *testing.T) {
+	log := &bytes.Buffer{}
+	crashes := 0
+	s := &supervisor{
+		options: SuperviseOptions{MaxCrashes: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond},
+		start:   helperCommand("crash"),
+		crashed: func(cmd *exec.Cmd) { crashes++ },
+		log:     log,
+	}
+
+	err := s.run()
+	if !errors.Is(err, ErrCrashLoop) {
+		t.Fatalf("expected ErrCrashLoop, got %v", err)
+	}
+	if crashes != 3 {
+		t.Fatalf("expected 3 crashes, got %d", crashes)
+	}
+	if got := strings.Count(log.String(), "event=started"); got != 3 {
+		t.Fatalf("expected 3 starts, got %d:\n%s", got, log)
+	}
+	if !strings.Contains(log.String(), "reason=crashed restart_in=1ms") || !strings.Contains(log.String(), "restart_in=2ms") {
+		t.Fatalf("backoff not logged:\n%s", log)
+	}
+	if !strings.Contains(log.String(), "exit_code=1 uptime=0s reason=crash-loop") {
+		t.Fatalf("crash loop not logged:\n%s", log)
+	}
+}
+
+func TestSupervisor_StopOnSignal(t *testing.T) {
+	log := &bytes.Buffer{}
+	signals := make(chan os.Signal, 1)
+	s := &supervisor{
+		start:   helperCommand("server"),
+		signals: signals,
+		log:     log,
+	}
+
+	signals <- os.Interrupt
+	if err := s.run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(log.String(), "exit_code=0") || !strings.Contains(log.String(), "reason=signal") {
+		t.Fatalf("graceful stop not logged:\n%s", log)
+	}
+}
+
+func TestSupervisor_StopTimeout(t *testing.T) {
+	log := &bytes.Buffer{}
+	signals := make(chan os.Signal, 1)
+	s := &supervisor{
+		options: SuperviseOptions{StopTimeout: 100 * time.Millisecond},
+		start:   helperCommand("hang"),
+		signals: signals,
+		log:     log,
+	}
+
+	signals <- os.Interrupt
+	if err := s.run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(log.String(), "reason=killed") {
+		t.Fatalf("kill not logged:\n%s", log)
+	}
+}
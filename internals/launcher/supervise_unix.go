@@ -0,0 +1,14 @@
+//go:build !windows
+
+package launcher
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// isolateProcess starts the process in its own process group, so ctrl-c in the terminal
+// does not reach it and the supervisor can stop it gracefully
+func isolateProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package launcher
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+const createNewProcessGroup = 0x00000200
+
+// isolateProcess starts the process in its own process group, so ctrl-c in the console
+// does not reach it and the supervisor can stop it gracefully
+func isolateProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: createNewProcessGroup}
+}
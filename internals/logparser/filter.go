@@ -0,0 +1,137 @@
+package logparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Output formats supported by `Writer`
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var (
+	// ErrInvalidLevel is returned for unknown log levels
+	ErrInvalidLevel = errors.New("invalid log level. must be trace, debug, info, warn, error or fatal")
+	// ErrInvalidFormat is returned for unknown output formats
+	ErrInvalidFormat = errors.New("invalid log format. must be text or json")
+)
+
+// levels maps all known levels to their severity
+var levels = map[string]int{
+	"TRACE":   0,
+	"DEBUG":   1,
+	"INFO":    2,
+	"WARN":    3,
+	"WARNING": 3,
+	"ERROR":   4,
+	"SEVERE":  4,
+	"FATAL":   5,
+}
+
+// Filter decides which log lines should be kept
+type Filter struct {
+	// MinLevel is the lowest level that is kept (eg. "warn"). Keeps everything if empty
+	MinLevel string
+	// Tags only keeps lines with one of these tags (case insensitive substring match). Keeps everything if empty
+	Tags []string
+}
+
+// Validate checks if the filter uses a known level
+func (f *Filter) Validate() error {
+	if f.MinLevel == "" {
+		return nil
+	}
+	if _, ok := levels[strings.ToUpper(f.MinLevel)]; !ok {
+		return ErrInvalidLevel
+	}
+	return nil
+}
+
+// Match returns true if the line should be kept.
+// Unparsable lines are treated as info lines without tag
+func (f *Filter) Match(l *LogLine) bool {
+	if f.MinLevel != "" {
+		level, ok := levels[strings.ToUpper(l.Level)]
+		if !ok {
+			level = levels["INFO"]
+		}
+		if level < levels[strings.ToUpper(f.MinLevel)] {
+			return false
+		}
+	}
+
+	if len(f.Tags) == 0 {
+		return true
+	}
+	tag := strings.ToLower(l.Tag)
+	for _, t := range f.Tags {
+		if tag != "" && strings.Contains(tag, strings.ToLower(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Writer parses everything written to it and writes the filtered lines
+// in the given format to the underlying writer. Call `Close` to flush the last line
+type Writer struct {
+	pw   *io.PipeWriter
+	done chan struct{}
+	mu   sync.Mutex
+}
+
+// NewWriter returns a new `Writer`. format has to be `FormatText` or `FormatJSON`
+func NewWriter(out io.Writer, format string, filter *Filter) (*Writer, error) {
+	if format == "" {
+		format = FormatText
+	}
+	if format != FormatText && format != FormatJSON {
+		return nil, ErrInvalidFormat
+	}
+	if filter == nil {
+		filter = &Filter{}
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	w := &Writer{pw: pw, done: make(chan struct{})}
+	encoder := json.NewEncoder(out)
+
+	go func() {
+		defer close(w.done)
+		Stream(pr, 200*time.Millisecond, func(l *LogLine) {
+			if !filter.Match(l) {
+				return
+			}
+			if format == FormatJSON {
+				encoder.Encode(l)
+				return
+			}
+			fmt.Fprintln(out, l.String())
+		})
+	}()
+
+	return w, nil
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.pw.Write(p)
+}
+
+// Close flushes the remaining output and waits until everything was written
+func (w *Writer) Close() error {
+	err := w.pw.Close()
+	<-w.done
+	return err
+}
@@ -3,28 +3,66 @@ package logparser
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 )
 
 const timeFormat = "15:04:05"
 
+// timeFormats are all supported time prefixes. Formats without date are parsed with a zero date
+var timeFormats = []string{
+	timeFormat,
+	"15:04:05.000",
+	// newer forge versions (eg. "[20Jun2022 13:46:33.123]")
+	"02Jan2006 15:04:05.000",
+	"2006-01-02 15:04:05",
+}
+
+var (
+	// lineRegex matches all supported formats:
+	//   [13:46:33] [main/INFO] [FML]: message
+	//   [13:46:33] [Server thread/INFO]: message
+	//   [13:46:33] [main/INFO] (FabricLoader) message
+	//   [20Jun2022 13:46:33.123] [main/INFO] [cpw.mods.modlauncher.Launcher/MODLAUNCHER]: message
+	lineRegex = regexp.MustCompile(`^\[([^\]]+)\] \[(.+?)/([A-Z]+)\](?: \[(.+?)\]:| \((.+?)\)|:) ?(.*)$`)
+	// continuationRegex matches lines that belong to the previous line (like stacktraces)
+	continuationRegex = regexp.MustCompile(`^(\s|Caused by: |Suppressed: |[\w$]+(\.[\w$]+)+(Exception|Error|Throwable)\b)`)
+)
+
 // LogLine is a parsed log line
 type LogLine struct {
-	Time    time.Time
-	Thread  string
-	Level   string
-	Tag     string
-	Message string
-	Garbage bool
+	Time    time.Time `json:"time"`
+	Thread  string    `json:"thread,omitempty"`
+	Level   string    `json:"level,omitempty"`
+	Tag     string    `json:"tag,omitempty"`
+	Message string    `json:"message"`
+	// Trace contains the following lines that belong to this one (usually a stacktrace)
+	Trace []string `json:"trace,omitempty"`
+	// Garbage is true if the line could not be parsed. `Message` contains the whole line in that case
+	Garbage bool `json:"garbage,omitempty"`
+
+	format string
 }
 
 func (l LogLine) String() string {
-	if l.Garbage == true {
+	lines := append([]string{l.firstLine()}, l.Trace...)
+	return strings.Join(lines, "\n")
+}
+
+func (l LogLine) firstLine() string {
+	if l.Garbage {
 		return l.Message
 	}
+	format := l.format
+	if format == "" {
+		format = timeFormat
+	}
+	if l.Tag == "" {
+		return fmt.Sprintf("[%s] [%s/%s]: %s", l.Time.Format(format), l.Thread, l.Level, l.Message)
+	}
 	return fmt.Sprintf(
 		"[%s] [%s/%s] [%s]: %s",
-		l.Time.Format(timeFormat),
+		l.Time.Format(format),
 		l.Thread,
 		l.Level,
 		l.Tag,
@@ -32,26 +70,43 @@ func (l LogLine) String() string {
 	)
 }
 
-// ParseLine parses a string into a `LogLine`
+// ParseLine parses a single line into a `LogLine`. Times without date have a zero date.
+// Use a `Parser` to group multi-line messages like stacktraces
 func ParseLine(input string) *LogLine {
-	r := regexp.MustCompile(`\[(\d+:\d+:\d+)\] \[(.+)\/(.+)\] \[(.+)\]: (.+)`)
-
-	found := r.FindStringSubmatch(input)
+	found := lineRegex.FindStringSubmatch(input)
 	if len(found) == 0 {
 		return &LogLine{Garbage: true, Message: input}
 	}
-	time, err := time.Parse(timeFormat, found[1])
-	if err != nil {
-		return &LogLine{Garbage: true, Message: input}
-	}
 
 	parsed := &LogLine{
-		Time:    time,
 		Thread:  found[2],
 		Level:   found[3],
 		Tag:     found[4],
-		Message: found[5],
+		Message: found[6],
+	}
+	// fabric style tag
+	if found[5] != "" {
+		parsed.Tag = found[5]
+	}
+
+	for _, format := range timeFormats {
+		t, err := time.Parse(format, found[1])
+		if err == nil {
+			parsed.Time = t
+			parsed.format = format
+			return parsed
+		}
 	}
 
-	return parsed
+	return &LogLine{Garbage: true, Message: input}
+}
+
+// HasDate returns true if the time of this line includes a date
+func (l *LogLine) HasDate() bool {
+	return !l.Garbage && l.Time.Year() != 0
+}
+
+// isContinuation returns true if the line belongs to the previous one
+func isContinuation(line string) bool {
+	return continuationRegex.MatchString(line)
 }
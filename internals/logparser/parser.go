@@ -0,0 +1,100 @@
+package logparser
+
+import (
+	"bufio"
+	"io"
+	"time"
+)
+
+// Parser groups lines into `LogLine`s. Lines that belong to the previous line
+// (like stacktraces) are added to its `Trace`
+type Parser struct {
+	// Date is used for log lines that only contain a time and as time of unparsable lines.
+	// The zero date is used if not set
+	Date    time.Time
+	pending *LogLine
+}
+
+// Push adds a single line. It returns the previous `LogLine` if it is complete.
+// The last line is only returned by `Flush`, as more lines could belong to it
+func (p *Parser) Push(line string) *LogLine {
+	if p.pending != nil && isContinuation(line) {
+		p.pending.Trace = append(p.pending.Trace, line)
+		return nil
+	}
+
+	parsed := ParseLine(line)
+	// unparsable lines have no time, so we use the time they were read
+	if parsed.Garbage {
+		parsed.Time = p.Date
+	}
+	if !parsed.Garbage && !parsed.HasDate() && !p.Date.IsZero() {
+		y, m, d := p.Date.Date()
+		parsed.Time = time.Date(y, m, d, parsed.Time.Hour(), parsed.Time.Minute(), parsed.Time.Second(), parsed.Time.Nanosecond(), p.Date.Location())
+	}
+
+	complete := p.pending
+	p.pending = parsed
+	return complete
+}
+
+// Flush returns the pending `LogLine` (if any)
+func (p *Parser) Flush() *LogLine {
+	complete := p.pending
+	p.pending = nil
+	return complete
+}
+
+// Stream reads r line by line and calls fn for every complete `LogLine`.
+// The last line is passed to fn if no new line was read within `idle`, so live output is not held back.
+// Lines without date get the current date
+func Stream(r io.Reader, idle time.Duration, fn func(*LogLine)) error {
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		err := scanner.Err()
+		readErr <- err
+		close(lines)
+		if err != nil {
+			// keep reading, otherwise the writer (like the output of Minecraft) would block forever
+			io.Copy(io.Discard, r)
+		}
+	}()
+
+	parser := &Parser{}
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if last := parser.Flush(); last != nil {
+					fn(last)
+				}
+				return <-readErr
+			}
+			// servers can run for days
+			parser.Date = time.Now()
+			if complete := parser.Push(line); complete != nil {
+				fn(complete)
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(idle)
+		case <-timer.C:
+			if last := parser.Flush(); last != nil {
+				fn(last)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package logparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine_formats(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  LogLine
+	}{
+		{
+			name:  "vanilla without tag",
+			input: "[13:46:33] [Server thread/INFO]: Done (3.2s)! For help, type \"help\"",
+			want:  LogLine{Thread: "Server thread", Level: "INFO", Message: "Done (3.2s)! For help, type \"help\""},
+		},
+		{
+			name:  "fabric",
+			input: "[13:46:33] [main/INFO] (FabricLoader) Loading 42 mods",
+			want:  LogLine{Thread: "main", Level: "INFO", Tag: "FabricLoader", Message: "Loading 42 mods"},
+		},
+		{
+			name:  "date prefixed",
+			input: "[20Jun2022 13:46:33.123] [main/WARN] [cpw.mods.modlauncher.Launcher/MODLAUNCHER]: Something",
+			want:  LogLine{Thread: "main", Level: "WARN", Tag: "cpw.mods.modlauncher.Launcher/MODLAUNCHER", Message: "Something"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseLine(tt.input)
+			if got.Garbage {
+				t.Fatalf("%q was not parsed", tt.input)
+			}
+			if got.Thread != tt.want.Thread || got.Level != tt.want.Level || got.Tag != tt.want.Tag || got.Message != tt.want.Message {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	dated := ParseLine("[20Jun2022 13:46:33.123] [main/INFO] [FML]: x")
+	if !dated.HasDate() || dated.Time.Day() != 20 {
+		t.Fatalf("date was not parsed: %s", dated.Time)
+	}
+}
+
+func TestParser_groupsStacktraces(t *testing.T) {
+	input := []string{
+		"[13:46:33] [main/INFO] [FML]: Starting",
+		"[13:46:34] [main/ERROR] [FML]: Could not load mod",
+		"java.lang.RuntimeException: broken",
+		"\tat net.example.Mod.init(Mod.java:12)",
+		"Caused by: java.lang.NullPointerException",
+		"\t... 3 more",
+		"[13:46:35] [main/INFO] [FML]: Continuing",
+		"random garbage",
+	}
+
+	p := &Parser{}
+	lines := make([]*LogLine, 0)
+	for _, line := range input {
+		if complete := p.Push(line); complete != nil {
+			lines = append(lines, complete)
+		}
+	}
+	lines = append(lines, p.Flush())
+
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d", len(lines))
+	}
+	if len(lines[1].Trace) != 4 {
+		t.Fatalf("expected the stacktrace to be grouped, got %q", lines[1].Trace)
+	}
+	if lines[1].String() != strings.Join(input[1:6], "\n") {
+		t.Fatalf("grouped line did not produce the same output:\n%s", lines[1])
+	}
+	if !lines[3].Garbage {
+		t.Fatal("expected the last line to be garbage")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	out := new(bytes.Buffer)
+	w, err := NewWriter(out, FormatJSON, &Filter{MinLevel: "warn", Tags: []string{"fabricloader"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w.Write([]byte("[13:46:33] [main/INFO] (FabricLoader) Loading 42 mods\n"))
+	w.Write([]byte("[13:46:34] [main/WARN] (FabricLoader) Mod a uses a deprecated api\n"))
+	w.Write([]byte("[13:46:35] [main/ERROR] [minecraft/Main]: Crashed\n"))
+	w.Close()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %q", lines)
+	}
+
+	parsed := struct {
+		Time    time.Time
+		Level   string
+		Tag     string
+		Message string
+	}{}
+	if err := json.Unmarshal([]byte(lines[0]), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Level != "WARN" || parsed.Message != "Mod a uses a deprecated api" {
+		t.Fatalf("unexpected line %s", lines[0])
+	}
+	if parsed.Time.Year() != time.Now().Year() {
+		t.Fatalf("expected the current date to be used, got %s", parsed.Time)
+	}
+
+	if _, err := NewWriter(out, "xml", nil); err != ErrInvalidFormat {
+		t.Fatalf("expected ErrInvalidFormat, got %v", err)
+	}
+}
+
+func TestStream_drainsAfterError(t *testing.T) {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- Stream(pr, time.Second, func(*LogLine) {})
+	}()
+
+	// a line that is too long for the scanner
+	if _, err := pw.Write([]byte(strings.Repeat("x", 2*1024*1024) + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err == nil {
+		t.Fatal("expected an error for the long line")
+	}
+
+	// the writer must not block after the error
+	written := make(chan struct{})
+	go func() {
+		pw.Write([]byte("[13:46:33] [Server thread/INFO]: still running\n"))
+		pw.Close()
+		close(written)
+	}()
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writer blocked after the scanner failed")
+	}
+}
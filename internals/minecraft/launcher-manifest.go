@@ -118,6 +118,11 @@ func (l *LaunchManifest) JarName() string {
 	return l.MinecraftVersion() + ".jar"
 }
 
+// ServerJarName returns the name of the dedicated server jar (for example `1.18.1-server.jar`)
+func (l *LaunchManifest) ServerJarName() string {
+	return l.MinecraftVersion() + "-server.jar"
+}
+
 // MinecraftVersion returns the minecraft version
 func (l *LaunchManifest) MinecraftVersion() string {
 	v := l.Jar
@@ -0,0 +1,162 @@
+package microsoft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrDeviceCodeExpired is returned if the user did not sign in before the device code expired
+	ErrDeviceCodeExpired = errors.New("the code expired before the sign in was completed")
+	// ErrDeviceLoginDeclined is returned if the user declined the sign in
+	ErrDeviceLoginDeclined = errors.New("the sign in was declined")
+)
+
+// devicePollUnit is the unit of the poll interval returned by the API (seconds). Tests make this shorter
+var devicePollUnit = time.Second
+
+// DeviceCode is the response of the device authorization endpoint
+type DeviceCode struct {
+	DeviceCode string `json:"device_code"`
+	// UserCode is the code the user enters on the VerificationURI page
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	// ExpiresIn is the number of seconds the code is valid
+	ExpiresIn int `json:"expires_in"`
+	// Interval is the number of seconds to wait between polling for the token
+	Interval int `json:"interval"`
+	// Message is a human readable instruction from Microsoft
+	Message string `json:"message"`
+}
+
+type deviceTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+// RequestDeviceCode starts the device authorization flow. The user has to open the
+// verification URI and enter the user code on any device, then `PollDeviceToken` returns
+func (m *MicrosoftClient) RequestDeviceCode(ctx context.Context) (*DeviceCode, error) {
+	form := url.Values{
+		"client_id": {m.Config.ClientID},
+		"scope":     {strings.Join(m.Config.Scopes, " ")},
+	}
+	res, err := m.postForm(ctx, m.Endpoints.DeviceCode, form)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		errorResponse := &deviceTokenResponse{}
+		if err := json.NewDecoder(res.Body).Decode(errorResponse); err == nil && errorResponse.Error != "" {
+			return nil, fmt.Errorf("device code request failed: %s (%s)", errorResponse.Error, errorResponse.Description)
+		}
+		return nil, fmt.Errorf("device code request failed with status %d (%s)", res.StatusCode, res.Status)
+	}
+
+	code := &DeviceCode{}
+	if err := json.NewDecoder(res.Body).Decode(code); err != nil {
+		return nil, err
+	}
+	return code, nil
+}
+
+// PollDeviceToken waits until the user signed in with the device code and sets the oauth token.
+// Returns `ErrDeviceCodeExpired` or `ErrDeviceLoginDeclined` if the user did not sign in
+func (m *MicrosoftClient) PollDeviceToken(ctx context.Context, code *DeviceCode) error {
+	interval := time.Duration(code.Interval) * devicePollUnit
+	if code.Interval <= 0 {
+		interval = 5 * devicePollUnit
+	}
+	if code.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(code.ExpiresIn)*devicePollUnit)
+		defer cancel()
+	}
+
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"client_id":   {m.Config.ClientID},
+		"device_code": {code.DeviceCode},
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrDeviceCodeExpired
+			}
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+
+		token, err := m.requestDeviceToken(ctx, form)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrDeviceCodeExpired
+			}
+			return err
+		}
+
+		switch token.Error {
+		case "":
+			m.SetOauthToken(&oauth2.Token{
+				AccessToken:  token.AccessToken,
+				RefreshToken: token.RefreshToken,
+				TokenType:    token.TokenType,
+				Expiry:       time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+			})
+			return nil
+		case "authorization_pending":
+			// user did not sign in yet
+		case "slow_down":
+			interval += 5 * devicePollUnit
+		case "authorization_declined":
+			return ErrDeviceLoginDeclined
+		case "expired_token":
+			return ErrDeviceCodeExpired
+		default:
+			return fmt.Errorf("device login failed: %s (%s)", token.Error, token.Description)
+		}
+	}
+}
+
+func (m *MicrosoftClient) requestDeviceToken(ctx context.Context, form url.Values) (*deviceTokenResponse, error) {
+	res, err := m.postForm(ctx, m.Config.Endpoint.TokenURL, form)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	token := &deviceTokenResponse{}
+	if err := json.NewDecoder(res.Body).Decode(token); err != nil {
+		return nil, fmt.Errorf("device token request failed with status %d (%s)", res.StatusCode, res.Status)
+	}
+	if res.StatusCode != http.StatusOK && token.Error == "" {
+		return nil, fmt.Errorf("device token request failed with status %d (%s)", res.StatusCode, res.Status)
+	}
+	return token, nil
+}
+
+func (m *MicrosoftClient) postForm(ctx context.Context, endpoint string, form url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	return m.Client.Do(req)
+}
@@ -43,7 +43,7 @@ func (m *MicrosoftClient) xblAuth(ctx context.Context, token string) (*xblAuthRe
 		"RelyingParty": "http://auth.xboxlive.com",
 		"TokenType": "JWT"
 	}`, token)
-	req, _ := jsonPostReqFromText(XBL_AUTHENTICATE, body)
+	req, _ := jsonPostReqFromText(m.Endpoints.XBLAuthenticate, body)
 	req = req.WithContext(ctx)
 	res, err := m.xblClient.Do(req)
 	if err != nil {
@@ -77,7 +77,7 @@ func (m *MicrosoftClient) xstsAuth(ctx context.Context, xblToken string) (*xblAu
 		"RelyingParty": "rp://api.minecraftservices.com/",
 		"TokenType": "JWT"
 	}`, xblToken)
-	req, _ := jsonPostReqFromText(XBL_XSTS_AUTHORIZE, body)
+	req, _ := jsonPostReqFromText(m.Endpoints.XSTSAuthorize, body)
 	req = req.WithContext(ctx)
 	res, err := m.xblClient.Do(req)
 	if err != nil {
@@ -47,7 +47,7 @@ type GetProfileResponse struct {
 func (m *MicrosoftClient) minecraftLoginWithXbox(ctx context.Context, userHash string, token string) (*XboxLoginResponse, error) {
 	body := fmt.Sprintf(`{ "identityToken": "x=%s;%s" }`, userHash, token)
 
-	req, _ := jsonPostReqFromText(MC_API_XBOX_LOGIN, body)
+	req, _ := jsonPostReqFromText(m.Endpoints.MinecraftLogin, body)
 	req = req.WithContext(ctx)
 	res, err := m.Client.Do(req)
 	if err != nil {
@@ -67,7 +67,7 @@ func (m *MicrosoftClient) minecraftLoginWithXbox(ctx context.Context, userHash s
 }
 
 func (m *MicrosoftClient) getProfile(ctx context.Context, token string) (*GetProfileResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", MC_API_PROFILE, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", m.Endpoints.Profile, nil)
 	if err != nil {
 		return nil, err
 	}
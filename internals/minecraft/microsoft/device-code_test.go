@@ -0,0 +1,139 @@
+package microsoft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeAPI implements the Microsoft, Xbox Live and Minecraft endpoints used by the device login
+func fakeAPI(t *testing.T, tokenErrors ...string) (*MicrosoftClient, *httptest.Server) {
+	t.Helper()
+	polls := 0
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/devicecode", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "test-client" || r.Form.Get("scope") != "XboxLive.signin offline_access" {
+			t.Errorf("unexpected device code request %v", r.Form)
+		}
+		reply(w, 200, map[string]interface{}{
+			"device_code":      "device-123",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": "https://microsoft.com/link",
+			"expires_in":       900,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("device_code") != "device-123" || r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:device_code" {
+			t.Errorf("unexpected token request %v", r.Form)
+		}
+		if polls < len(tokenErrors) {
+			polls++
+			reply(w, 400, map[string]string{"error": tokenErrors[polls-1]})
+			return
+		}
+		reply(w, 200, map[string]interface{}{
+			"access_token":  "ms-token",
+			"refresh_token": "ms-refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	mux.HandleFunc("/xbl", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, 200, map[string]interface{}{"Token": "xbl-token"})
+	})
+	mux.HandleFunc("/xsts", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, 200, map[string]interface{}{
+			"Token":         "xsts-token",
+			"DisplayClaims": map[string]interface{}{"xui": []map[string]string{{"uhs": "user-hash"}}},
+		})
+	})
+	mux.HandleFunc("/login_with_xbox", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, 200, map[string]interface{}{"access_token": "mc-token", "expires_in": 86400})
+	})
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer mc-token" {
+			w.WriteHeader(401)
+			return
+		}
+		reply(w, 200, map[string]string{"id": "069a79f444e94726a5befca90e38aaf5", "name": "Notch"})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := New(server.Client(), &oauth2.Config{
+		ClientID: "test-client",
+		Endpoint: oauth2.Endpoint{AuthURL: server.URL + "/authorize", TokenURL: server.URL + "/token"},
+	})
+	client.Endpoints = Endpoints{
+		DeviceCode:      server.URL + "/devicecode",
+		XBLAuthenticate: server.URL + "/xbl",
+		XSTSAuthorize:   server.URL + "/xsts",
+		MinecraftLogin:  server.URL + "/login_with_xbox",
+		Profile:         server.URL + "/profile",
+	}
+	return client, server
+}
+
+func TestDeviceLogin(t *testing.T) {
+	devicePollUnit = time.Millisecond
+	defer func() { devicePollUnit = time.Second }()
+
+	client, _ := fakeAPI(t, "authorization_pending", "slow_down")
+	ctx := context.Background()
+
+	code, err := client.RequestDeviceCode(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.UserCode != "ABCD-EFGH" || code.VerificationURI != "https://microsoft.com/link" {
+		t.Fatalf("unexpected device code %+v", code)
+	}
+
+	if err := client.PollDeviceToken(ctx, code); err != nil {
+		t.Fatal(err)
+	}
+	if client.Token.AccessToken != "ms-token" || client.Token.RefreshToken != "ms-refresh" {
+		t.Fatalf("unexpected token %+v", client.Token)
+	}
+
+	creds, err := client.GetMinecraftCredentials(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.GetAccessToken() != "mc-token" || creds.GetPlayerName() != "Notch" {
+		t.Fatalf("unexpected credentials %+v", creds)
+	}
+}
+
+func TestDeviceLogin_Errors(t *testing.T) {
+	devicePollUnit = time.Millisecond
+	defer func() { devicePollUnit = time.Second }()
+
+	client, _ := fakeAPI(t, "authorization_declined")
+	err := client.PollDeviceToken(context.Background(), &DeviceCode{DeviceCode: "device-123", Interval: 1})
+	if !errors.Is(err, ErrDeviceLoginDeclined) {
+		t.Fatalf("expected ErrDeviceLoginDeclined, got %v", err)
+	}
+
+	client, _ = fakeAPI(t, "authorization_pending", "authorization_pending", "authorization_pending")
+	err = client.PollDeviceToken(context.Background(), &DeviceCode{DeviceCode: "device-123", Interval: 2, ExpiresIn: 3})
+	if !errors.Is(err, ErrDeviceCodeExpired) {
+		t.Fatalf("expected ErrDeviceCodeExpired, got %v", err)
+	}
+}
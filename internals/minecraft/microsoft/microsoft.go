@@ -13,12 +13,31 @@ import (
 )
 
 const (
+	MS_DEVICE_CODE     = "https://login.microsoftonline.com/consumers/oauth2/v2.0/devicecode"
 	XBL_AUTHENTICATE   = "https://user.auth.xboxlive.com/user/authenticate"
 	XBL_XSTS_AUTHORIZE = "https://xsts.auth.xboxlive.com/xsts/authorize"
 	MC_API_XBOX_LOGIN  = "https://api.minecraftservices.com/authentication/login_with_xbox"
 	MC_API_PROFILE     = "https://api.minecraftservices.com/minecraft/profile"
 )
 
+// Endpoints are the URLs used to sign in. The oauth token URL is part of the oauth2 config
+type Endpoints struct {
+	DeviceCode      string
+	XBLAuthenticate string
+	XSTSAuthorize   string
+	MinecraftLogin  string
+	Profile         string
+}
+
+// DefaultEndpoints are the Microsoft, Xbox Live and Minecraft production APIs
+var DefaultEndpoints = Endpoints{
+	DeviceCode:      MS_DEVICE_CODE,
+	XBLAuthenticate: XBL_AUTHENTICATE,
+	XSTSAuthorize:   XBL_XSTS_AUTHORIZE,
+	MinecraftLogin:  MC_API_XBOX_LOGIN,
+	Profile:         MC_API_PROFILE,
+}
+
 type MicrosoftClient struct {
 	*http.Client
 	// xblClient is a separate client because we need to set the token
@@ -26,6 +45,8 @@ type MicrosoftClient struct {
 	xblClient *http.Client
 	Config    *oauth2.Config
 	Token     *oauth2.Token
+	// Endpoints can be changed to use other APIs (for testing). Defaults to `DefaultEndpoints`
+	Endpoints Endpoints
 }
 
 type Credentials struct {
@@ -66,6 +87,7 @@ func New(httpClient *http.Client, config *oauth2.Config) *MicrosoftClient {
 		xblClient: &lessSecureClient,
 		Config:    config,
 		Token:     nil,
+		Endpoints: DefaultEndpoints,
 	}
 }
 
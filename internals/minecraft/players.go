@@ -0,0 +1,176 @@
+package minecraft
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultProfileAPI is the Mojang API used to resolve player names to UUIDs
+const DefaultProfileAPI = "https://api.mojang.com/users/profiles/minecraft/"
+
+var (
+	// ErrProfileNotFound is returned if no player with the given name exists
+	ErrProfileNotFound = errors.New("player not found")
+	// ErrInvalidPlayerName is returned for names that can not be a Minecraft account
+	ErrInvalidPlayerName = errors.New("invalid player name (3-16 characters: letters, numbers and _)")
+)
+
+var playerNameRegex = regexp.MustCompile(`^[A-Za-z0-9_]{3,16}$`)
+
+// Player is a player as stored in the `whitelist.json`
+type Player struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+// Op is an operator as stored in the `ops.json`
+type Op struct {
+	UUID  string `json:"uuid"`
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+	// BypassesPlayerLimit allows this op to join when the server is full
+	BypassesPlayerLimit bool `json:"bypassesPlayerLimit"`
+}
+
+// OfflineUUID returns the UUID a server in offline mode assigns to a player.
+// It is a version 3 UUID of "OfflinePlayer:<name>"
+func OfflineUUID(name string) string {
+	sum := md5.Sum([]byte("OfflinePlayer:" + name))
+	sum[6] = sum[6]&0x0f | 0x30
+	sum[8] = sum[8]&0x3f | 0x80
+	return FormatUUID(hex.EncodeToString(sum[:]))
+}
+
+// FormatUUID adds dashes to a UUID without them (as returned by the Mojang API)
+func FormatUUID(id string) string {
+	id = strings.ToLower(strings.ReplaceAll(id, "-", ""))
+	if len(id) != 32 {
+		return id
+	}
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:]
+}
+
+// ValidPlayerName reports whether name can be the name of a Minecraft account
+func ValidPlayerName(name string) bool {
+	return playerNameRegex.MatchString(name)
+}
+
+// ProfileLookup resolves player names to UUIDs using the Mojang API or a compatible one
+type ProfileLookup struct {
+	// URL is the base URL of the API. The player name is appended to it.
+	// Defaults to `DefaultProfileAPI`
+	URL  string
+	HTTP *http.Client
+}
+
+// Lookup returns the player with the given name. Returns `ErrProfileNotFound` if no such player exists
+func (p *ProfileLookup) Lookup(ctx context.Context, name string) (*Player, error) {
+	if !ValidPlayerName(name) {
+		return nil, ErrInvalidPlayerName
+	}
+
+	base := p.URL
+	if base == "" {
+		base = DefaultProfileAPI
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	client := p.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent, http.StatusNotFound:
+		return nil, ErrProfileNotFound
+	default:
+		return nil, fmt.Errorf("profile API responded with unexpected status %s", res.Status)
+	}
+
+	profile := GetProfileResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&profile); err != nil {
+		return nil, err
+	}
+	if profile.ID == "" {
+		return nil, ErrProfileNotFound
+	}
+	return &Player{UUID: FormatUUID(profile.ID), Name: profile.Name}, nil
+}
+
+// ReadWhitelist reads a `whitelist.json`. A missing file is an empty whitelist
+func ReadWhitelist(path string) ([]Player, error) {
+	players := []Player{}
+	if err := readPlayerList(path, &players); err != nil {
+		return nil, err
+	}
+	return players, nil
+}
+
+// WriteWhitelist writes players to a `whitelist.json`
+func WriteWhitelist(path string, players []Player) error {
+	return writePlayerList(path, players)
+}
+
+// ReadOps reads an `ops.json`. A missing file means no ops
+func ReadOps(path string) ([]Op, error) {
+	ops := []Op{}
+	if err := readPlayerList(path, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// WriteOps writes ops to an `ops.json`
+func WriteOps(path string, ops []Op) error {
+	return writePlayerList(path, ops)
+}
+
+// ReadUserCache reads the players in a `usercache.json` (the players that joined the server recently)
+func ReadUserCache(path string) ([]Player, error) {
+	return ReadWhitelist(path)
+}
+
+func readPlayerList(path string, v interface{}) error {
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(raw)) == "" {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func writePlayerList(path string, v interface{}) error {
+	// minecraft writes these files with 2 space indentation
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, raw, 0644)
+}
@@ -0,0 +1,67 @@
+package minecraft
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOfflineUUID(t *testing.T) {
+	if got := OfflineUUID("Notch"); got != "b50ad385-829d-3141-a216-7e7d7539ba7f" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestProfileLookup_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/profiles/Notch":
+			w.Write([]byte(`{"id":"069a79f444e94726a5befca90e38aaf5","name":"Notch"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	lookup := &ProfileLookup{URL: server.URL + "/profiles", HTTP: server.Client()}
+	player, err := lookup.Lookup(context.Background(), "Notch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Player{UUID: "069a79f4-44e9-4726-a5be-fca90e38aaf5", Name: "Notch"}
+	if !reflect.DeepEqual(player, want) {
+		t.Fatalf("got %+v, want %+v", player, want)
+	}
+
+	if _, err := lookup.Lookup(context.Background(), "nobody"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected ErrProfileNotFound, got %v", err)
+	}
+	if _, err := lookup.Lookup(context.Background(), "../admin"); !errors.Is(err, ErrInvalidPlayerName) {
+		t.Fatalf("expected ErrInvalidPlayerName, got %v", err)
+	}
+}
+
+func TestWhitelist_ReadWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "whitelist.json")
+
+	players, err := ReadWhitelist(path)
+	if err != nil || len(players) != 0 {
+		t.Fatalf("missing file should be empty, got %v %v", players, err)
+	}
+
+	want := []Player{{UUID: OfflineUUID("Steve"), Name: "Steve"}}
+	if err := WriteWhitelist(path, want); err != nil {
+		t.Fatal(err)
+	}
+	players, err = ReadWhitelist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(players, want) {
+		t.Fatalf("got %v, want %v", players, want)
+	}
+}
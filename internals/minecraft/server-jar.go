@@ -0,0 +1,238 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoServerMainClass is returned if the main class of a server jar could not be determined
+var ErrNoServerMainClass = errors.New("could not find the main class of the server jar")
+
+// BundledFile is a jar that is contained in a bundled server jar (1.18+)
+type BundledFile struct {
+	// Sha256 is the hex encoded sha256 checksum of the file
+	Sha256 string
+	// ID is the maven like id of the file (eg. `com.google.guava:guava:31.0.1-jre`)
+	ID string
+	// Path is the path relative to the `META-INF/versions` or `META-INF/libraries` directory
+	Path string
+
+	entry string
+}
+
+// ServerJar is a dedicated server jar as downloaded from `Downloads.Server`
+type ServerJar struct {
+	// Path of the server jar
+	Path string
+	// MainClass is the class used to start the server
+	MainClass string
+	// Bundled is true if this jar is in the bundler format used since 1.18.
+	// The actual server and its libraries have to be extracted before launching
+	Bundled bool
+	// Versions are the bundled server jars (usually only one)
+	Versions []BundledFile
+	// Libraries are the bundled libraries needed to run the server
+	Libraries []BundledFile
+}
+
+// ReadServerJar reads the server jar at the given path
+func ReadServerJar(path string) (*ServerJar, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	server := &ServerJar{Path: path}
+	files := make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+
+	// not a bundler. the main class is in the jar manifest
+	if files["META-INF/versions.list"] == nil {
+		manifest, err := readZipFile(files["META-INF/MANIFEST.MF"])
+		if err != nil {
+			return nil, ErrNoServerMainClass
+		}
+		server.MainClass = manifestAttribute(manifest, "Main-Class")
+		if server.MainClass == "" {
+			return nil, ErrNoServerMainClass
+		}
+		return server, nil
+	}
+
+	server.Bundled = true
+	if server.Versions, err = readBundleList(files["META-INF/versions.list"], "META-INF/versions/"); err != nil {
+		return nil, err
+	}
+	if files["META-INF/libraries.list"] != nil {
+		if server.Libraries, err = readBundleList(files["META-INF/libraries.list"], "META-INF/libraries/"); err != nil {
+			return nil, err
+		}
+	}
+
+	mainClass, err := readZipFile(files["META-INF/main-class"])
+	if err != nil {
+		return nil, ErrNoServerMainClass
+	}
+	server.MainClass = strings.TrimSpace(mainClass)
+	if server.MainClass == "" {
+		return nil, ErrNoServerMainClass
+	}
+
+	return server, nil
+}
+
+// Classpath returns all jars needed to run the server.
+// Bundled files get extracted first: libraries to `libDir` and the actual server to `versionsDir`.
+// Files that already exist with the correct size are not extracted again
+func (s *ServerJar) Classpath(libDir string, versionsDir string) ([]string, error) {
+	if !s.Bundled {
+		return []string{s.Path}, nil
+	}
+
+	r, err := zip.OpenReader(s.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	files := make(map[string]*zip.File, len(r.File))
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+
+	classpath := make([]string, 0, len(s.Libraries)+len(s.Versions))
+	extract := func(bundled []BundledFile, dir string) error {
+		for _, b := range bundled {
+			f := files[b.entry]
+			if f == nil {
+				return fmt.Errorf("server jar is missing bundled file %s", b.entry)
+			}
+			if err := sanitizeExtractPath(b.Path, dir); err != nil {
+				return err
+			}
+			target := filepath.Join(dir, filepath.FromSlash(b.Path))
+			if err := extractBundled(f, target, b.Sha256); err != nil {
+				return err
+			}
+			classpath = append(classpath, target)
+		}
+		return nil
+	}
+
+	if err := extract(s.Libraries, libDir); err != nil {
+		return nil, err
+	}
+	if err := extract(s.Versions, versionsDir); err != nil {
+		return nil, err
+	}
+
+	return classpath, nil
+}
+
+// extractBundled extracts f to target if it does not exist yet and verifies its checksum
+func extractBundled(f *zip.File, target string, sha string) error {
+	if info, err := os.Stat(target); err == nil && uint64(info.Size()) == f.UncompressedSize64 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tmp := target + ".part"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, hash), rc)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && sha != "" && hex.EncodeToString(hash.Sum(nil)) != strings.ToLower(sha) {
+		err = fmt.Errorf("checksum mismatch for bundled file %s", f.Name)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, target)
+}
+
+// readBundleList parses a `versions.list` or `libraries.list` file.
+// Every line has the format `<sha256>\t<id>\t<path>`
+func readBundleList(f *zip.File, prefix string) ([]BundledFile, error) {
+	content, err := readZipFile(f)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]BundledFile, 0)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid line in %s: %q", f.Name, line)
+		}
+		list = append(list, BundledFile{
+			Sha256: parts[0],
+			ID:     parts[1],
+			Path:   parts[2],
+			entry:  prefix + parts[2],
+		})
+	}
+	return list, scanner.Err()
+}
+
+func readZipFile(f *zip.File) (string, error) {
+	if f == nil {
+		return "", os.ErrNotExist
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	content, err := ioutil.ReadAll(rc)
+	return string(content), err
+}
+
+// manifestAttribute returns the value of a main attribute in a jar manifest
+func manifestAttribute(manifest string, name string) string {
+	for _, line := range strings.Split(manifest, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, name+":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, name+":"))
+		}
+	}
+	return ""
+}
+
+// sanitizeExtractPath makes sure that filePath stays inside of destination
+func sanitizeExtractPath(filePath string, destination string) error {
+	destpath := filepath.Join(destination, filepath.FromSlash(filePath))
+	if !strings.HasPrefix(destpath, filepath.Clean(destination)+string(os.PathSeparator)) {
+		return fmt.Errorf("%s: illegal file path", filePath)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package minecraft
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeJar(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func sha(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestReadServerJar_Legacy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "1.16.5-server.jar")
+	writeJar(t, path, map[string]string{
+		"META-INF/MANIFEST.MF": "Manifest-Version: 1.0\r\nMain-Class: net.minecraft.server.Main\r\n",
+	})
+
+	server, err := ReadServerJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Bundled || server.MainClass != "net.minecraft.server.Main" {
+		t.Fatalf("unexpected server jar %+v", server)
+	}
+	cp, err := server.Classpath(t.TempDir(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cp, []string{path}) {
+		t.Errorf("unexpected classpath %v", cp)
+	}
+}
+
+func TestReadServerJar_Bundler(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "1.18.1-server.jar")
+	server := "server classes"
+	guava := "guava classes"
+	writeJar(t, path, map[string]string{
+		"META-INF/MANIFEST.MF":                       "Manifest-Version: 1.0\nMain-Class: net.minecraft.bundler.Main\n",
+		"META-INF/main-class":                        "net.minecraft.server.Main\n",
+		"META-INF/versions.list":                     sha(server) + "\t1.18.1\t1.18.1/server-1.18.1.jar\n",
+		"META-INF/libraries.list":                    sha(guava) + "\tcom.google.guava:guava:31.0.1-jre\tcom/google/guava/guava/31.0.1-jre/guava-31.0.1-jre.jar\n",
+		"META-INF/versions/1.18.1/server-1.18.1.jar": server,
+		"META-INF/libraries/com/google/guava/guava/31.0.1-jre/guava-31.0.1-jre.jar": guava,
+	})
+
+	jar, err := ReadServerJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jar.Bundled || jar.MainClass != "net.minecraft.server.Main" {
+		t.Fatalf("unexpected server jar %+v", jar)
+	}
+	if len(jar.Versions) != 1 || jar.Versions[0].ID != "1.18.1" || len(jar.Libraries) != 1 {
+		t.Fatalf("unexpected bundled files %+v %+v", jar.Versions, jar.Libraries)
+	}
+
+	libDir := filepath.Join(dir, "libraries")
+	versionsDir := filepath.Join(dir, "versions")
+	cp, err := jar.Classpath(libDir, versionsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(libDir, "com/google/guava/guava/31.0.1-jre/guava-31.0.1-jre.jar"),
+		filepath.Join(versionsDir, "1.18.1/server-1.18.1.jar"),
+	}
+	if !reflect.DeepEqual(cp, want) {
+		t.Fatalf("got classpath %v, want %v", cp, want)
+	}
+	content, err := os.ReadFile(want[1])
+	if err != nil || string(content) != server {
+		t.Fatalf("server was not extracted: %q %v", content, err)
+	}
+
+	// extracting a second time is a noop
+	if _, err := jar.Classpath(libDir, versionsDir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadServerJar_ChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.jar")
+	writeJar(t, path, map[string]string{
+		"META-INF/main-class":                    "net.minecraft.server.Main",
+		"META-INF/versions.list":                 sha("other") + "\t1.18\t1.18/server-1.18.jar",
+		"META-INF/versions/1.18/server-1.18.jar": "tampered",
+	})
+
+	jar, err := ReadServerJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jar.Classpath(dir, dir); err == nil {
+		t.Fatal("expected checksum error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "1.18/server-1.18.jar")); !os.IsNotExist(err) {
+		t.Error("tampered file should not be extracted")
+	}
+}
+
+func TestReadServerJar_PathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.jar")
+	writeJar(t, path, map[string]string{
+		"META-INF/main-class":              "net.minecraft.server.Main",
+		"META-INF/versions.list":           "\tevil\t../../evil.jar",
+		"META-INF/versions/../../evil.jar": "evil",
+	})
+
+	jar, err := ReadServerJar(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jar.Classpath(filepath.Join(dir, "libs"), filepath.Join(dir, "versions")); err == nil {
+		t.Fatal("expected illegal path error")
+	}
+}
@@ -1,49 +1,280 @@
 package minecraft
 
 import (
-	"bytes"
+	"fmt"
+	"net"
+	"strconv"
 	"strings"
 )
 
-// ServerProperties is a map of server props
-type ServerProperties map[string]string
+// ServerProperties is a `server.properties` file. It follows the rules of Java `.properties` files.
+// The order of the entries, comments & formatting of untouched lines are preserved
+type ServerProperties struct {
+	lines []*propLine
+	// newline is the line ending of the parsed file
+	newline string
+}
+
+// propLine is a logical line of a properties file. It might span multiple physical lines
+type propLine struct {
+	// raw is the original text of the line (without the trailing line break)
+	raw string
+	// key is empty for comments & blank lines
+	key   string
+	value string
+}
 
 // ParseServerProps returns a ServerProperties object that contains the parsed props
-func ParseServerProps(buf []byte) ServerProperties {
-	props := make(ServerProperties, 0)
+func ParseServerProps(buf []byte) *ServerProperties {
+	props := &ServerProperties{newline: "\n"}
+	content := string(buf)
+	if strings.Contains(content, "\r\n") {
+		props.newline = "\r\n"
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return props
+	}
+
+	physical := strings.Split(content, "\n")
+	for i := 0; i < len(physical); i++ {
+		raw := physical[i]
+		trimmed := strings.TrimLeft(raw, " \t\f")
+
+		// comments & blank lines can not be continued
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+			props.lines = append(props.lines, &propLine{raw: raw})
+			continue
+		}
+
+		// a line ending with an odd number of backslashes continues on the next line
+		logical := trimmed
+		for continues(logical) && i+1 < len(physical) {
+			i++
+			raw += "\n" + physical[i]
+			logical = logical[:len(logical)-1] + strings.TrimLeft(physical[i], " \t\f")
+		}
+		if continues(logical) {
+			logical = logical[:len(logical)-1]
+		}
+
+		key, value := splitProp(logical)
+		props.lines = append(props.lines, &propLine{raw: raw, key: key, value: value})
+	}
+	return props
+}
 
-	lines := bytes.Split(buf, []byte("\n"))
-	for _, bLine := range lines {
-		line := string(bLine)
+// continues returns true if line ends with an odd number of backslashes
+func continues(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
 
-		// skip comments
-		if strings.HasPrefix(line, "#") {
+// splitProp splits a logical line into the unescaped key & value
+func splitProp(line string) (string, string) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++
 			continue
 		}
-		// key = value
-		comp := strings.Split(line, "=")
-		key := comp[0]
-		value := ""
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			end = i
+			break
+		}
+	}
+
+	key := line[:end]
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	// one "=" or ":" (surrounded by whitespace) separates key & value
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return unescape(key), unescape(rest)
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
 
-		if key == "" {
+	out := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			out.WriteByte(c)
 			continue
 		}
+		i++
+		switch s[i] {
+		case 't':
+			out.WriteByte('\t')
+		case 'n':
+			out.WriteByte('\n')
+		case 'r':
+			out.WriteByte('\r')
+		case 'f':
+			out.WriteByte('\f')
+		case 'u':
+			if i+4 < len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					out.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			out.WriteByte('u')
+		default:
+			out.WriteByte(s[i])
+		}
+	}
+	return out.String()
+}
 
-		if len(comp) == 2 {
-			value = comp[1]
+// escape escapes s like Java does when storing properties
+func escape(s string, isKey bool) string {
+	out := strings.Builder{}
+	for i, r := range s {
+		switch r {
+		case ' ':
+			// spaces in values only need to be escaped at the start
+			if isKey || i == 0 {
+				out.WriteString(`\ `)
+			} else {
+				out.WriteRune(r)
+			}
+		case '\t':
+			out.WriteString(`\t`)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\r':
+			out.WriteString(`\r`)
+		case '\f':
+			out.WriteString(`\f`)
+		case '=', ':', '#', '!', '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				// non ascii chars are escaped, so the file can be read as latin-1 and utf-8
+				if r > 0xffff {
+					for _, c := range utf16Pair(r) {
+						fmt.Fprintf(&out, `\u%04x`, c)
+					}
+				} else {
+					fmt.Fprintf(&out, `\u%04x`, r)
+				}
+				continue
+			}
+			out.WriteRune(r)
 		}
-		props[key] = value
 	}
-	return props
+	return out.String()
+}
+
+func utf16Pair(r rune) []rune {
+	r -= 0x10000
+	return []rune{0xd800 + (r>>10)&0x3ff, 0xdc00 + r&0x3ff}
+}
+
+// Get returns the value of key. The last entry wins if a key is set multiple times
+func (s *ServerProperties) Get(key string) (string, bool) {
+	for i := len(s.lines) - 1; i >= 0; i-- {
+		if s.lines[i].key == key {
+			return s.lines[i].value, true
+		}
+	}
+	return "", false
+}
+
+// Value returns the value of key or an empty string if it is not set
+func (s *ServerProperties) Value(key string) string {
+	value, _ := s.Get(key)
+	return value
+}
+
+// Set sets key to value. Existing entries are updated in place, new ones are appended
+func (s *ServerProperties) Set(key string, value string) {
+	raw := escape(key, true) + "=" + escape(value, false)
+
+	found := false
+	for i := len(s.lines) - 1; i >= 0; i-- {
+		line := s.lines[i]
+		if line.key != key {
+			continue
+		}
+		if found {
+			// remove duplicates, they would be confusing
+			s.lines = append(s.lines[:i], s.lines[i+1:]...)
+			continue
+		}
+		found = true
+		if line.value != value {
+			line.value = value
+			line.raw = raw
+		}
+	}
+
+	if !found {
+		s.lines = append(s.lines, &propLine{raw: raw, key: key, value: value})
+	}
+}
+
+// Unset removes key. Returns false if the key was not set
+func (s *ServerProperties) Unset(key string) bool {
+	lines := s.lines[:0]
+	removed := false
+	for _, line := range s.lines {
+		if line.key == key {
+			removed = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+	s.lines = lines
+	return removed
+}
+
+// Keys returns all keys in the order they appear in the file
+func (s *ServerProperties) Keys() []string {
+	keys := make([]string, 0, len(s.lines))
+	seen := make(map[string]bool, len(s.lines))
+	for _, line := range s.lines {
+		if line.key != "" && !seen[line.key] {
+			seen[line.key] = true
+			keys = append(keys, line.key)
+		}
+	}
+	return keys
 }
 
 // String returns ServerProperties as a string (config file)
 func (s *ServerProperties) String() string {
 	config := strings.Builder{}
-	config.WriteString("# generated by minepkg\n")
-	for key, val := range *s {
-		config.WriteString(key + "=" + val + "\n")
+	for _, line := range s.lines {
+		raw := line.raw
+		if s.newline != "\n" {
+			raw = strings.ReplaceAll(raw, "\n", s.newline)
+		}
+		config.WriteString(raw + s.newline)
 	}
-
 	return config.String()
 }
+
+// RconAddress returns the address the rcon server listens on. Defaults to localhost
+func (s *ServerProperties) RconAddress() string {
+	host := s.Value("server-ip")
+	if host == "" || host == "0.0.0.0" {
+		host = "127.0.0.1"
+	}
+	port := s.Value("rcon.port")
+	if port == "" {
+		port = "25575"
+	}
+	return net.JoinHostPort(host, port)
+}
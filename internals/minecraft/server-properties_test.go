@@ -0,0 +1,106 @@
+package minecraft
+
+import (
+	"reflect"
+	"testing"
+)
+
+const vanillaProps = `#Minecraft server properties
+#Sat Jan 01 12:00:00 CET 2022
+enable-jmx-monitoring=false
+rcon.port=25575
+motd=A Minecraft Serveré \= fun
+level-name=world
+
+gamemode=survival
+`
+
+func TestParseServerProps_RoundTrip(t *testing.T) {
+	props := ParseServerProps([]byte(vanillaProps))
+	if got := props.String(); got != vanillaProps {
+		t.Fatalf("untouched file changed:\n%s", got)
+	}
+
+	want := []string{"enable-jmx-monitoring", "rcon.port", "motd", "level-name", "gamemode"}
+	if got := props.Keys(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got keys %v, want %v", got, want)
+	}
+}
+
+func TestParseServerProps_Values(t *testing.T) {
+	tests := []struct {
+		line  string
+		key   string
+		value string
+	}{
+		{line: "motd=a=b", key: "motd", value: "a=b"},
+		{line: "motd = spaced ", key: "motd", value: "spaced "},
+		{line: "motd:colon", key: "motd", value: "colon"},
+		{line: "motd value", key: "motd", value: "value"},
+		{line: `motd=é\t\\`, key: "motd", value: "é\t\\"},
+		{line: `my\ key=value`, key: "my key", value: "value"},
+		{line: `my\=key=value`, key: "my=key", value: "value"},
+		{line: "motd=first \\\n    second", key: "motd", value: "first second"},
+		{line: "empty=", key: "empty", value: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			props := ParseServerProps([]byte(tt.line + "\n"))
+			value, ok := props.Get(tt.key)
+			if !ok {
+				t.Fatalf("key %q not found in %v", tt.key, props.Keys())
+			}
+			if value != tt.value {
+				t.Fatalf("got %q, want %q", value, tt.value)
+			}
+		})
+	}
+}
+
+func TestServerProperties_Set(t *testing.T) {
+	props := ParseServerProps([]byte(vanillaProps))
+	props.Set("gamemode", "creative")
+	props.Set("motd", " Welcome = have fun ö")
+	props.Set("new-key", "value")
+	props.Unset("enable-jmx-monitoring")
+
+	want := `#Minecraft server properties
+#Sat Jan 01 12:00:00 CET 2022
+rcon.port=25575
+motd=\ Welcome \= have fun \u00f6
+level-name=world
+
+gamemode=creative
+new-key=value
+`
+	if got := props.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// everything written can be read back
+	reparsed := ParseServerProps([]byte(props.String()))
+	if got := reparsed.Value("motd"); got != " Welcome = have fun ö" {
+		t.Fatalf("got motd %q", got)
+	}
+}
+
+func TestServerProperties_SetDuplicates(t *testing.T) {
+	props := ParseServerProps([]byte("pvp=true\r\npvp=false\r\n"))
+	if got := props.Value("pvp"); got != "false" {
+		t.Fatalf("last entry should win, got %q", got)
+	}
+	props.Set("pvp", "true")
+	if got := props.String(); got != "pvp=true\r\n" {
+		t.Fatalf("got %q", got)
+	}
+	if props.Unset("pvp") != true || props.Unset("pvp") != false {
+		t.Fatal("unexpected Unset result")
+	}
+}
+
+func TestServerProperties_RconAddress(t *testing.T) {
+	props := ParseServerProps([]byte("server-ip=\nrcon.port=1234\n"))
+	if got := props.RconAddress(); got != "127.0.0.1:1234" {
+		t.Fatalf("got %s", got)
+	}
+}
@@ -15,6 +15,8 @@ const DefaultApiURL = "https://api.modrinth.com/"
 var (
 	ErrInvalidProjectIDOrSlug = errors.New("invalid project ID or slug")
 	ErrInvalidVersionID       = errors.New("invalid version ID")
+	// ErrNotFound is returned when the requested project, version or file does not exist
+	ErrNotFound = errors.New("not found on modrinth")
 )
 
 type Client struct {
@@ -57,6 +59,10 @@ func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
 
 // decode is a helper that decodes json, and checks the status code
 func (c *Client) decode(res *http.Response, v interface{}) error {
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
 	if res.StatusCode != 200 {
 		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
 	}
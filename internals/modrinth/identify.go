@@ -0,0 +1,33 @@
+package modrinth
+
+import "context"
+
+// FileLookup finds versions by file hash and projects. `*Client` implements it
+type FileLookup interface {
+	GetVersionFile(ctx context.Context, hash string) (*Version, error)
+	GetProject(ctx context.Context, idOrSlug string) (*Project, error)
+}
+
+// Identify returns the project and version of the file with the given sha1 or sha512 hash.
+// Returns `ErrNotFound` if Modrinth does not know the file
+func Identify(ctx context.Context, lookup FileLookup, hash string) (*Project, *Version, error) {
+	version, err := lookup.GetVersionFile(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	project, err := lookup.GetProject(ctx, version.ProjectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return project, version, nil
+}
+
+// Source returns the `modrinth:` source of the version as used in the minepkg.toml
+func Source(project *Project, version *Version) string {
+	return VersionSource(project.Slug, version.ID)
+}
+
+// VersionSource returns the `modrinth:` source of a version of the project with the given id or slug
+func VersionSource(idOrSlug string, versionID string) string {
+	return "modrinth:" + idOrSlug + "@" + versionID
+}
@@ -0,0 +1,22 @@
+package modrinth
+
+import "context"
+
+// GetProject returns a single project given its id or slug
+func (c *Client) GetProject(ctx context.Context, idOrSlug string) (*Project, error) {
+	if idOrSlug == "" {
+		return nil, ErrInvalidProjectIDOrSlug
+	}
+
+	res, err := c.get(ctx, c.url("v2/project", idOrSlug).String())
+	if err != nil {
+		return nil, err
+	}
+
+	var result Project
+	if err = c.decode(res, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -37,3 +37,11 @@ type Version struct {
 	GameVersions  []string     `json:"game_versions"`
 	Loaders       []string     `json:"loaders"`
 }
+
+type Project struct {
+	ID          string `json:"id"`
+	Slug        string `json:"slug"`
+	ProjectType string `json:"project_type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,130 @@
+package mrpack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/minepkg/minepkg/internals/modrinth"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// ErrUnsupportedLoader is returned for packs that need a loader minepkg can not launch (like Quilt)
+var ErrUnsupportedLoader = errors.New("unsupported mod loader")
+
+// Import is the result of converting an index to a minepkg manifest
+type Import struct {
+	Manifest *manifest.Manifest
+	// Files maps the names of the dependencies to their file in the pack
+	Files map[string]File
+	// Modrinth lists the dependencies that were identified on Modrinth
+	Modrinth []string
+	// HTTPS lists the dependencies that are downloaded from their url
+	HTTPS []string
+	// Other are files that are no mods (like resource packs). They belong in the overwrites directory
+	Other []File
+	// Skipped are mods without a https download that could not be identified
+	Skipped []File
+}
+
+// Import converts the index to a manifest of a modpack. Mods that Modrinth knows become
+// `modrinth:` dependencies, all other mods are `https:` dependencies
+func (x *Index) Import(ctx context.Context, lookup modrinth.FileLookup) (*Import, error) {
+	man := manifest.New()
+	man.Package.Type = manifest.TypeModpack
+	man.Package.Name = manifest.PackageName(x.Name)
+	man.Package.Description = x.Summary
+	if _, err := semver.StrictNewVersion(x.VersionID); err == nil {
+		man.Package.Version = x.VersionID
+	}
+
+	for dependency, version := range x.Dependencies {
+		switch dependency {
+		case DependencyMinecraft:
+			man.Requirements.Minecraft = version
+		case DependencyFabricLoader:
+			man.Package.Platform = manifest.PlatformFabric
+			man.Requirements.FabricLoader = version
+		case DependencyForge:
+			man.Package.Platform = manifest.PlatformForge
+			man.Requirements.ForgeLoader = version
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedLoader, dependency)
+		}
+	}
+	if man.Requirements.Minecraft == "" {
+		return nil, fmt.Errorf("%w: the minecraft dependency is missing", ErrInvalidPack)
+	}
+	if man.Package.Platform == "" {
+		man.Package.Platform = manifest.PlatformVanilla
+	}
+
+	result := &Import{Manifest: man, Files: map[string]File{}}
+	for _, file := range x.Files {
+		if path.Dir(file.Path) != "mods" || path.Ext(file.Path) != ".jar" {
+			result.Other = append(result.Other, file)
+			continue
+		}
+		if file.Env != nil && file.Env.Client == EnvUnsupported {
+			// server only mods are skipped, minepkg packs are played on clients as well
+			result.Skipped = append(result.Skipped, file)
+			continue
+		}
+
+		name, source, err := identify(ctx, lookup, &file)
+		if err != nil {
+			return nil, err
+		}
+		identified := name != ""
+		if !identified {
+			var ok bool
+			if name, source, ok = httpsSource(&file); !ok {
+				result.Skipped = append(result.Skipped, file)
+				continue
+			}
+		}
+
+		if _, exists := man.Dependencies[name]; exists && len(file.Hashes.Sha1) >= 8 {
+			name = name + "-" + file.Hashes.Sha1[:8]
+		}
+		man.AddDependency(name, source)
+		result.Files[name] = file
+		if identified {
+			result.Modrinth = append(result.Modrinth, name)
+		} else {
+			result.HTTPS = append(result.HTTPS, name)
+		}
+	}
+
+	return result, nil
+}
+
+// identify looks up the file on Modrinth. Returns an empty name if Modrinth does not know it
+func identify(ctx context.Context, lookup modrinth.FileLookup, file *File) (string, string, error) {
+	hash := file.Hashes.Sha512
+	if hash == "" {
+		hash = file.Hashes.Sha1
+	}
+	project, version, err := modrinth.Identify(ctx, lookup, hash)
+	switch {
+	case errors.Is(err, modrinth.ErrNotFound) || errors.Is(err, modrinth.ErrInvalidFileHash):
+		return "", "", nil
+	case err != nil:
+		return "", "", fmt.Errorf("could not look up %s on modrinth: %w", file.Path, err)
+	}
+	return manifest.PackageName(project.Slug), modrinth.Source(project, version), nil
+}
+
+// httpsSource returns the name and the first https download of the file
+func httpsSource(file *File) (string, string, bool) {
+	for _, download := range file.Downloads {
+		if strings.HasPrefix(download, "https://") {
+			name := manifest.PackageName(strings.TrimSuffix(path.Base(file.Path), ".jar"))
+			return name, download, true
+		}
+	}
+	return "", "", false
+}
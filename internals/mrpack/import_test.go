@@ -0,0 +1,115 @@
+package mrpack
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/modrinth"
+)
+
+type fakeLookup struct {
+	versions map[string]*modrinth.Version
+}
+
+func (f *fakeLookup) GetVersionFile(ctx context.Context, hash string) (*modrinth.Version, error) {
+	if version, ok := f.versions[hash]; ok {
+		return version, nil
+	}
+	return nil, modrinth.ErrNotFound
+}
+
+func (f *fakeLookup) GetProject(ctx context.Context, idOrSlug string) (*modrinth.Project, error) {
+	return &modrinth.Project{ID: idOrSlug, Slug: "slug-of-" + idOrSlug}, nil
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	clientMod := filepath.Join(dir, "client.jar")
+	writeJar(t, clientMod, `{"id":"client","environment":"client"}`)
+	otherMod := filepath.Join(dir, "other.jar")
+	writeJar(t, otherMod, `{"id":"other"}`)
+	privateMod := filepath.Join(dir, "private.jar")
+	writeJar(t, privateMod, `{"id":"private"}`)
+	overrides := filepath.Join(dir, "overwrites")
+	os.MkdirAll(filepath.Join(overrides, "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(overrides, "config", "test.json"), []byte("{}"), 0644)
+
+	buf := &bytes.Buffer{}
+	index, err := Write(buf, &Pack{
+		Name:         "My Test Pack",
+		Version:      "1.0.0",
+		Dependencies: map[string]string{DependencyMinecraft: "1.18.2", DependencyFabricLoader: "0.13.3"},
+		Mods: []Mod{
+			{Path: clientMod, Filename: "client-1.0.0.jar", URL: "https://cdn.modrinth.com/data/abc/client.jar"},
+			{Path: otherMod, Filename: "Other_Mod-2.0.jar", URL: "https://github.com/example/other/releases/download/2.0/other.jar"},
+			{Path: privateMod, Filename: "private-1.0.0.jar"},
+		},
+		Overrides: overrides,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	packFile := filepath.Join(dir, "test.mrpack")
+	if err := ioutil.WriteFile(packFile, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := Open(packFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	lookup := &fakeLookup{versions: map[string]*modrinth.Version{
+		index.Files[0].Hashes.Sha512: {ID: "AABBCCDD", ProjectID: "P1"},
+	}}
+	imported, err := archive.Index.Import(context.Background(), lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	man := imported.Manifest
+	if man.Package.Name != "my-test-pack" || man.Package.Version != "1.0.0" || man.Package.Type != "modpack" {
+		t.Fatalf("unexpected package %+v", man.Package)
+	}
+	if man.Requirements.Minecraft != "1.18.2" || man.Requirements.FabricLoader != "0.13.3" {
+		t.Fatalf("unexpected requirements %+v", man.Requirements)
+	}
+	if man.Dependencies["slug-of-p1"] != "modrinth:slug-of-P1@AABBCCDD" {
+		t.Fatalf("expected a modrinth dependency, got %v", man.Dependencies)
+	}
+	if man.Dependencies["other_mod-2-0"] != "https://github.com/example/other/releases/download/2.0/other.jar" {
+		t.Fatalf("expected a https dependency, got %v", man.Dependencies)
+	}
+	if len(man.Dependencies) != 2 {
+		t.Fatalf("unexpected dependencies %v", man.Dependencies)
+	}
+
+	target := t.TempDir()
+	if err := archive.ExtractOverrides(target); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"config/test.json", "mods/private-1.0.0.jar"} {
+		if _, err := os.Stat(filepath.Join(target, expected)); err != nil {
+			t.Fatalf("expected %s to be extracted", expected)
+		}
+	}
+}
+
+func TestIndex_ImportQuilt(t *testing.T) {
+	index := &Index{Dependencies: map[string]string{DependencyMinecraft: "1.18.2", DependencyQuiltLoader: "0.17.0"}}
+	if _, err := index.Import(context.Background(), &fakeLookup{}); err == nil {
+		t.Fatal("expected quilt packs to be rejected")
+	}
+}
+
+func TestFile_Target(t *testing.T) {
+	file := &File{Path: "../../.bashrc"}
+	if _, err := file.Target(t.TempDir()); err == nil {
+		t.Fatal("expected path outside of the directory to be rejected")
+	}
+}
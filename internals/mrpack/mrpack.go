@@ -0,0 +1,61 @@
+// Package mrpack reads and writes Modrinth modpacks (.mrpack files)
+package mrpack
+
+// IndexFile is the name of the index in the pack
+const IndexFile = "modrinth.index.json"
+
+// OverridesDir is the directory in the pack whose content is copied into the Minecraft directory
+const OverridesDir = "overrides"
+
+// ClientOverridesDir contains overrides that are only applied to clients
+const ClientOverridesDir = "client-overrides"
+
+// Values of `Env.Client` and `Env.Server`
+const (
+	EnvRequired    = "required"
+	EnvOptional    = "optional"
+	EnvUnsupported = "unsupported"
+)
+
+// Keys of `Index.Dependencies`
+const (
+	DependencyMinecraft    = "minecraft"
+	DependencyFabricLoader = "fabric-loader"
+	DependencyForge        = "forge"
+	DependencyQuiltLoader  = "quilt-loader"
+)
+
+// Index is the `modrinth.index.json` of a pack
+type Index struct {
+	FormatVersion int    `json:"formatVersion"`
+	Game          string `json:"game"`
+	VersionID     string `json:"versionId"`
+	Name          string `json:"name"`
+	Summary       string `json:"summary,omitempty"`
+	Files         []File `json:"files"`
+	// Dependencies maps "minecraft", "fabric-loader" etc. to the version
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// File is a file the launcher downloads into the Minecraft directory
+type File struct {
+	// Path is relative to the Minecraft directory (like "mods/example.jar")
+	Path   string `json:"path"`
+	Hashes Hashes `json:"hashes"`
+	// Env is nil if the file is required on both sides
+	Env       *Env     `json:"env,omitempty"`
+	Downloads []string `json:"downloads"`
+	FileSize  int64    `json:"fileSize"`
+}
+
+// Hashes are the hex encoded hashes of a file
+type Hashes struct {
+	Sha1   string `json:"sha1"`
+	Sha512 string `json:"sha512"`
+}
+
+// Env describes on which side a file is needed
+type Env struct {
+	Client string `json:"client"`
+	Server string `json:"server"`
+}
@@ -0,0 +1,159 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/pack"
+)
+
+var (
+	// ErrInvalidPack is returned if the file is not a zip with a valid `modrinth.index.json`
+	ErrInvalidPack = errors.New("not a valid .mrpack file")
+	// ErrHashMismatch is returned if a downloaded file does not match the hash in the index
+	ErrHashMismatch = errors.New("downloaded file does not match its hash")
+)
+
+// Archive is an opened .mrpack file
+type Archive struct {
+	Index *Index
+
+	file   *os.File
+	reader *pack.Reader
+}
+
+// Open opens the .mrpack file and reads its index
+func Open(p string) (*Archive, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	index, err := readIndex(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &Archive{
+		Index:  index,
+		file:   f,
+		reader: pack.NewReader(f, info.Size()),
+	}, nil
+}
+
+func readIndex(r io.ReaderAt, size int64) (*Index, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidPack
+	}
+
+	for _, f := range archive.File {
+		if f.Name != IndexFile {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		index := &Index{}
+		if err := json.NewDecoder(rc).Decode(index); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidPack, err)
+		}
+		if index.Game != "minecraft" || index.FormatVersion != 1 {
+			return nil, fmt.Errorf("%w: unsupported game %q or format version %d", ErrInvalidPack, index.Game, index.FormatVersion)
+		}
+		return index, nil
+	}
+	return nil, fmt.Errorf("%w: %s is missing", ErrInvalidPack, IndexFile)
+}
+
+// ExtractOverrides extracts the overrides (and client overrides) of the pack to dest
+func (a *Archive) ExtractOverrides(dest string) error {
+	if err := a.reader.ExtractDir(OverridesDir, dest); err != nil {
+		return err
+	}
+	return a.reader.ExtractDir(ClientOverridesDir, dest)
+}
+
+// Close closes the underlying file
+func (a *Archive) Close() error {
+	return a.file.Close()
+}
+
+// Download downloads the file to dest and checks its hash
+func (f *File) Download(ctx context.Context, client *http.Client, dest string) error {
+	if len(f.Downloads) == 0 {
+		return fmt.Errorf("%s has no download url", f.Path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", f.Downloads[0], nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not download %s: unexpected status code %d", f.Path, res.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return err
+	}
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var h hash.Hash
+	var expected string
+	switch {
+	case f.Hashes.Sha512 != "":
+		h, expected = sha512.New(), f.Hashes.Sha512
+	default:
+		h, expected = sha1.New(), f.Hashes.Sha1
+	}
+	if _, err := io.Copy(io.MultiWriter(out, h), res.Body); err != nil {
+		return err
+	}
+	if expected != "" && hex.EncodeToString(h.Sum(nil)) != expected {
+		out.Close()
+		os.Remove(dest)
+		return fmt.Errorf("%s: %w", f.Path, ErrHashMismatch)
+	}
+	return nil
+}
+
+// Target returns the location of the file in the Minecraft directory dir.
+// Returns an error if the path of the file points outside of dir
+func (f *File) Target(dir string) (string, error) {
+	target := filepath.Join(dir, filepath.FromSlash(f.Path))
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s: illegal file path", f.Path)
+	}
+	return target, nil
+}
@@ -0,0 +1,173 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path"
+
+	"github.com/minepkg/minepkg/internals/pack"
+)
+
+// Pack describes a modpack to write
+type Pack struct {
+	Name    string
+	Version string
+	Summary string
+	// Dependencies maps "minecraft", "fabric-loader" etc. to the version
+	Dependencies map[string]string
+	Mods         []Mod
+	// Overrides is a directory that is copied into the Minecraft directory. Can be empty
+	Overrides string
+}
+
+// Mod is a mod jar of the pack
+type Mod struct {
+	// Path is the location of the jar on disk
+	Path string
+	// Filename is the name of the file in the mods directory
+	Filename string
+	// URL is the download URL. Mods without one (or with one Modrinth does not accept) are embedded in the pack
+	URL string
+}
+
+// Write writes the pack as .mrpack to w. Returns the written index
+func Write(w io.Writer, p *Pack) (*Index, error) {
+	index := &Index{
+		FormatVersion: 1,
+		Game:          "minecraft",
+		VersionID:     p.Version,
+		Name:          p.Name,
+		Summary:       p.Summary,
+		Files:         []File{},
+		Dependencies:  p.Dependencies,
+	}
+
+	archive := zip.NewWriter(w)
+
+	for _, mod := range p.Mods {
+		target := "mods/" + mod.Filename
+		if !PublicURL(mod.URL) {
+			if err := pack.AddZipFile(archive, mod.Path, OverridesDir+"/"+target); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		file, err := describeFile(mod.Path)
+		if err != nil {
+			return nil, err
+		}
+		file.Path = target
+		file.Downloads = []string{mod.URL}
+		file.Env = ModEnv(mod.Path)
+		index.Files = append(index.Files, *file)
+	}
+
+	err := pack.WalkOverrides(p.Overrides, func(src string, name string) error {
+		return pack.AddZipFile(archive, src, path.Join(OverridesDir, name))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	indexWriter, err := archive.Create(IndexFile)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(indexWriter)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(index); err != nil {
+		return nil, err
+	}
+
+	return index, archive.Close()
+}
+
+// allowedHosts are the download hosts Modrinth accepts in packs
+var allowedHosts = []string{
+	"cdn.modrinth.com",
+	"github.com",
+	"raw.githubusercontent.com",
+	"gitlab.com",
+}
+
+// PublicURL reports whether u can be used as download in a pack. Modrinth only accepts a few hosts,
+// files from anywhere else have to be embedded
+func PublicURL(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Scheme != "https" {
+		return false
+	}
+	for _, host := range allowedHosts {
+		if parsed.Hostname() == host {
+			return true
+		}
+	}
+	return false
+}
+
+// describeFile returns the hashes and size of the file
+func describeFile(p string) (*File, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sha1Hash := sha1.New()
+	sha512Hash := sha512.New()
+	size, err := io.Copy(io.MultiWriter(sha1Hash, sha512Hash), f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Hashes: Hashes{
+			Sha1:   hex.EncodeToString(sha1Hash.Sum(nil)),
+			Sha512: hex.EncodeToString(sha512Hash.Sum(nil)),
+		},
+		FileSize: size,
+	}, nil
+}
+
+// ModEnv returns the sides a mod jar supports according to its `fabric.mod.json`.
+// Returns nil if the mod is needed on both sides or the side is unknown
+func ModEnv(jar string) *Env {
+	r, err := zip.OpenReader(jar)
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != "fabric.mod.json" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil
+		}
+		defer rc.Close()
+
+		meta := struct {
+			Environment string `json:"environment"`
+		}{}
+		if err := json.NewDecoder(rc).Decode(&meta); err != nil {
+			return nil
+		}
+		switch meta.Environment {
+		case "client":
+			return &Env{Client: EnvRequired, Server: EnvUnsupported}
+		case "server":
+			return &Env{Client: EnvUnsupported, Server: EnvRequired}
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeJar(t *testing.T, path string, fabricMod string) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	archive := zip.NewWriter(buf)
+	w, err := archive.Create("fabric.mod.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(fabricMod))
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	clientMod := filepath.Join(dir, "client.jar")
+	writeJar(t, clientMod, `{"id":"client","environment":"client"}`)
+	privateMod := filepath.Join(dir, "private.jar")
+	writeJar(t, privateMod, `{"id":"private","environment":"*"}`)
+
+	overrides := filepath.Join(dir, "overwrites")
+	os.MkdirAll(filepath.Join(overrides, "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(overrides, "config", "test.json"), []byte("{}"), 0644)
+
+	buf := &bytes.Buffer{}
+	index, err := Write(buf, &Pack{
+		Name:         "test-pack",
+		Version:      "1.0.0",
+		Dependencies: map[string]string{DependencyMinecraft: "1.18.2", DependencyFabricLoader: "0.13.3"},
+		Mods: []Mod{
+			{Path: clientMod, Filename: "client-1.0.0.jar", URL: "https://cdn.modrinth.com/data/abc/client.jar"},
+			{Path: privateMod, Filename: "private-1.0.0.jar", URL: "http://localhost:8080/private.jar"},
+			// modrinth does not accept downloads from other hosts
+			{Path: privateMod, Filename: "storage-1.0.0.jar", URL: "https://storage.minepkg.io/storage.jar"},
+		},
+		Overrides: overrides,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(index.Files) != 1 {
+		t.Fatalf("expected 1 file in the index, got %d", len(index.Files))
+	}
+	file := index.Files[0]
+	if file.Path != "mods/client-1.0.0.jar" || len(file.Hashes.Sha1) != 40 || len(file.Hashes.Sha512) != 128 {
+		t.Fatalf("unexpected file %+v", file)
+	}
+	if file.Env == nil || file.Env.Client != EnvRequired || file.Env.Server != EnvUnsupported {
+		t.Fatalf("unexpected env %+v", file.Env)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]*zip.File{}
+	for _, f := range archive.File {
+		names[f.Name] = f
+	}
+	for _, expected := range []string{IndexFile, "overrides/mods/private-1.0.0.jar", "overrides/mods/storage-1.0.0.jar", "overrides/config/test.json"} {
+		if names[expected] == nil {
+			t.Fatalf("expected %s in the pack", expected)
+		}
+	}
+
+	rc, err := names[IndexFile].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	written := &Index{}
+	if err := json.NewDecoder(rc).Decode(written); err != nil {
+		t.Fatal(err)
+	}
+	if written.FormatVersion != 1 || written.Dependencies[DependencyFabricLoader] != "0.13.3" {
+		t.Fatalf("unexpected index %+v", written)
+	}
+}
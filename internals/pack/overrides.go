@@ -0,0 +1,49 @@
+package pack
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WalkOverrides calls fn for every file in the overrides directory dir (like the "overwrites" directory of an instance).
+// name is the slash separated path of the file relative to dir. A missing or empty dir has no files
+func WalkOverrides(dir string, fn func(src string, name string) error) error {
+	if dir == "" {
+		return nil
+	}
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		return fn(p, filepath.ToSlash(rel))
+	})
+}
+
+// AddZipFile copies the file src into the archive as name
+func AddZipFile(archive *zip.Writer, src string, name string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	return err
+}
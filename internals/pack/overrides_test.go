@@ -0,0 +1,30 @@
+package pack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWalkOverrides(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "config", "empty"), os.ModePerm)
+	os.WriteFile(filepath.Join(dir, "config", "test.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(dir, "options.txt"), []byte(""), 0644)
+
+	names := []string{}
+	err := WalkOverrides(dir, func(src string, name string) error {
+		names = append(names, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "config/test.json" || names[1] != "options.txt" {
+		t.Fatalf("unexpected files %v", names)
+	}
+
+	if err := WalkOverrides(filepath.Join(dir, "missing"), func(string, string) error { return nil }); err != nil {
+		t.Fatalf("a missing directory should have no files, got %v", err)
+	}
+}
@@ -50,21 +50,36 @@ func (p *Reader) Files() []*zip.File {
 // ExtractModpack will extract everything in this zipfile to `dest` but will
 // NOT overwrite existing savefiles
 func (p *Reader) ExtractModpack(dest string) error {
+	return p.ExtractDir("", dest)
+}
+
+// ExtractDir extracts the content of the directory `dir` in this zipfile (like "overrides") to `dest`.
+// Existing savefiles are NOT overwritten. An empty `dir` extracts everything
+func (p *Reader) ExtractDir(dir string, dest string) error {
 	zipReader := p.zipReader
 
+	prefix := ""
+	if dir != "" {
+		prefix = strings.TrimSuffix(dir, "/") + "/"
+	}
+
 	skipPrefixes := []string{}
 	createdDirs := make(map[string]interface{})
 
 outer:
 	for _, f := range zipReader.File {
+		if !strings.HasPrefix(f.Name, prefix) || f.Name == prefix {
+			continue
+		}
+		name := strings.TrimPrefix(f.Name, prefix)
 
 		// make sure zip only contains valid paths
-		if err := sanitizeExtractPath(f.Name, dest); err != nil {
+		if err := sanitizeExtractPath(name, dest); err != nil {
 			return err
 		}
 
 		// get a relative path – used for name matching and stuff
-		relative, err := filepath.Rel(dest, filepath.Join(dest, f.Name))
+		relative, err := filepath.Rel(dest, filepath.Join(dest, name))
 		if err != nil {
 			return err
 		}
@@ -115,7 +130,7 @@ outer:
 		}
 		defer rc.Close()
 
-		target, err := os.Create(filepath.Join(dest, f.Name))
+		target, err := os.Create(filepath.Join(dest, name))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,127 @@
+package packwiz
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/minepkg/minepkg/internals/curseforge"
+	"github.com/minepkg/minepkg/internals/modrinth"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// Import is the result of converting a pack to a minepkg manifest
+type Import struct {
+	Manifest *manifest.Manifest
+	// Metafiles maps the names of the dependencies to their metafile
+	Metafiles map[string]*Metafile
+	// Files are files of the pack (like configs) that belong in the overwrites directory
+	Files []IndexFile
+	// Downloads are metafiles that are no mods (like resource packs). They are downloaded into the overwrites directory
+	Downloads []OtherFile
+	// Unresolved lists the metafiles that could not be added
+	Unresolved []Unresolved
+}
+
+// OtherFile is a metafile that is not a dependency
+type OtherFile struct {
+	*Metafile
+	// Dir is the directory of the file in the Minecraft directory (like "resourcepacks")
+	Dir string
+}
+
+// Unresolved is a metafile that could not be added as dependency
+type Unresolved struct {
+	File   string
+	Reason string
+}
+
+// Import converts the pack to a manifest of a modpack. Metafiles with Modrinth or CurseForge update
+// metadata become `modrinth:` or `curseforge:` dependencies, all other metafiles `https:` dependencies
+func (m *Modpack) Import(ctx context.Context) (*Import, error) {
+	man := manifest.New()
+	man.Package.Type = manifest.TypeModpack
+	man.Package.Name = manifest.PackageName(m.Pack.Name)
+	man.Package.Author = m.Pack.Author
+	if _, err := semver.StrictNewVersion(m.Pack.Version); err == nil {
+		man.Package.Version = m.Pack.Version
+	}
+
+	man.Package.Platform = manifest.PlatformVanilla
+	for key, version := range m.Pack.Versions {
+		switch key {
+		case VersionMinecraft:
+			man.Requirements.Minecraft = version
+		case VersionFabric:
+			man.Package.Platform = manifest.PlatformFabric
+			man.Requirements.FabricLoader = version
+		case VersionForge:
+			man.Package.Platform = manifest.PlatformForge
+			man.Requirements.ForgeLoader = version
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedLoader, key)
+		}
+	}
+	if man.Requirements.Minecraft == "" {
+		return nil, fmt.Errorf("%s does not contain a minecraft version", PackFile)
+	}
+
+	result := &Import{Manifest: man, Metafiles: map[string]*Metafile{}}
+	for i := range m.Index.Files {
+		file := m.Index.Files[i]
+		if !file.Metafile && !strings.HasSuffix(file.File, MetafileExt) {
+			result.Files = append(result.Files, file)
+			continue
+		}
+
+		meta, err := m.Metafile(ctx, &file)
+		if err != nil {
+			return nil, err
+		}
+		dir := path.Dir(file.File)
+		if dir != "mods" || path.Ext(meta.Filename) != ".jar" {
+			if meta.Download.URL == "" {
+				result.Unresolved = append(result.Unresolved, Unresolved{file.File, "no download url"})
+				continue
+			}
+			result.Downloads = append(result.Downloads, OtherFile{meta, dir})
+			continue
+		}
+		if meta.Side == SideServer {
+			// server only mods are skipped, minepkg packs are played on clients as well
+			result.Unresolved = append(result.Unresolved, Unresolved{file.File, "server only mod"})
+			continue
+		}
+
+		source, ok := Source(meta)
+		if !ok {
+			result.Unresolved = append(result.Unresolved, Unresolved{file.File, "no https download and no Modrinth or CurseForge metadata"})
+			continue
+		}
+		name := manifest.PackageName(strings.TrimSuffix(path.Base(file.File), MetafileExt))
+		if _, exists := man.Dependencies[name]; exists || name == "" {
+			name = manifest.PackageName(name + "-" + meta.Filename)
+		}
+		man.AddDependency(name, source)
+		result.Metafiles[name] = meta
+	}
+
+	return result, nil
+}
+
+// Source returns the minepkg.toml source of the metafile
+func Source(meta *Metafile) (string, bool) {
+	switch {
+	case meta.Update != nil && meta.Update.Modrinth != nil && meta.Update.Modrinth.Version != "":
+		// the modrinth provider accepts project ids instead of slugs
+		return modrinth.VersionSource(meta.Update.Modrinth.ModID, meta.Update.Modrinth.Version), true
+	case meta.Update != nil && meta.Update.CurseForge != nil:
+		file := &curseforge.ManifestFile{ProjectID: meta.Update.CurseForge.ProjectID, FileID: meta.Update.CurseForge.FileID}
+		return file.Source(), true
+	case strings.HasPrefix(meta.Download.URL, "https://"):
+		return meta.Download.URL, true
+	}
+	return "", false
+}
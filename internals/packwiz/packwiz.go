@@ -0,0 +1,134 @@
+// Package packwiz reads and writes modpacks in the packwiz format (pack.toml, index.toml and *.pw.toml metafiles).
+// See https://packwiz.infra.link/reference/pack-format/
+package packwiz
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// PackFile is the name of the main file of a pack
+const PackFile = "pack.toml"
+
+// PackFormat is the written format version
+const PackFormat = "packwiz:1.1.0"
+
+// MetafileExt is the extension of metafiles
+const MetafileExt = ".pw.toml"
+
+// Keys of `Pack.Versions`
+const (
+	VersionMinecraft = "minecraft"
+	VersionFabric    = "fabric"
+	VersionForge     = "forge"
+	VersionQuilt     = "quilt"
+)
+
+// Values of `Metafile.Side`
+const (
+	SideBoth   = "both"
+	SideClient = "client"
+	SideServer = "server"
+)
+
+var (
+	// ErrUnsupportedLoader is returned for packs that need a loader minepkg can not launch (like Quilt)
+	ErrUnsupportedLoader = errors.New("unsupported mod loader")
+	// ErrUnsupportedHash is returned for hash formats that can not be checked (like murmur2)
+	ErrUnsupportedHash = errors.New("unsupported hash format")
+	// ErrHashMismatch is returned if a file does not match its hash
+	ErrHashMismatch = errors.New("file does not match its hash")
+)
+
+// Pack is the content of `pack.toml`
+type Pack struct {
+	Name       string    `toml:"name"`
+	Author     string    `toml:"author,omitempty"`
+	Version    string    `toml:"version,omitempty"`
+	PackFormat string    `toml:"pack-format"`
+	Index      PackIndex `toml:"index"`
+	// Versions maps "minecraft", "fabric", "forge" etc. to the version
+	Versions map[string]string `toml:"versions"`
+}
+
+// PackIndex references the index file
+type PackIndex struct {
+	File       string `toml:"file"`
+	HashFormat string `toml:"hash-format"`
+	Hash       string `toml:"hash"`
+}
+
+// Index is the content of `index.toml`
+type Index struct {
+	HashFormat string      `toml:"hash-format"`
+	Files      []IndexFile `toml:"files"`
+}
+
+// IndexFile is a file of the pack
+type IndexFile struct {
+	// File is the path relative to the index (like "mods/sodium.pw.toml" or "config/sodium.json")
+	File string `toml:"file"`
+	Hash string `toml:"hash"`
+	// HashFormat overrides the hash format of the index. Usually empty
+	HashFormat string `toml:"hash-format,omitempty"`
+	// Metafile is true for *.pw.toml files that describe a download
+	Metafile bool `toml:"metafile,omitempty"`
+}
+
+// Metafile describes a file that is downloaded (usually a mod)
+type Metafile struct {
+	Name     string   `toml:"name"`
+	Filename string   `toml:"filename"`
+	Side     string   `toml:"side,omitempty"`
+	Download Download `toml:"download"`
+	Update   *Update  `toml:"update,omitempty"`
+}
+
+// Download describes where the file of a metafile is downloaded from
+type Download struct {
+	URL        string `toml:"url,omitempty"`
+	HashFormat string `toml:"hash-format"`
+	Hash       string `toml:"hash"`
+	// Mode is "metadata:curseforge" for CurseForge files without an url
+	Mode string `toml:"mode,omitempty"`
+}
+
+// Update contains the update metadata of a metafile
+type Update struct {
+	Modrinth   *ModrinthUpdate   `toml:"modrinth,omitempty"`
+	CurseForge *CurseForgeUpdate `toml:"curseforge,omitempty"`
+}
+
+// ModrinthUpdate references a version on Modrinth
+type ModrinthUpdate struct {
+	ModID   string `toml:"mod-id"`
+	Version string `toml:"version"`
+}
+
+// CurseForgeUpdate references a file on CurseForge
+type CurseForgeUpdate struct {
+	FileID    int `toml:"file-id"`
+	ProjectID int `toml:"project-id"`
+}
+
+// newHash returns a hash for the packwiz hash format
+func newHash(format string) (hash.Hash, error) {
+	switch strings.ToLower(format) {
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	case "md5":
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedHash, format)
+	}
+}
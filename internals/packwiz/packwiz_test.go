@@ -0,0 +1,283 @@
+package packwiz
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestPack(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := filepath.Join(dir, "files")
+	overrides := filepath.Join(dir, "overwrites")
+	os.MkdirAll(files, os.ModePerm)
+	os.MkdirAll(filepath.Join(overrides, "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(files, "sodium.jar"), []byte("sodium"), 0644)
+	ioutil.WriteFile(filepath.Join(files, "jei.jar"), []byte("jei"), 0644)
+	ioutil.WriteFile(filepath.Join(files, "custom.jar"), []byte("custom"), 0644)
+	ioutil.WriteFile(filepath.Join(overrides, "config", "sodium.json"), []byte("{}"), 0644)
+
+	pack := filepath.Join(dir, "pack")
+	_, err := Write(pack, &Export{
+		Name:      "Test Pack",
+		Author:    "someone",
+		Version:   "1.0.0",
+		Versions:  map[string]string{VersionMinecraft: "1.18.2", VersionFabric: "0.14.9"},
+		Overrides: overrides,
+		Mods: []Mod{
+			{
+				Name:     "sodium",
+				Path:     filepath.Join(files, "sodium.jar"),
+				Filename: "sodium.jar",
+				URL:      "https://cdn.modrinth.com/data/AANobbMI/versions/yaoBL9D9/sodium.jar",
+				Update:   &Update{Modrinth: &ModrinthUpdate{ModID: "AANobbMI", Version: "yaoBL9D9"}},
+			},
+			{
+				Name:     "jei",
+				Path:     filepath.Join(files, "jei.jar"),
+				Filename: "jei.jar",
+				URL:      "https://edge.forgecdn.net/files/1/2/jei.jar",
+				Update:   &Update{CurseForge: &CurseForgeUpdate{ProjectID: 238222, FileID: 3847103}},
+			},
+			{Name: "custom", Path: filepath.Join(files, "custom.jar"), Filename: "custom.jar"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pack
+}
+
+func TestWriteImport(t *testing.T) {
+	ctx := context.Background()
+	dir := writeTestPack(t)
+
+	modpack, err := Open(ctx, filepath.Join(dir, PackFile), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modpack.Index.Files) != 4 {
+		t.Fatalf("unexpected index %+v", modpack.Index.Files)
+	}
+
+	imported, err := modpack.Import(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	man := imported.Manifest
+	if man.Package.Name != "test-pack" || man.Package.Version != "1.0.0" || man.Package.Platform != "fabric" {
+		t.Fatalf("unexpected package %+v", man.Package)
+	}
+	if man.Requirements.Minecraft != "1.18.2" || man.Requirements.FabricLoader != "0.14.9" {
+		t.Fatalf("unexpected requirements %+v", man.Requirements)
+	}
+	expected := map[string]string{
+		"sodium": "modrinth:AANobbMI@yaoBL9D9",
+		"jei":    "curseforge:238222@3847103",
+	}
+	if len(man.Dependencies) != len(expected) {
+		t.Fatalf("unexpected dependencies %v", man.Dependencies)
+	}
+	for name, source := range expected {
+		if man.Dependencies[name] != source {
+			t.Errorf("expected %s to be %s, got %s", name, source, man.Dependencies[name])
+		}
+	}
+
+	if len(imported.Files) != 2 {
+		t.Fatalf("unexpected files %+v", imported.Files)
+	}
+	dest := t.TempDir()
+	for i := range imported.Files {
+		if err := modpack.CopyFile(ctx, &imported.Files[i], dest); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if raw, err := ioutil.ReadFile(filepath.Join(dest, "mods", "custom.jar")); err != nil || string(raw) != "custom" {
+		t.Fatalf("expected custom.jar to be copied, got %q %v", raw, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "config", "sodium.json")); err != nil {
+		t.Fatal("expected config to be copied")
+	}
+}
+
+func TestOpen_HashMismatch(t *testing.T) {
+	dir := writeTestPack(t)
+	index := filepath.Join(dir, "index.toml")
+	raw, _ := ioutil.ReadFile(index)
+	ioutil.WriteFile(index, append(raw, '\n'), 0644)
+
+	if _, err := Open(context.Background(), dir, nil); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("expected ErrHashMismatch, got %v", err)
+	}
+	if _, err := Open(context.Background(), t.TempDir(), nil); !errors.Is(err, ErrNoPack) {
+		t.Fatalf("expected ErrNoPack, got %v", err)
+	}
+}
+
+func TestImport_UnsupportedLoader(t *testing.T) {
+	modpack := &Modpack{
+		Pack:  &Pack{Name: "quilt", Versions: map[string]string{VersionMinecraft: "1.19.2", VersionQuilt: "0.17.0"}},
+		Index: &Index{},
+	}
+	if _, err := modpack.Import(context.Background()); !errors.Is(err, ErrUnsupportedLoader) {
+		t.Fatalf("expected ErrUnsupportedLoader, got %v", err)
+	}
+}
+
+func TestImport_Metafiles(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "mods"), os.ModePerm)
+	os.MkdirAll(filepath.Join(dir, "resourcepacks"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, PackFile), []byte(`name = "pack"
+pack-format = "packwiz:1.1.0"
+[index]
+file = "index.toml"
+[versions]
+minecraft = "1.19.2"
+forge = "43.1.1"
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "index.toml"), []byte(`hash-format = "sha256"
+[[files]]
+file = "mods/server-only.pw.toml"
+metafile = true
+[[files]]
+file = "mods/url-mod.pw.toml"
+metafile = true
+[[files]]
+file = "resourcepacks/faithful.pw.toml"
+metafile = true
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "mods", "server-only.pw.toml"), []byte(`name = "Server Only"
+filename = "server.jar"
+side = "server"
+[download]
+url = "https://example.com/server.jar"
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "mods", "url-mod.pw.toml"), []byte(`name = "Url Mod"
+filename = "url.jar"
+side = "both"
+[download]
+url = "https://example.com/url.jar"
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "resourcepacks", "faithful.pw.toml"), []byte(`name = "Faithful"
+filename = "faithful.zip"
+[download]
+url = "https://example.com/faithful.zip"
+`), 0644)
+
+	modpack, err := Open(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := modpack.Import(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Manifest.Package.Platform != "forge" || imported.Manifest.Requirements.ForgeLoader != "43.1.1" {
+		t.Fatalf("unexpected manifest %+v", imported.Manifest.Package)
+	}
+	if imported.Manifest.Dependencies["url-mod"] != "https://example.com/url.jar" || len(imported.Manifest.Dependencies) != 1 {
+		t.Fatalf("unexpected dependencies %v", imported.Manifest.Dependencies)
+	}
+	if len(imported.Downloads) != 1 || imported.Downloads[0].Dir != "resourcepacks" {
+		t.Fatalf("unexpected downloads %+v", imported.Downloads)
+	}
+	if len(imported.Unresolved) != 1 || !strings.Contains(imported.Unresolved[0].Reason, "server") {
+		t.Fatalf("unexpected unresolved %+v", imported.Unresolved)
+	}
+}
+
+func TestImport_IndexInSubdirectory(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "pack", "mods"), os.ModePerm)
+	os.MkdirAll(filepath.Join(dir, "pack", "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, PackFile), []byte(`name = "pack"
+pack-format = "packwiz:1.1.0"
+[index]
+file = "pack/index.toml"
+[versions]
+minecraft = "1.19.2"
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "pack", "index.toml"), []byte(`hash-format = "sha256"
+[[files]]
+file = "mods/url-mod.pw.toml"
+metafile = true
+[[files]]
+file = "config/url-mod.json"
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "pack", "mods", "url-mod.pw.toml"), []byte(`name = "Url Mod"
+filename = "url.jar"
+side = "both"
+[download]
+url = "https://example.com/url.jar"
+`), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "pack", "config", "url-mod.json"), []byte("{}"), 0644)
+
+	modpack, err := Open(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := modpack.Import(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Manifest.Dependencies["url-mod"] != "https://example.com/url.jar" || len(imported.Downloads) != 0 {
+		t.Fatalf("mod was not imported as dependency: %v %+v", imported.Manifest.Dependencies, imported.Downloads)
+	}
+
+	dest := t.TempDir()
+	if len(imported.Files) != 1 {
+		t.Fatalf("unexpected files %+v", imported.Files)
+	}
+	if err := modpack.CopyFile(context.Background(), &imported.Files[0], dest); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "config", "url-mod.json")); err != nil {
+		t.Fatal("expected config to be copied relative to the index")
+	}
+}
+
+func TestWrite_Side(t *testing.T) {
+	dir := t.TempDir()
+	jar := filepath.Join(dir, "zoomify.jar")
+	f, err := os.Create(jar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(f)
+	w, _ := archive.Create("fabric.mod.json")
+	w.Write([]byte(`{"id": "zoomify", "environment": "client"}`))
+	archive.Close()
+	f.Close()
+
+	pack := filepath.Join(dir, "pack")
+	_, err = Write(pack, &Export{
+		Name:     "Side Pack",
+		Versions: map[string]string{VersionMinecraft: "1.18.2", VersionFabric: "0.14.9"},
+		Mods: []Mod{
+			{Name: "zoomify", Path: jar, Filename: "zoomify.jar", URL: "https://cdn.modrinth.com/data/w7ThoJFB/versions/abc/zoomify.jar"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modpack, err := Open(context.Background(), pack, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := modpack.Metafile(context.Background(), &modpack.Index.Files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Side != SideClient {
+		t.Fatalf("expected client only mod, got side %q", meta.Side)
+	}
+}
@@ -0,0 +1,236 @@
+package packwiz
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// ErrNoPack is returned if there is no `pack.toml`
+var ErrNoPack = errors.New("not a packwiz pack (pack.toml not found)")
+
+// source reads the files of a pack
+type source interface {
+	ReadFile(ctx context.Context, name string) ([]byte, error)
+}
+
+type dirSource struct {
+	fsys fs.FS
+}
+
+func (d *dirSource) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	return fs.ReadFile(d.fsys, name)
+}
+
+type httpSource struct {
+	client *http.Client
+	base   *url.URL
+}
+
+func (h *httpSource) ReadFile(ctx context.Context, name string) ([]byte, error) {
+	u, err := h.base.Parse(name)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not download %s: unexpected status code %d", u, res.StatusCode)
+	}
+	return ioutil.ReadAll(res.Body)
+}
+
+// Modpack is an opened packwiz pack
+type Modpack struct {
+	Pack  *Pack
+	Index *Index
+
+	source source
+	client *http.Client
+	// indexDir is the directory of the index in the pack. The files in the index are relative to it
+	indexDir string
+}
+
+// Open opens a pack from a directory, the path of a `pack.toml` or the https url of a `pack.toml`
+func Open(ctx context.Context, location string, client *http.Client) (*Modpack, error) {
+	modpack := &Modpack{client: client}
+	packFile := PackFile
+
+	if strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://") {
+		base, err := url.Parse(location)
+		if err != nil {
+			return nil, err
+		}
+		if path.Base(base.Path) == PackFile {
+			base.Path = path.Dir(base.Path)
+		}
+		base.Path = strings.TrimSuffix(base.Path, "/") + "/"
+		modpack.source = &httpSource{client: client, base: base}
+	} else {
+		if filepath.Base(location) == PackFile {
+			location = filepath.Dir(location)
+		}
+		modpack.source = &dirSource{fsys: os.DirFS(location)}
+	}
+
+	raw, err := modpack.source.ReadFile(ctx, packFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNoPack
+		}
+		return nil, err
+	}
+	modpack.Pack = &Pack{}
+	if err := toml.Unmarshal(raw, modpack.Pack); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", PackFile, err)
+	}
+
+	indexFile := modpack.Pack.Index.File
+	if indexFile == "" {
+		indexFile = "index.toml"
+	}
+	raw, err = modpack.source.ReadFile(ctx, indexFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHash(raw, modpack.Pack.Index.HashFormat, modpack.Pack.Index.Hash); err != nil {
+		return nil, fmt.Errorf("%s: %w", indexFile, err)
+	}
+	modpack.Index = &Index{}
+	if err := toml.Unmarshal(raw, modpack.Index); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", indexFile, err)
+	}
+	modpack.indexDir = path.Dir(indexFile)
+	return modpack, nil
+}
+
+// checkHash returns ErrHashMismatch if content does not match. Unsupported formats are not checked
+func checkHash(content []byte, format string, expected string) error {
+	if format == "" || expected == "" {
+		return nil
+	}
+	h, err := newHash(format)
+	if err != nil {
+		return nil
+	}
+	h.Write(content)
+	if !strings.EqualFold(hex.EncodeToString(h.Sum(nil)), expected) {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+// hashFormat returns the hash format of the file in the index
+func (m *Modpack) hashFormat(file *IndexFile) string {
+	if file.HashFormat != "" {
+		return file.HashFormat
+	}
+	return m.Index.HashFormat
+}
+
+// readFile reads a file of the index from the source
+func (m *Modpack) readFile(ctx context.Context, file *IndexFile) ([]byte, error) {
+	return m.source.ReadFile(ctx, path.Join(m.indexDir, file.File))
+}
+
+// Metafile reads the metafile in the index
+func (m *Modpack) Metafile(ctx context.Context, file *IndexFile) (*Metafile, error) {
+	raw, err := m.readFile(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkHash(raw, m.hashFormat(file), file.Hash); err != nil {
+		return nil, fmt.Errorf("%s: %w", file.File, err)
+	}
+	meta := &Metafile{}
+	if err := toml.Unmarshal(raw, meta); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", file.File, err)
+	}
+	return meta, nil
+}
+
+// target returns the location of a file of the pack in dest. Returns an error if name points outside of dest
+func target(dest string, name string) (string, error) {
+	p := filepath.Join(dest, filepath.FromSlash(name))
+	rel, err := filepath.Rel(dest, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s: illegal file path", name)
+	}
+	return p, nil
+}
+
+// CopyFile copies a file (that is no metafile) of the pack into the Minecraft directory dest
+func (m *Modpack) CopyFile(ctx context.Context, file *IndexFile, dest string) error {
+	p, err := target(dest, file.File)
+	if err != nil {
+		return err
+	}
+	raw, err := m.readFile(ctx, file)
+	if err != nil {
+		return err
+	}
+	if err := checkHash(raw, m.hashFormat(file), file.Hash); err != nil {
+		return fmt.Errorf("%s: %w", file.File, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, raw, 0644)
+}
+
+// Download downloads the file of the metafile to the Minecraft directory dest.
+// dir is the directory of the metafile in the pack (like "resourcepacks")
+func (m *Modpack) Download(ctx context.Context, meta *Metafile, dir string, dest string) error {
+	p, err := target(dest, path.Join(dir, meta.Filename))
+	if err != nil {
+		return err
+	}
+	if meta.Download.URL == "" {
+		return fmt.Errorf("%s has no download url", meta.Name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", meta.Download.URL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not download %s: unexpected status code %d", meta.Name, res.StatusCode)
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := io.Copy(buf, res.Body); err != nil {
+		return err
+	}
+	if err := checkHash(buf.Bytes(), meta.Download.HashFormat, meta.Download.Hash); err != nil {
+		return fmt.Errorf("%s: %w", meta.Name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, buf.Bytes(), 0644)
+}
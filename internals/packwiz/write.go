@@ -0,0 +1,156 @@
+package packwiz
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/minepkg/minepkg/internals/mrpack"
+	"github.com/minepkg/minepkg/internals/pack"
+	"github.com/pelletier/go-toml"
+)
+
+// hashFormat is the hash format of written packs
+const hashFormat = "sha256"
+
+// Export describes a pack to write
+type Export struct {
+	Name    string
+	Author  string
+	Version string
+	// Versions maps "minecraft", "fabric" etc. to the version
+	Versions map[string]string
+	Mods     []Mod
+	// Overrides is a directory whose files are added to the pack (configs etc.). Can be empty
+	Overrides string
+}
+
+// Mod is a mod of the pack
+type Mod struct {
+	// Name is the name of the metafile (without .pw.toml)
+	Name string
+	// Path is the location of the jar on disk
+	Path string
+	// Filename is the name of the file in the mods directory
+	Filename string
+	// URL is the download url. Mods without one are copied into the pack
+	URL string
+	// Update is the Modrinth or CurseForge metadata of the mod. Can be nil
+	Update *Update
+}
+
+type packWriter struct {
+	dir   string
+	index *Index
+}
+
+// write writes a file of the pack and adds it to the index
+func (p *packWriter) write(name string, content []byte, metafile bool) error {
+	target := filepath.Join(p.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(target, content, 0644); err != nil {
+		return err
+	}
+	p.index.Files = append(p.index.Files, IndexFile{File: name, Hash: sha256Hex(content), Metafile: metafile})
+	return nil
+}
+
+// modSide returns the side of the mod jar. It uses the same detection as the mrpack export
+func modSide(jar string) string {
+	env := mrpack.ModEnv(jar)
+	switch {
+	case env == nil:
+		return SideBoth
+	case env.Server == mrpack.EnvUnsupported:
+		return SideClient
+	case env.Client == mrpack.EnvUnsupported:
+		return SideServer
+	}
+	return SideBoth
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := toml.NewEncoder(buf).Order(toml.OrderPreserve).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes the pack to the directory dir
+func Write(dir string, export *Export) (*Pack, error) {
+	w := &packWriter{dir: dir, index: &Index{HashFormat: hashFormat, Files: []IndexFile{}}}
+
+	for _, mod := range export.Mods {
+		content, err := ioutil.ReadFile(mod.Path)
+		if err != nil {
+			return nil, err
+		}
+		if mod.URL == "" {
+			if err := w.write(path.Join("mods", mod.Filename), content, false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		meta, err := marshal(&Metafile{
+			Name:     mod.Name,
+			Filename: mod.Filename,
+			Side:     modSide(mod.Path),
+			Download: Download{URL: mod.URL, HashFormat: hashFormat, Hash: sha256Hex(content)},
+			Update:   mod.Update,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := w.write(path.Join("mods", mod.Name+MetafileExt), meta, true); err != nil {
+			return nil, err
+		}
+	}
+
+	err := pack.WalkOverrides(export.Overrides, func(src string, name string) error {
+		content, err := ioutil.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		return w.write(name, content, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(w.index.Files, func(a, b int) bool { return w.index.Files[a].File < w.index.Files[b].File })
+	index, err := marshal(w.index)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "index.toml"), index, 0644); err != nil {
+		return nil, err
+	}
+
+	packFile := &Pack{
+		Name:       export.Name,
+		Author:     export.Author,
+		Version:    export.Version,
+		PackFormat: PackFormat,
+		Index:      PackIndex{File: "index.toml", HashFormat: hashFormat, Hash: sha256Hex(index)},
+		Versions:   export.Versions,
+	}
+	raw, err := marshal(packFile)
+	if err != nil {
+		return nil, err
+	}
+	return packFile, ioutil.WriteFile(filepath.Join(dir, PackFile), raw, 0644)
+}
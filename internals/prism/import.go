@@ -0,0 +1,291 @@
+package prism
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/modrinth"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// ErrNoInstance is returned if there is no `mmc-pack.json` in the directory or zip
+var ErrNoInstance = errors.New("not a Prism Launcher or MultiMC instance")
+
+// excludeFromImport are files and directories in the Minecraft directory that do not belong to the pack
+var excludeFromImport = map[string]bool{
+	"mods":               true,
+	"saves":              true,
+	"logs":               true,
+	"crash-reports":      true,
+	"screenshots":        true,
+	".fabric":            true,
+	".mixin.out":         true,
+	"usercache.json":     true,
+	"usernamecache.json": true,
+}
+
+// Source is an opened instance directory or zip
+type Source struct {
+	Pack *MMCPack
+	// Name is the name of the instance from `instance.cfg`. Defaults to the name of the instance directory or zip
+	Name string
+
+	config map[string]string
+	fsys   fs.FS
+	mcDir  string
+	closer io.Closer
+}
+
+// Open opens an instance directory or a zip of an instance. The instance can also be in
+// a single subdirectory of the zip (as exported by Prism Launcher)
+func Open(p string) (*Source, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &Source{}
+	if info.IsDir() {
+		source.fsys = os.DirFS(p)
+	} else {
+		archive, err := zip.OpenReader(p)
+		if err != nil {
+			return nil, ErrNoInstance
+		}
+		source.fsys = archive
+		source.closer = archive
+	}
+
+	if err := source.init(); err != nil {
+		source.Close()
+		return nil, err
+	}
+	if source.Name == "" {
+		source.Name = defaultName(p, info.IsDir())
+	}
+	return source, nil
+}
+
+// init finds the instance root and reads the pack and config
+func (s *Source) init() error {
+	if _, err := fs.Stat(s.fsys, PackFile); err != nil {
+		entries, err := fs.ReadDir(s.fsys, ".")
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, entry := range entries {
+			if _, err := fs.Stat(s.fsys, path.Join(entry.Name(), PackFile)); entry.IsDir() && err == nil {
+				sub, err := fs.Sub(s.fsys, entry.Name())
+				if err != nil {
+					return err
+				}
+				s.fsys = sub
+				s.Name = entry.Name()
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrNoInstance
+		}
+	}
+
+	raw, err := fs.ReadFile(s.fsys, PackFile)
+	if err != nil {
+		return err
+	}
+	s.Pack = &MMCPack{}
+	if err := json.Unmarshal(raw, s.Pack); err != nil {
+		return fmt.Errorf("%w: invalid %s: %s", ErrNoInstance, PackFile, err)
+	}
+
+	s.config = map[string]string{}
+	if raw, err := fs.ReadFile(s.fsys, ConfigFile); err == nil {
+		s.config = parseConfig(string(raw))
+	}
+	if name := s.config["name"]; name != "" {
+		s.Name = name
+	}
+
+	s.mcDir = MinecraftDir
+	if _, err := fs.Stat(s.fsys, MinecraftDir); err != nil {
+		s.mcDir = "minecraft"
+	}
+	return nil
+}
+
+// defaultName returns the name of the instance directory or zip p
+func defaultName(p string, isDir bool) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		p = abs
+	}
+	name := filepath.Base(p)
+	if !isDir {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// Close closes the underlying zip file (if any)
+func (s *Source) Close() error {
+	if s.closer != nil {
+		return s.closer.Close()
+	}
+	return nil
+}
+
+// Import is the result of converting an instance to a minepkg manifest
+type Import struct {
+	Manifest *manifest.Manifest
+	// Modrinth lists the dependencies that were identified on Modrinth
+	Modrinth []string
+	// Unidentified lists the mod jars Modrinth does not know. They belong in the overwrites directory
+	Unidentified []string
+}
+
+// Import converts the instance to a manifest of a modpack. Mod jars are identified by their hash
+// and become `modrinth:` dependencies
+func (s *Source) Import(ctx context.Context, lookup modrinth.FileLookup) (*Import, error) {
+	man := manifest.New()
+	man.Package.Type = manifest.TypeModpack
+	man.Package.Name = manifest.PackageName(s.Name)
+	if err := s.Pack.Requirements(man); err != nil {
+		return nil, err
+	}
+	man.Launch = s.launch()
+
+	result := &Import{Manifest: man}
+	mods, err := fs.ReadDir(s.fsys, path.Join(s.mcDir, "mods"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, mod := range mods {
+		// disabled mods end with ".jar.disabled" and are skipped
+		if mod.IsDir() || path.Ext(mod.Name()) != ".jar" {
+			continue
+		}
+		hash, err := s.sha512(path.Join(s.mcDir, "mods", mod.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		project, version, err := modrinth.Identify(ctx, lookup, hash)
+		switch {
+		case errors.Is(err, modrinth.ErrNotFound):
+			result.Unidentified = append(result.Unidentified, mod.Name())
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("could not look up %s on modrinth: %w", mod.Name(), err)
+		}
+
+		name := manifest.PackageName(project.Slug)
+		if _, exists := man.Dependencies[name]; exists {
+			name = manifest.PackageName(name + "-" + version.ID)
+		}
+		man.AddDependency(name, modrinth.Source(project, version))
+		result.Modrinth = append(result.Modrinth, name)
+	}
+
+	return result, nil
+}
+
+// launch returns the memory and java arguments of `instance.cfg` (if overwritten)
+func (s *Source) launch() *manifest.Launch {
+	launch := &manifest.Launch{}
+	if s.config["OverrideMemory"] == "true" {
+		if minMemory, err := strconv.Atoi(s.config["MinMemAlloc"]); err == nil && minMemory > 0 {
+			launch.MinMemory = fmt.Sprintf("%dM", minMemory)
+		}
+		if maxMemory, err := strconv.Atoi(s.config["MaxMemAlloc"]); err == nil && maxMemory > 0 {
+			launch.MaxMemory = fmt.Sprintf("%dM", maxMemory)
+		}
+	}
+	if s.config["OverrideJavaArgs"] == "true" && s.config["JvmArgs"] != "" {
+		launch.JVMArgs = strings.Fields(s.config["JvmArgs"])
+	}
+	if launch.MinMemory == "" && launch.MaxMemory == "" && len(launch.JVMArgs) == 0 {
+		return nil
+	}
+	return launch
+}
+
+func (s *Source) sha512(name string) (string, error) {
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha512.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Extract copies the files of the Minecraft directory (configs, resource packs etc.) and the
+// unidentified mods of the import to dest. Worlds, logs and the other mods are left out
+func (s *Source) Extract(dest string, imported *Import) error {
+	for _, mod := range imported.Unidentified {
+		name := path.Join("mods", mod)
+		if err := s.extractFile(path.Join(s.mcDir, name), filepath.Join(dest, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+	}
+
+	return fs.WalkDir(s.fsys, s.mcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p == s.mcDir {
+				return fs.SkipDir
+			}
+			return err
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, s.mcDir), "/")
+		if rel == "" {
+			return nil
+		}
+		if excludeFromImport[rel] {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		return s.extractFile(p, filepath.Join(dest, filepath.FromSlash(rel)))
+	})
+}
+
+func (s *Source) extractFile(name string, target string) error {
+	in, err := s.fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,111 @@
+// Package prism reads and writes instances of Prism Launcher and MultiMC
+package prism
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// PackFile is the name of the file that lists the components of an instance
+const PackFile = "mmc-pack.json"
+
+// ConfigFile is the name of the instance settings file
+const ConfigFile = "instance.cfg"
+
+// MinecraftDir is the Minecraft directory inside the instance. MultiMC also uses "minecraft"
+const MinecraftDir = ".minecraft"
+
+// Component uids
+const (
+	UIDMinecraft    = "net.minecraft"
+	UIDIntermediary = "net.fabricmc.intermediary"
+	UIDFabricLoader = "net.fabricmc.fabric-loader"
+	UIDQuiltLoader  = "org.quiltmc.quilt-loader"
+	UIDForge        = "net.minecraftforge"
+)
+
+// ErrUnsupportedLoader is returned for instances that use a loader minepkg can not launch (like Quilt)
+var ErrUnsupportedLoader = errors.New("unsupported mod loader")
+
+// MMCPack is the content of `mmc-pack.json`
+type MMCPack struct {
+	FormatVersion int         `json:"formatVersion"`
+	Components    []Component `json:"components"`
+}
+
+// Component is Minecraft, a loader or a library of the instance
+type Component struct {
+	UID            string `json:"uid"`
+	Version        string `json:"version"`
+	Important      bool   `json:"important,omitempty"`
+	DependencyOnly bool   `json:"dependencyOnly,omitempty"`
+}
+
+// NewMMCPack returns the components for the Minecraft version and platform (like "fabric")
+func NewMMCPack(minecraft string, platform string, platformVersion string) *MMCPack {
+	pack := &MMCPack{
+		FormatVersion: 1,
+		Components:    []Component{{UID: UIDMinecraft, Version: minecraft, Important: true}},
+	}
+	switch platform {
+	case manifest.PlatformFabric:
+		pack.Components = append(pack.Components,
+			Component{UID: UIDIntermediary, Version: minecraft, DependencyOnly: true},
+			Component{UID: UIDFabricLoader, Version: platformVersion},
+		)
+	case manifest.PlatformForge:
+		pack.Components = append(pack.Components, Component{UID: UIDForge, Version: platformVersion})
+	}
+	return pack
+}
+
+// Version returns the version of the component or an empty string if the instance does not have it
+func (p *MMCPack) Version(uid string) string {
+	for _, component := range p.Components {
+		if component.UID == uid {
+			return component.Version
+		}
+	}
+	return ""
+}
+
+// Requirements sets the requirements and platform of the manifest to the components of the instance
+func (p *MMCPack) Requirements(man *manifest.Manifest) error {
+	man.Requirements.Minecraft = p.Version(UIDMinecraft)
+	if man.Requirements.Minecraft == "" {
+		return fmt.Errorf("%s does not contain Minecraft", PackFile)
+	}
+
+	switch {
+	case p.Version(UIDFabricLoader) != "":
+		man.Package.Platform = manifest.PlatformFabric
+		man.Requirements.FabricLoader = p.Version(UIDFabricLoader)
+	case p.Version(UIDForge) != "":
+		man.Package.Platform = manifest.PlatformForge
+		man.Requirements.ForgeLoader = p.Version(UIDForge)
+	case p.Version(UIDQuiltLoader) != "":
+		return fmt.Errorf("%w: quilt", ErrUnsupportedLoader)
+	default:
+		man.Package.Platform = manifest.PlatformVanilla
+	}
+	return nil
+}
+
+// parseConfig parses the `key=value` lines of `instance.cfg`
+func parseConfig(content string) map[string]string {
+	config := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			config[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return config
+}
@@ -0,0 +1,172 @@
+package prism
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/modrinth"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+type fakeLookup struct {
+	versions map[string]*modrinth.Version
+}
+
+func (f *fakeLookup) GetVersionFile(ctx context.Context, hash string) (*modrinth.Version, error) {
+	if version, ok := f.versions[hash]; ok {
+		return version, nil
+	}
+	return nil, modrinth.ErrNotFound
+}
+
+func (f *fakeLookup) GetProject(ctx context.Context, idOrSlug string) (*modrinth.Project, error) {
+	return &modrinth.Project{ID: idOrSlug, Slug: "sodium"}, nil
+}
+
+func testInstance(t *testing.T) (*Instance, string) {
+	t.Helper()
+	dir := t.TempDir()
+	known := filepath.Join(dir, "known.jar")
+	ioutil.WriteFile(known, []byte("known jar"), 0644)
+	unknown := filepath.Join(dir, "unknown.jar")
+	ioutil.WriteFile(unknown, []byte("unknown jar"), 0644)
+	overrides := filepath.Join(dir, "overwrites")
+	os.MkdirAll(filepath.Join(overrides, "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(overrides, "config", "test.json"), []byte("{}"), 0644)
+
+	hash := sha512.Sum512([]byte("known jar"))
+	return &Instance{
+		Name:            "Test Pack",
+		Minecraft:       "1.19.4",
+		Platform:        "fabric",
+		PlatformVersion: "0.14.21",
+		Launch:          &manifest.Launch{MaxMemory: "6G", JVMArgs: []string{"-Dfoo=bar"}},
+		Mods:            []Mod{{Path: known, Filename: "sodium.jar"}, {Path: unknown, Filename: "private.jar"}},
+		Overrides:       overrides,
+	}, hex.EncodeToString(hash[:])
+}
+
+func TestWriteAndImport(t *testing.T) {
+	instance, knownHash := testInstance(t)
+	buf := &bytes.Buffer{}
+	if err := Write(buf, instance); err != nil {
+		t.Fatal(err)
+	}
+	zipFile := filepath.Join(t.TempDir(), "instance.zip")
+	if err := ioutil.WriteFile(zipFile, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := Open(zipFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+	if source.Pack.Version(UIDFabricLoader) != "0.14.21" || source.Pack.Version(UIDMinecraft) != "1.19.4" {
+		t.Fatalf("unexpected components %+v", source.Pack.Components)
+	}
+
+	lookup := &fakeLookup{versions: map[string]*modrinth.Version{knownHash: {ID: "AABBCCDD", ProjectID: "P1"}}}
+	imported, err := source.Import(context.Background(), lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	man := imported.Manifest
+	if man.Package.Name != "test-pack" || man.Requirements.FabricLoader != "0.14.21" || man.Requirements.Minecraft != "1.19.4" {
+		t.Fatalf("unexpected manifest %+v %+v", man.Package, man.Requirements)
+	}
+	if len(man.Dependencies) != 1 || man.Dependencies["sodium"] != "modrinth:sodium@AABBCCDD" {
+		t.Fatalf("unexpected dependencies %v", man.Dependencies)
+	}
+	if man.Launch == nil || man.Launch.MaxMemory != "6144M" || len(man.Launch.JVMArgs) != 1 {
+		t.Fatalf("unexpected launch settings %+v", man.Launch)
+	}
+	if len(imported.Unidentified) != 1 || imported.Unidentified[0] != "private.jar" {
+		t.Fatalf("unexpected unidentified mods %v", imported.Unidentified)
+	}
+
+	dest := t.TempDir()
+	if err := source.Extract(dest, imported); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"config/test.json", "mods/private.jar"} {
+		if _, err := os.Stat(filepath.Join(dest, expected)); err != nil {
+			t.Fatalf("expected %s to be extracted", expected)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dest, "mods", "sodium.jar")); !os.IsNotExist(err) {
+		t.Fatal("expected identified mods to not be copied")
+	}
+}
+
+func TestWriteDir(t *testing.T) {
+	instance, _ := testInstance(t)
+	instance.Platform, instance.PlatformVersion = "vanilla", ""
+	dir := filepath.Join(t.TempDir(), "instances", "test-pack")
+	if err := WriteDir(dir, instance); err != nil {
+		t.Fatal(err)
+	}
+
+	// instances can also be opened from their parent directory
+	source, err := Open(filepath.Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.Name != "Test Pack" || len(source.Pack.Components) != 1 {
+		t.Fatalf("unexpected instance %s %+v", source.Name, source.Pack)
+	}
+	if _, err := os.Stat(filepath.Join(dir, MinecraftDir, "mods", "private.jar")); err != nil {
+		t.Fatal("expected mods to be written")
+	}
+
+	if _, err := Open(t.TempDir()); err != ErrNoInstance {
+		t.Fatalf("expected ErrNoInstance, got %v", err)
+	}
+}
+
+func TestImport_names(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.jar")
+	ioutil.WriteFile(first, []byte("first"), 0644)
+	second := filepath.Join(dir, "second.jar")
+	ioutil.WriteFile(second, []byte("second"), 0644)
+	firstHash := sha512.Sum512([]byte("first"))
+	secondHash := sha512.Sum512([]byte("second"))
+
+	// instances without name are named like their directory
+	instanceDir := filepath.Join(dir, "My Instance")
+	err := WriteDir(instanceDir, &Instance{
+		Minecraft: "1.19.4",
+		Platform:  "vanilla",
+		Mods:      []Mod{{Path: first, Filename: "a.jar"}, {Path: second, Filename: "b.jar"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := Open(instanceDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookup := &fakeLookup{versions: map[string]*modrinth.Version{
+		hex.EncodeToString(firstHash[:]):  {ID: "AABBCCDD", ProjectID: "P1"},
+		hex.EncodeToString(secondHash[:]): {ID: "EeFfGgHh", ProjectID: "P1"},
+	}}
+	imported, err := source.Import(context.Background(), lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	man := imported.Manifest
+	if man.Package.Name != "my-instance" {
+		t.Fatalf("unexpected name %q", man.Package.Name)
+	}
+	if len(man.Dependencies) != 2 || man.Dependencies["sodium-eeffgghh"] != "modrinth:sodium@EeFfGgHh" {
+		t.Fatalf("unexpected dependencies %v", man.Dependencies)
+	}
+}
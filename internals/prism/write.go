@@ -0,0 +1,152 @@
+package prism
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/pack"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// Instance describes an instance to write
+type Instance struct {
+	Name            string
+	Minecraft       string
+	Platform        string
+	PlatformVersion string
+	// Launch sets the memory and java arguments of the instance. Can be nil
+	Launch *manifest.Launch
+	Mods   []Mod
+	// Overrides is a directory that is copied into the Minecraft directory (configs etc.). Can be empty
+	Overrides string
+}
+
+// Mod is a mod jar of the instance
+type Mod struct {
+	// Path is the location of the jar on disk
+	Path string
+	// Filename is the name of the file in the mods directory
+	Filename string
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// Write writes the instance as zip that can be imported in Prism Launcher and MultiMC
+func Write(w io.Writer, instance *Instance) error {
+	archive := zip.NewWriter(w)
+	create := func(name string) (io.WriteCloser, error) {
+		out, err := archive.Create(name)
+		return nopCloser{out}, err
+	}
+	if err := writeInstance(create, instance); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// WriteDir writes the instance into the directory dir (like the instances directory of Prism Launcher)
+func WriteDir(dir string, instance *Instance) error {
+	return writeInstance(func(name string) (io.WriteCloser, error) {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return nil, err
+		}
+		return os.Create(target)
+	}, instance)
+}
+
+func writeInstance(create func(name string) (io.WriteCloser, error), instance *Instance) error {
+	cfg, err := instanceConfig(instance)
+	if err != nil {
+		return err
+	}
+	if err := writeBytes(create, ConfigFile, []byte(cfg)); err != nil {
+		return err
+	}
+
+	mmcPack, err := json.MarshalIndent(NewMMCPack(instance.Minecraft, instance.Platform, instance.PlatformVersion), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeBytes(create, PackFile, mmcPack); err != nil {
+		return err
+	}
+
+	for _, mod := range instance.Mods {
+		if err := copyFile(create, mod.Path, path.Join(MinecraftDir, "mods", mod.Filename)); err != nil {
+			return err
+		}
+	}
+
+	return pack.WalkOverrides(instance.Overrides, func(src string, name string) error {
+		return copyFile(create, src, path.Join(MinecraftDir, name))
+	})
+}
+
+// instanceConfig returns the content of `instance.cfg`
+func instanceConfig(instance *Instance) (string, error) {
+	lines := []string{"InstanceType=OneSix", "name=" + instance.Name}
+
+	if launch := instance.Launch; launch != nil {
+		minMemory, err := manifest.ParseMemory(launch.MinMemory)
+		if err != nil {
+			return "", err
+		}
+		maxMemory, err := manifest.ParseMemory(launch.MaxMemory)
+		if err != nil {
+			return "", err
+		}
+		if minMemory != 0 || maxMemory != 0 {
+			lines = append(lines, "OverrideMemory=true")
+			if minMemory != 0 {
+				lines = append(lines, fmt.Sprintf("MinMemAlloc=%d", minMemory))
+			}
+			if maxMemory != 0 {
+				lines = append(lines, fmt.Sprintf("MaxMemAlloc=%d", maxMemory))
+			}
+		}
+		if len(launch.JVMArgs) != 0 {
+			lines = append(lines, "OverrideJavaArgs=true", "JvmArgs="+strings.Join(launch.JVMArgs, " "))
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func writeBytes(create func(name string) (io.WriteCloser, error), name string, content []byte) error {
+	out, err := create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(content); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func copyFile(create func(name string) (io.WriteCloser, error), src string, name string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
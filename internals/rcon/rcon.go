@@ -0,0 +1,191 @@
+// Package rcon implements a client for the Source RCON protocol that is used by Minecraft servers
+// to accept remote commands. See https://wiki.vg/RCON
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Packet types
+const (
+	TypeResponse int32 = 0
+	TypeCommand  int32 = 2
+	TypeAuth     int32 = 3
+)
+
+const (
+	// MaxCommandLength is the maximum length of a command Minecraft accepts
+	MaxCommandLength = 1446
+	// maxPacketSize is the maximum size of a packet we accept (responses are up to 4096 bytes)
+	maxPacketSize = 4096 + 10
+	// DefaultPort is the default rcon port of Minecraft servers
+	DefaultPort = 25575
+)
+
+var (
+	// ErrAuthFailed is returned if the server rejected the password
+	ErrAuthFailed = errors.New("rcon authentication failed (wrong password)")
+	// ErrCommandTooLong is returned if a command exceeds `MaxCommandLength`
+	ErrCommandTooLong = fmt.Errorf("rcon command is longer than %d bytes", MaxCommandLength)
+	// ErrInvalidPacket is returned if the server sent a malformed packet
+	ErrInvalidPacket = errors.New("invalid rcon packet")
+)
+
+// Packet is a single rcon packet
+type Packet struct {
+	ID   int32
+	Type int32
+	Body string
+}
+
+// WritePacket writes p to w in the rcon wire format
+func WritePacket(w io.Writer, p *Packet) error {
+	buf := bytes.Buffer{}
+	length := int32(4 + 4 + len(p.Body) + 2)
+	binary.Write(&buf, binary.LittleEndian, length)
+	binary.Write(&buf, binary.LittleEndian, p.ID)
+	binary.Write(&buf, binary.LittleEndian, p.Type)
+	buf.WriteString(p.Body)
+	buf.Write([]byte{0, 0})
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadPacket reads a single packet from r
+func ReadPacket(r io.Reader) (*Packet, error) {
+	var length int32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < 10 || length > maxPacketSize {
+		return nil, ErrInvalidPacket
+	}
+
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	return &Packet{
+		ID:   int32(binary.LittleEndian.Uint32(buf[0:4])),
+		Type: int32(binary.LittleEndian.Uint32(buf[4:8])),
+		// strip the two null bytes
+		Body: string(bytes.TrimRight(buf[8:], "\x00")),
+	}, nil
+}
+
+// Client is a connection to a rcon server
+type Client struct {
+	conn    net.Conn
+	timeout time.Duration
+	lastID  int32
+	mu      sync.Mutex
+}
+
+// Dial connects to the rcon server at addr and authenticates with password
+func Dial(addr string, password string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: conn, timeout: timeout}
+	if err := c.auth(password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) auth(password string) error {
+	id := c.nextID()
+	if err := c.write(&Packet{ID: id, Type: TypeAuth, Body: password}); err != nil {
+		return err
+	}
+
+	for {
+		res, err := c.read()
+		if err != nil {
+			return err
+		}
+		// some servers send an empty response value before the auth response
+		if res.Type != TypeCommand {
+			continue
+		}
+		if res.ID == -1 {
+			return ErrAuthFailed
+		}
+		if res.ID != id {
+			return ErrInvalidPacket
+		}
+		return nil
+	}
+}
+
+// Exec runs command on the server and returns the response
+func (c *Client) Exec(command string) (string, error) {
+	if len(command) > MaxCommandLength {
+		return "", ErrCommandTooLong
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.nextID()
+	if err := c.write(&Packet{ID: id, Type: TypeCommand, Body: command}); err != nil {
+		return "", err
+	}
+	// long responses are split into multiple packets without any end marker.
+	// we send a second (invalid) packet & read until we get the answer to that one
+	endID := c.nextID()
+	if err := c.write(&Packet{ID: endID, Type: TypeResponse}); err != nil {
+		return "", err
+	}
+
+	response := bytes.Buffer{}
+	for {
+		res, err := c.read()
+		if err != nil {
+			return "", err
+		}
+		switch res.ID {
+		case id:
+			response.WriteString(res.Body)
+		case endID:
+			return response.String(), nil
+		default:
+			return "", ErrInvalidPacket
+		}
+	}
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) nextID() int32 {
+	c.lastID++
+	return c.lastID
+}
+
+func (c *Client) write(p *Packet) error {
+	if c.timeout != 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	}
+	return WritePacket(c.conn, p)
+}
+
+func (c *Client) read() (*Packet, error) {
+	if c.timeout != 0 {
+		c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+	}
+	return ReadPacket(c.conn)
+}
@@ -0,0 +1,141 @@
+package rcon
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer behaves like the rcon server of Minecraft
+type fakeServer struct {
+	listener net.Listener
+	password string
+	commands []string
+}
+
+func newFakeServer(t *testing.T, password string) *fakeServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{listener: l, password: password}
+	t.Cleanup(func() { l.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	authed := false
+	for {
+		p, err := ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p.Type {
+		case TypeAuth:
+			if p.Body != s.password {
+				WritePacket(conn, &Packet{ID: -1, Type: TypeCommand})
+				return
+			}
+			authed = true
+			WritePacket(conn, &Packet{ID: p.ID, Type: TypeCommand})
+		case TypeCommand:
+			if !authed {
+				WritePacket(conn, &Packet{ID: -1, Type: TypeCommand})
+				return
+			}
+			s.commands = append(s.commands, p.Body)
+			response := "Executed " + p.Body
+			if p.Body == "help" {
+				// long responses are split into 4096 byte packets
+				response = strings.Repeat("/command\n", 1000)
+			}
+			for len(response) > 4096 {
+				WritePacket(conn, &Packet{ID: p.ID, Type: TypeResponse, Body: response[:4096]})
+				response = response[4096:]
+			}
+			WritePacket(conn, &Packet{ID: p.ID, Type: TypeResponse, Body: response})
+		default:
+			WritePacket(conn, &Packet{ID: p.ID, Type: TypeResponse, Body: fmt.Sprintf("Unknown request %x", p.Type)})
+		}
+	}
+}
+
+func TestClient_Exec(t *testing.T) {
+	server := newFakeServer(t, "hunter2")
+
+	client, err := Dial(server.Addr(), "hunter2", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	res, err := client.Exec("say hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "Executed say hi" {
+		t.Errorf("unexpected response %q", res)
+	}
+
+	res, err = client.Exec("help")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != strings.Repeat("/command\n", 1000) {
+		t.Errorf("multi packet response was not joined (got %d bytes)", len(res))
+	}
+
+	if len(server.commands) != 2 {
+		t.Errorf("unexpected commands %v", server.commands)
+	}
+}
+
+func TestClient_WrongPassword(t *testing.T) {
+	server := newFakeServer(t, "hunter2")
+
+	_, err := Dial(server.Addr(), "wrong", time.Second)
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("expected ErrAuthFailed, got %v", err)
+	}
+}
+
+func TestClient_CommandTooLong(t *testing.T) {
+	server := newFakeServer(t, "hunter2")
+
+	client, err := Dial(server.Addr(), "hunter2", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.Exec(strings.Repeat("a", MaxCommandLength+1)); !errors.Is(err, ErrCommandTooLong) {
+		t.Fatalf("expected ErrCommandTooLong, got %v", err)
+	}
+}
+
+func TestReadPacket_Invalid(t *testing.T) {
+	r := strings.NewReader("\x02\x00\x00\x00\x00\x00")
+	if _, err := ReadPacket(r); !errors.Is(err, ErrInvalidPacket) {
+		t.Fatalf("expected ErrInvalidPacket, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/minepkg/minepkg/internals/curseforge"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+var (
+	ErrDownloadNotAllowed = errors.New("the author does not allow downloads through other launchers")
+)
+
+type CurseForgeProvider struct {
+	Client *curseforge.Client
+}
+
+type curseForgeResult struct {
+	name string
+	file *curseforge.File
+}
+
+func (c *curseForgeResult) Lock() *manifest.DependencyLock {
+	return &manifest.DependencyLock{
+		Name: c.name,
+		// file ids are unique, display names may contain anything
+		Version:  strconv.Itoa(c.file.ID),
+		Type:     "mod",
+		URL:      c.file.DownloadURL,
+		Provider: "curseforge",
+		Sha1:     c.file.Sha1(),
+	}
+}
+
+func (c *curseForgeResult) Dependencies() []*manifest.InterpretedDependency {
+	return nil
+}
+
+func (c *CurseForgeProvider) Resolve(ctx context.Context, request *Request) (Result, error) {
+	projectID, fileID, err := curseforge.ParseSource(request.Dependency.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := c.Client.GetFile(ctx, projectID, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %s: %w", request.Dependency.Name, err)
+	}
+	if file.DownloadURL == "" {
+		return nil, fmt.Errorf("could not resolve %s: %w", request.Dependency.Name, ErrDownloadNotAllowed)
+	}
+
+	return &curseForgeResult{name: request.Dependency.Name, file: file}, nil
+}
+
+func (c *CurseForgeProvider) Fetch(ctx context.Context, toFetch Result) (io.Reader, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", toFetch.Lock().URL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fileRes, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return fileRes.Body, int(fileRes.ContentLength), nil
+}
@@ -92,6 +92,10 @@ func New(man *manifest.Manifest, platformLock manifest.PlatformLock) *Resolver {
 		Client: modrinth.New(),
 	}
 
+	resolver.Providers["curseforge"] = &providers.CurseForgeProvider{
+		Client: globals.CurseForgeClient,
+	}
+
 	resolver.Providers["dummy"] = &providers.DummyProvider{}
 
 	return resolver
@@ -0,0 +1,131 @@
+package smoketest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Case is the result of a single check of the smoke test
+type Case struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	// Failure is empty if the check passed
+	Failure string `json:"failure,omitempty"`
+}
+
+// Report contains the results of a smoke test
+type Report struct {
+	Name     string        `json:"name"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	Cases    []*Case       `json:"cases"`
+}
+
+// run runs check as a new case and returns true if it passed
+func (r *Report) run(name string, check func() error) bool {
+	start := time.Now()
+	err := check()
+	c := &Case{Name: name, Duration: time.Since(start)}
+	if err != nil {
+		c.Failure = err.Error()
+	}
+	r.Cases = append(r.Cases, c)
+	return err == nil
+}
+
+// Failed returns the cases that failed
+func (r *Report) Failed() []*Case {
+	failed := make([]*Case, 0)
+	for _, c := range r.Cases {
+		if c.Failure != "" {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Passed returns true if no case failed
+func (r *Report) Passed() bool {
+	return len(r.Failed()) == 0
+}
+
+// WriteJSON writes the report as JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		*Report
+		Passed bool `json:"passed"`
+	}{r, r.Passed()})
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report in the JUnit XML format most CI systems understand
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suite := junitSuite{
+		Name:      r.Name,
+		Tests:     len(r.Cases),
+		Failures:  len(r.Failed()),
+		Time:      seconds(r.Duration),
+		Timestamp: r.Started.UTC().Format("2006-01-02T15:04:05"),
+	}
+	for _, c := range r.Cases {
+		jc := junitCase{Name: c.Name, ClassName: r.Name, Time: seconds(c.Duration)}
+		if c.Failure != "" {
+			jc.Failure = &junitFailure{Message: firstLine(c.Failure), Text: c.Failure}
+		}
+		suite.Cases = append(suite.Cases, jc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func firstLine(s string) string {
+	for i, c := range s {
+		if c == '\n' {
+			return s[:i]
+		}
+	}
+	return s
+}
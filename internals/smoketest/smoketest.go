@@ -0,0 +1,221 @@
+// Package smoketest starts a server, runs console commands against it and reports the results
+package smoketest
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/logparser"
+	"github.com/pelletier/go-toml"
+)
+
+// doneRegex matches the message the server prints once it is ready
+var doneRegex = regexp.MustCompile(`^Done \(\d+[.,]?\d*s\)!`)
+
+// Step is a console command of a test script
+type Step struct {
+	// Command is sent to the server console (without leading slash)
+	Command string `toml:"command"`
+	// Expect is a regular expression that a log message has to match after the command was sent.
+	// The step does not wait for output if it is empty
+	Expect string `toml:"expect,omitempty"`
+	// Timeout is the time to wait for the expected output (like "10s"). Defaults to 10 seconds
+	Timeout string `toml:"timeout,omitempty"`
+}
+
+// Script is a list of steps, usually read from a toml file with `[[step]]` tables
+type Script struct {
+	Steps []Step `toml:"step"`
+}
+
+// ReadScript reads a script file and checks all expectations & timeouts
+func ReadScript(path string) (*Script, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	script := &Script{}
+	if err := toml.Unmarshal(raw, script); err != nil {
+		return nil, err
+	}
+	for i, step := range script.Steps {
+		if step.Command == "" {
+			return nil, fmt.Errorf("step %d has no command", i+1)
+		}
+		if _, err := regexp.Compile(step.Expect); err != nil {
+			return nil, fmt.Errorf("step %d: invalid expect regex: %w", i+1, err)
+		}
+		if _, err := step.timeout(); err != nil {
+			return nil, fmt.Errorf("step %d: invalid timeout: %w", i+1, err)
+		}
+	}
+	return script, nil
+}
+
+func (s *Step) timeout() (time.Duration, error) {
+	if s.Timeout == "" {
+		return 10 * time.Second, nil
+	}
+	return time.ParseDuration(s.Timeout)
+}
+
+// Test is a smoke test of a server
+type Test struct {
+	// Name is used as name of the test suite
+	Name string
+	// StartupTimeout is the time the server gets to start. Defaults to 5 minutes
+	StartupTimeout time.Duration
+	// StopTimeout is the time the server gets to stop. Defaults to 1 minute
+	StopTimeout time.Duration
+	// Script is run after the server started. Can be nil
+	Script *Script
+	// ErrorTags limits the error log lines that fail the test to these tags. All error lines fail the test if empty
+	ErrorTags []string
+}
+
+// Server is a started server process
+type Server struct {
+	// Stdin is connected to the server console
+	Stdin io.Writer
+	// Output is the output of the server
+	Output io.Reader
+	// Exited is closed once the process exited
+	Exited <-chan struct{}
+	// ExitCode returns the exit code after the process exited
+	ExitCode func() int
+}
+
+// Run runs the test against the server. The server is stopped in the end
+func (t *Test) Run(server *Server) *Report {
+	startupTimeout := t.StartupTimeout
+	if startupTimeout == 0 {
+		startupTimeout = 5 * time.Minute
+	}
+	stopTimeout := t.StopTimeout
+	if stopTimeout == 0 {
+		stopTimeout = time.Minute
+	}
+	errorFilter := &logparser.Filter{MinLevel: "error", Tags: t.ErrorTags}
+
+	report := &Report{Name: t.Name, Started: time.Now()}
+	// the buffer is big, lines are dropped once it is full so the server output is never blocked
+	lines := make(chan *logparser.LogLine, 10000)
+	errorLines := make([]string, 0)
+	mu := sync.Mutex{}
+	streamDone := make(chan struct{})
+	go func() {
+		defer close(streamDone)
+		logparser.Stream(server.Output, 200*time.Millisecond, func(l *logparser.LogLine) {
+			if errorFilter.Match(l) {
+				mu.Lock()
+				errorLines = append(errorLines, l.String())
+				mu.Unlock()
+			}
+			select {
+			case lines <- l:
+			default:
+			}
+		})
+		// the server output must never block, even if the stream failed
+		io.Copy(io.Discard, server.Output)
+	}()
+
+	started := report.run("startup", func() error {
+		return waitFor(lines, server.Exited, server.ExitCode, doneRegex, startupTimeout)
+	})
+
+	if started && t.Script != nil {
+		for _, step := range t.Script.Steps {
+			step := step
+			report.run("command: "+step.Command, func() error {
+				return runStep(server, lines, &step)
+			})
+		}
+	}
+
+	report.run("shutdown", func() error {
+		return stop(server, stopTimeout)
+	})
+
+	// wait for the remaining output, errors could be logged while stopping
+	select {
+	case <-streamDone:
+	case <-time.After(5 * time.Second):
+	}
+	report.run("no errors", func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(errorLines) != 0 {
+			return fmt.Errorf("%d error log lines:\n%s", len(errorLines), strings.Join(errorLines, "\n"))
+		}
+		return nil
+	})
+
+	report.Duration = time.Since(report.Started)
+	return report
+}
+
+// waitFor waits for a log message matching regex
+func waitFor(lines <-chan *logparser.LogLine, exited <-chan struct{}, exitCode func() int, regex *regexp.Regexp, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case l, ok := <-lines:
+			if ok && regex.MatchString(l.Message) {
+				return nil
+			}
+		case <-exited:
+			return fmt.Errorf("server exited with code %d before a line matched %q", exitCode(), regex)
+		case <-timer.C:
+			return fmt.Errorf("no line matched %q within %s", regex, timeout)
+		}
+	}
+}
+
+func runStep(server *Server, lines <-chan *logparser.LogLine, step *Step) error {
+	timeout, _ := step.timeout()
+	// skip output that was printed before the command was sent
+	for drained := false; !drained; {
+		select {
+		case <-lines:
+		default:
+			drained = true
+		}
+	}
+	if _, err := fmt.Fprintln(server.Stdin, strings.TrimPrefix(step.Command, "/")); err != nil {
+		return err
+	}
+	if step.Expect == "" {
+		return nil
+	}
+	return waitFor(lines, server.Exited, server.ExitCode, regexp.MustCompile(step.Expect), timeout)
+}
+
+// stop sends the stop command and waits for the server to exit
+func stop(server *Server, timeout time.Duration) error {
+	select {
+	case <-server.Exited:
+		return fmt.Errorf("server exited unexpectedly with code %d", server.ExitCode())
+	default:
+	}
+
+	if _, err := fmt.Fprintln(server.Stdin, "stop"); err != nil {
+		return err
+	}
+	select {
+	case <-server.Exited:
+	case <-time.After(timeout):
+		return fmt.Errorf("server did not stop within %s", timeout)
+	}
+	// minecraft exits with 130 after the stop command
+	if code := server.ExitCode(); code != 0 && code != 130 {
+		return fmt.Errorf("server exited with code %d", code)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package smoketest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer behaves like a Minecraft server console
+func fakeServer(t *testing.T, startup []string) *Server {
+	t.Helper()
+	stdinR, stdinW := io.Pipe()
+	outR, outW := io.Pipe()
+	exited := make(chan struct{})
+	code := 0
+
+	go func() {
+		defer close(exited)
+		defer outW.Close()
+		for _, line := range startup {
+			fmt.Fprintln(outW, line)
+		}
+		scanner := bufio.NewScanner(stdinR)
+		for scanner.Scan() {
+			switch scanner.Text() {
+			case "list":
+				fmt.Fprintln(outW, "[12:00:02] [Server thread/INFO]: There are 0 of a max of 20 players online:")
+			case "stop":
+				fmt.Fprintln(outW, "[12:00:03] [Server thread/INFO]: Stopping server")
+				code = 130
+				return
+			}
+		}
+	}()
+
+	return &Server{Stdin: stdinW, Output: outR, Exited: exited, ExitCode: func() int { return code }}
+}
+
+func TestTest_Run(t *testing.T) {
+	server := fakeServer(t, []string{
+		"[12:00:00] [main/INFO]: Loading for game Minecraft 1.18.2",
+		`[12:00:01] [Server thread/INFO]: Done (1.234s)! For help, type "help"`,
+	})
+	test := &Test{
+		Name:           "test-pack",
+		StartupTimeout: time.Second,
+		Script: &Script{Steps: []Step{
+			{Command: "/list", Expect: `There are \d+ of a max`},
+		}},
+	}
+
+	report := test.Run(server)
+	if !report.Passed() {
+		t.Fatalf("expected report to pass: %+v", report.Failed()[0])
+	}
+	names := []string{}
+	for _, c := range report.Cases {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "startup,command: /list,shutdown,no errors" {
+		t.Fatalf("unexpected cases %s", got)
+	}
+}
+
+func TestTest_RunFailures(t *testing.T) {
+	server := fakeServer(t, []string{
+		"[12:00:00] [main/ERROR] (BadMod) Could not load config",
+		"[12:00:00] [main/ERROR] (OtherMod) Ignored",
+		`[12:00:01] [Server thread/INFO]: Done (1.234s)! For help, type "help"`,
+	})
+	test := &Test{
+		Name:      "test-pack",
+		ErrorTags: []string{"BadMod"},
+		Script: &Script{Steps: []Step{
+			{Command: "list", Expect: "nothing like this", Timeout: "100ms"},
+		}},
+	}
+
+	report := test.Run(server)
+	failed := report.Failed()
+	if len(failed) != 2 || failed[0].Name != "command: list" || failed[1].Name != "no errors" {
+		t.Fatalf("unexpected failures %+v", failed)
+	}
+	if !strings.Contains(failed[1].Failure, "Could not load config") || strings.Contains(failed[1].Failure, "Ignored") {
+		t.Fatalf("unexpected error lines: %s", failed[1].Failure)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := report.WriteJUnit(buf); err != nil {
+		t.Fatal(err)
+	}
+	suites := junitSuites{}
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+	if suite := suites.Suites[0]; suite.Tests != 4 || suite.Failures != 2 || suite.Cases[1].Failure == nil {
+		t.Fatalf("unexpected junit report:\n%s", buf)
+	}
+}
+
+func TestTest_RunServerExits(t *testing.T) {
+	exited := make(chan struct{})
+	close(exited)
+	server := &Server{Stdin: io.Discard, Output: strings.NewReader(""), Exited: exited, ExitCode: func() int { return 1 }}
+
+	report := (&Test{Name: "crash"}).Run(server)
+	if report.Cases[0].Failure == "" || !strings.Contains(report.Cases[0].Failure, "exited with code 1") {
+		t.Fatalf("unexpected startup result %+v", report.Cases[0])
+	}
+}
+
+func TestReadScript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.toml")
+	os.WriteFile(path, []byte("[[step]]\ncommand = \"list\"\nexpect = \"(\"\n"), 0644)
+	if _, err := ReadScript(path); err == nil {
+		t.Fatal("expected invalid regex to fail")
+	}
+
+	os.WriteFile(path, []byte("[[step]]\ncommand = \"list\"\ntimeout = \"5s\"\n\n[[step]]\ncommand = \"seed\"\n"), 0644)
+	script, err := ReadScript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(script.Steps) != 2 || script.Steps[1].Command != "seed" {
+		t.Fatalf("unexpected script %+v", script)
+	}
+}
@@ -0,0 +1,117 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Garbage collector presets that can be used in `[launch]`
+const (
+	// GCG1 uses the G1 garbage collector tuned for clients. This is the default
+	GCG1 = "g1"
+	// GCZGC uses the Z garbage collector. Requires Java 17 or newer
+	GCZGC = "zgc"
+	// GCAikar uses Aikar's flags which are tuned for servers. See https://mcflags.emc.gs
+	GCAikar = "aikar"
+	// GCNone sets no garbage collector flags at all
+	GCNone = "none"
+)
+
+// ErrInvalidMemory is returned for memory values that can not be parsed
+var ErrInvalidMemory = errors.New("invalid memory value. use a number with an optional M or G suffix like 4G or 512M")
+
+// Launch contains options that are used when launching a modpack
+type Launch struct {
+	// JVMArgs are additional arguments that are passed to java (eg. "-Dfml.readTimeout=120")
+	JVMArgs []string `toml:"jvmArgs,omitempty" json:"jvmArgs,omitempty"`
+	// GameArgs are additional arguments that are passed to Minecraft (eg. "--width 1280")
+	GameArgs []string `toml:"gameArgs,omitempty" json:"gameArgs,omitempty"`
+	// Env are additional environment variables
+	Env map[string]string `toml:"env,omitempty" json:"env,omitempty"`
+	// MinMemory is the initial heap size like "2G" or "512M" (-Xms)
+	MinMemory string `toml:"minMemory,omitempty" json:"minMemory,omitempty"`
+	// MaxMemory is the maximum heap size like "6G" (-Xmx).
+	// It is calculated from the mod count and system memory if omitted
+	MaxMemory string `toml:"maxMemory,omitempty" json:"maxMemory,omitempty"`
+	// GC is the garbage collector preset. Can be `g1` (default), `zgc`, `aikar` or `none`
+	GC string `toml:"gc,omitempty" json:"gc,omitempty"`
+}
+
+// Merge returns a new `Launch` with all values of `override` applied on top of `l`.
+// Arguments are appended, environment variables and all other values are replaced.
+// Both `l` and `override` can be nil
+func (l *Launch) Merge(override *Launch) *Launch {
+	merged := &Launch{Env: make(map[string]string)}
+	for _, src := range []*Launch{l, override} {
+		if src == nil {
+			continue
+		}
+		merged.JVMArgs = append(merged.JVMArgs, src.JVMArgs...)
+		merged.GameArgs = append(merged.GameArgs, src.GameArgs...)
+		for key, value := range src.Env {
+			merged.Env[key] = value
+		}
+		if src.MinMemory != "" {
+			merged.MinMemory = src.MinMemory
+		}
+		if src.MaxMemory != "" {
+			merged.MaxMemory = src.MaxMemory
+		}
+		if src.GC != "" {
+			merged.GC = src.GC
+		}
+	}
+	return merged
+}
+
+// ParseMemory parses memory values like "4G", "512M" or "2048" (MiB) and returns the amount in MiB.
+// An empty string returns 0
+func ParseMemory(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	factor := 1
+	switch s[len(s)-1] {
+	case 'g', 'G':
+		factor = 1024
+		s = s[:len(s)-1]
+	case 'm', 'M':
+		s = s[:len(s)-1]
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, ErrInvalidMemory
+	}
+	return n * factor, nil
+}
+
+func validateLaunch(l *Launch) Problems {
+	problems := Problems{}
+
+	for path, value := range map[string]string{"launch.minMemory": l.MinMemory, "launch.maxMemory": l.MaxMemory} {
+		if _, err := ParseMemory(value); err != nil {
+			problems = append(problems, ValidationError{
+				message: fmt.Sprintf("%s is not a valid memory value", value),
+				Path:    path,
+				Level:   ErrorLevelFatal,
+			})
+		}
+	}
+
+	switch l.GC {
+	case "", GCG1, GCZGC, GCAikar, GCNone:
+	default:
+		problems = append(problems, ValidationError{
+			message: fmt.Sprintf("unknown gc preset %s. must be g1, zgc, aikar or none", l.GC),
+			Path:    "launch.gc",
+			Level:   ErrorLevelFatal,
+		})
+	}
+
+	return problems
+}
@@ -0,0 +1,64 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "4G", want: 4096},
+		{in: "512m", want: 512},
+		{in: "2048", want: 2048},
+		{in: "1.5G", wantErr: true},
+		{in: "-1G", wantErr: true},
+		{in: "G", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseMemory(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLaunch_Merge(t *testing.T) {
+	base := &Launch{
+		JVMArgs:   []string{"-Da=1"},
+		Env:       map[string]string{"A": "1", "B": "1"},
+		MaxMemory: "4G",
+		GC:        GCG1,
+	}
+	override := &Launch{
+		JVMArgs:  []string{"-Db=2"},
+		GameArgs: []string{"--width", "1280"},
+		Env:      map[string]string{"B": "2"},
+		GC:       GCAikar,
+	}
+
+	want := &Launch{
+		JVMArgs:   []string{"-Da=1", "-Db=2"},
+		GameArgs:  []string{"--width", "1280"},
+		Env:       map[string]string{"A": "1", "B": "2"},
+		MaxMemory: "4G",
+		GC:        GCAikar,
+	}
+	if got := base.Merge(override); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	var empty *Launch
+	if got := empty.Merge(nil); got == nil || len(got.JVMArgs) != 0 {
+		t.Fatalf("merging nil launch options should return empty options, got %+v", got)
+	}
+}
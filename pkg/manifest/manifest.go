@@ -101,6 +101,10 @@ type Manifest struct {
 		// They should never be installed for published packages
 		Dependencies `toml:"dependencies,omitempty" json:"dependencies,omitempty"`
 	} `toml:"dev" json:"dev"`
+	// Launch contains options used when launching this modpack (like jvm arguments or memory)
+	Launch *Launch `toml:"launch,omitempty" json:"launch,omitempty"`
+	// Server contains options for dedicated servers (like the `server.properties`)
+	Server *Server `toml:"server,omitempty" json:"server,omitempty"`
 }
 
 // Dependencies are the dependencies of a mod or modpack as a map
@@ -236,6 +240,8 @@ func NewInstanceLike(from *Manifest) *Manifest {
 	manifest.Package.BasedOn = from.Package.Name
 
 	manifest.Requirements = from.Requirements
+	manifest.Launch = from.Launch
+	manifest.Server = from.Server
 
 	// set this instance as first dependency
 	manifest.Dependencies[from.Package.Name] = from.Package.Version
@@ -0,0 +1,50 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Server contains options for dedicated servers
+type Server struct {
+	// Properties are applied to the `server.properties` of the server when it is launched.
+	// Dotted keys like `rcon.port` can also be written as nested tables
+	Properties map[string]interface{} `toml:"properties,omitempty" json:"properties,omitempty"`
+}
+
+// FlatProperties returns the server properties as strings. Nested tables are joined with a "."
+func (s *Server) FlatProperties() map[string]string {
+	flat := make(map[string]string)
+	if s == nil {
+		return flat
+	}
+	flattenProperties(flat, "", s.Properties)
+	return flat
+}
+
+// PropertyKeys returns the keys of `FlatProperties` sorted alphabetically
+func (s *Server) PropertyKeys() []string {
+	flat := s.FlatProperties()
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func flattenProperties(flat map[string]string, prefix string, props map[string]interface{}) {
+	for key, value := range props {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenProperties(flat, key, v)
+		case nil:
+			flat[key] = ""
+		default:
+			flat[key] = fmt.Sprint(v)
+		}
+	}
+}
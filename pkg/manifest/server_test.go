@@ -0,0 +1,39 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+)
+
+func TestServer_FlatProperties(t *testing.T) {
+	raw := `
+[server.properties]
+motd = "Welcome = have fun"
+max-players = 10
+pvp = false
+"spawn-protection" = 0
+rcon.port = 25580
+`
+	m := Manifest{}
+	if err := toml.Unmarshal([]byte(raw), &m); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"motd":             "Welcome = have fun",
+		"max-players":      "10",
+		"pvp":              "false",
+		"spawn-protection": "0",
+		"rcon.port":        "25580",
+	}
+	if got := m.Server.FlatProperties(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	var empty *Server
+	if len(empty.FlatProperties()) != 0 {
+		t.Fatal("nil server should have no properties")
+	}
+}
@@ -76,9 +76,16 @@ var (
 
 // helper regexes
 var (
-	validName = regexp.MustCompile(`^[a-z0-9-_]+$`)
+	validName        = regexp.MustCompile(`^[a-z0-9-_]+$`)
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9-_]+`)
 )
 
+// PackageName turns any name (like "My Pack 2.0") into a valid package name ("my-pack-2-0")
+func PackageName(name string) string {
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-_")
+}
+
 type Problems []ValidationError
 
 // Fatal returns the first fatal error in the list. If there are no fatal errors, it returns nil.
@@ -190,6 +197,10 @@ func (m *Manifest) Validate() Problems {
 		problems = append(problems, ErrNoLoaderRequirement)
 	}
 
+	if m.Launch != nil {
+		problems = append(problems, validateLaunch(m.Launch)...)
+	}
+
 	// TODO: validate other fields (dependencies, dev stuff)
 	return problems
 }